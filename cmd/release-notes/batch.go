@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/go-kit/kit/log/level"
+	"gopkg.in/yaml.v2"
+)
+
+// batchRange is one release of a -ranges-file
+type batchRange struct {
+	releaseRange `yaml:",inline"`
+
+	// Output is the file the notes of the release are written to
+	Output string `yaml:"output"`
+}
+
+// loadRangesFile reads the list of releases of a -ranges-file. Since JSON is
+// a subset of YAML, both formats are supported.
+func loadRangesFile(path string) ([]batchRange, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read ranges file: %v", err)
+	}
+
+	ranges := []batchRange{}
+	if err := yaml.Unmarshal(content, &ranges); err != nil {
+		return nil, fmt.Errorf("unable to parse ranges file %s: %v", path, err)
+	}
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("the ranges file %s lists no releases", path)
+	}
+	for i, r := range ranges {
+		if r.StartSHA == "" || r.EndSHA == "" || r.Output == "" {
+			return nil, fmt.Errorf("release %d of the ranges file %s requires start_sha, end_sha and output", i+1, path)
+		}
+	}
+	return ranges, nil
+}
+
+// runBatch generates the notes of every release of the -ranges-file in
+// sequence. All releases share one GitHub client, so that -host-concurrency
+// applies to the whole run. The notes of every release are tagged with its
+// release_version, or -release-version if it has none, so that releases which
+// are written to the same JSON output can still be told apart. A failed
+// release does not stop the remaining ones, the failures are reported at the
+// end.
+func (o *options) runBatch(ranges []batchRange) error {
+	client, err := o.newGithubClient(context.Background())
+	if err != nil {
+		level.Error(o.logger).Log("msg", "error creating the GitHub client", "err", err)
+		return err
+	}
+	o.githubClient = client
+
+	failed := 0
+	results := make([]error, len(ranges))
+	for i, r := range ranges {
+		ro := *o
+		ro.startSHA = r.StartSHA
+		ro.endSHA = r.EndSHA
+		ro.output = r.Output
+		if r.ReleaseVersion != "" {
+			ro.releaseVersion = r.ReleaseVersion
+		}
+
+		level.Info(o.logger).Log("msg", "generating the notes of a release", "release", i+1, "of", len(ranges), "version", ro.releaseVersion)
+		if err := ro.validateOutputTemplate(); err != nil {
+			results[i] = err
+		} else {
+			results[i] = ro.generate()
+		}
+		if results[i] != nil {
+			failed++
+		}
+	}
+
+	for i, r := range ranges {
+		if results[i] != nil {
+			level.Error(o.logger).Log("msg", "release failed", "version", r.ReleaseVersion, "start", r.StartSHA, "end", r.EndSHA, "err", results[i])
+		} else {
+			level.Info(o.logger).Log("msg", "release succeeded", "version", r.ReleaseVersion, "output", r.Output)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d releases failed", failed, len(ranges))
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/require"
+	"k8s.io/release/pkg/notes"
+)
+
+func TestLoadRangesFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ranges-file-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, tc := range []struct {
+		name    string
+		content string
+		ranges  []batchRange
+		err     bool
+	}{
+		{
+			name: "yaml",
+			content: "- release_version: v1.15.0\n  start_sha: a\n  end_sha: b\n  output: v1.15.0.md\n" +
+				"- start_sha: b\n  end_sha: c\n  output: v1.16.0.md\n",
+			ranges: []batchRange{
+				{releaseRange: releaseRange{ReleaseVersion: "v1.15.0", StartSHA: "a", EndSHA: "b"}, Output: "v1.15.0.md"},
+				{releaseRange: releaseRange{StartSHA: "b", EndSHA: "c"}, Output: "v1.16.0.md"},
+			},
+		},
+		{
+			name:    "json",
+			content: `[{"start_sha": "a", "end_sha": "b", "output": "notes.md"}]`,
+			ranges:  []batchRange{{releaseRange: releaseRange{StartSHA: "a", EndSHA: "b"}, Output: "notes.md"}},
+		},
+		{name: "empty", content: "[]", err: true},
+		{name: "missing output", content: `[{"start_sha": "a", "end_sha": "b"}]`, err: true},
+		{name: "invalid", content: "start_sha: a", err: true},
+	} {
+		path := filepath.Join(dir, tc.name)
+		require.NoError(t, ioutil.WriteFile(path, []byte(tc.content), 0644))
+
+		ranges, err := loadRangesFile(path)
+		if tc.err {
+			require.Error(t, err, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.ranges, ranges, tc.name)
+	}
+}
+
+func TestRunBatch(t *testing.T) {
+	client, server := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "/compare/"):
+			fmt.Fprint(w, `{"status": "behind"}`)
+		case strings.HasSuffix(r.URL.Path, "/git/commits/missing"):
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+		case strings.Contains(r.URL.Path, "/git/commits/"):
+			fmt.Fprint(w, `{"committer": {"date": "2019-01-01T00:00:00Z"}}`)
+		case strings.HasSuffix(r.URL.Path, "/commits"):
+			fmt.Fprint(w, `[]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "ranges-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	o := &options{
+		githubOrg:    "kubernetes",
+		githubRepo:   "kubernetes",
+		branch:       "master",
+		format:       "markdown",
+		githubClient: client,
+		logger:       log.NewNopLogger(),
+	}
+	err = o.runBatch([]batchRange{
+		{releaseRange: releaseRange{StartSHA: "missing", EndSHA: "a"}, Output: filepath.Join(dir, "failed.md")},
+		{releaseRange: releaseRange{ReleaseVersion: "v1.16.0", StartSHA: "a", EndSHA: "b"}, Output: filepath.Join(dir, "{version}.md")},
+	})
+	require.EqualError(t, err, "1 of 2 releases failed")
+
+	// the release after the failed one is still generated
+	_, err = os.Stat(filepath.Join(dir, "v1.16.0.md"))
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, "failed.md"))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestRunBatchReleaseVersion(t *testing.T) {
+	client, server := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/kubernetes/kubernetes/git/commits/a":
+			fmt.Fprint(w, `{"committer": {"date": "2019-01-01T00:00:00Z"}}`)
+		case "/repos/kubernetes/kubernetes/git/commits/b":
+			fmt.Fprint(w, `{"committer": {"date": "2019-01-02T00:00:00Z"}}`)
+		case "/repos/kubernetes/kubernetes/git/commits/c":
+			fmt.Fprint(w, `{"committer": {"date": "2019-01-03T00:00:00Z"}}`)
+		case "/repos/kubernetes/kubernetes/commits":
+			// every release has one commit
+			if r.URL.Query().Get("until") == "2019-01-02T00:00:00Z" {
+				fmt.Fprint(w, `[{"sha": "aaa", "commit": {"message": "Fix the kubelet"}}]`)
+			} else {
+				fmt.Fprint(w, `[{"sha": "bbb", "commit": {"message": "Fix the scheduler"}}]`)
+			}
+		case "/repos/kubernetes/kubernetes/commits/aaa/pulls":
+			fmt.Fprint(w, `[{"number": 1}]`)
+		case "/repos/kubernetes/kubernetes/commits/bbb/pulls":
+			fmt.Fprint(w, `[{"number": 2}]`)
+		case "/repos/kubernetes/kubernetes/pulls/1":
+			fmt.Fprint(w, "{\"number\": 1, \"merged\": true, \"user\": {\"login\": \"someone\"}, \"body\": \"```release-note\\nFixed the kubelet\\n```\"}")
+		case "/repos/kubernetes/kubernetes/pulls/2":
+			fmt.Fprint(w, "{\"number\": 2, \"merged\": true, \"user\": {\"login\": \"someone\"}, \"body\": \"```release-note\\nFixed the scheduler\\n```\"}")
+		default:
+			if strings.Contains(r.URL.Path, "/compare/") {
+				fmt.Fprint(w, `{"status": "identical"}`)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "ranges-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	output := filepath.Join(dir, "notes.json")
+	o := &options{
+		githubOrg:      "kubernetes",
+		githubRepo:     "kubernetes",
+		branch:         "master",
+		format:         "json",
+		releaseVersion: "v1.20.x",
+		githubClient:   client,
+		logger:         log.NewNopLogger(),
+	}
+	require.NoError(t, o.runBatch([]batchRange{
+		{releaseRange: releaseRange{ReleaseVersion: "v1.20.2", StartSHA: "a", EndSHA: "b"}, Output: output},
+		{releaseRange: releaseRange{StartSHA: "b", EndSHA: "c"}, Output: output},
+	}))
+
+	content, err := ioutil.ReadFile(output)
+	require.NoError(t, err)
+	releaseNotes := notes.ReleaseNoteList{}
+	require.NoError(t, json.Unmarshal(content, &releaseNotes))
+	require.Len(t, releaseNotes, 2)
+	require.Equal(t, "v1.20.2", releaseNotes[1].ReleaseVersion)
+	require.Equal(t, "v1.20.x", releaseNotes[2].ReleaseVersion)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/go-kit/kit/log/level"
+)
+
+// fileChecksum returns the hex encoded SHA256 digest of the whole file
+func fileChecksum(f *os.File) (string, error) {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeChecksumFile writes the digest next to the file at path in the format
+// of sha256sum, so that it can be verified with `sha256sum -c`.
+func writeChecksumFile(path, digest string) error {
+	line := fmt.Sprintf("%s  %s\n", digest, filepath.Base(path))
+	return ioutil.WriteFile(path+".sha256", []byte(line), 0644)
+}
+
+// writePartChecksums writes the checksum file of every part of a split output
+func (o *options) writePartChecksums(parts []string) error {
+	for _, part := range parts {
+		f, err := os.Open(part)
+		if err != nil {
+			return err
+		}
+		digest, err := fileChecksum(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		level.Info(o.logger).Log("msg", "computed checksum of the release notes", "path", part, "sha256", digest)
+
+		if err := writeChecksumFile(part, digest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
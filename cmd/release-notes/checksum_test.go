@@ -0,0 +1,74 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/release/pkg/notes"
+)
+
+// sha256sumLine matches a line of the output of sha256sum, which is the input
+// of `sha256sum -c`: the hex digest, two spaces and the file name
+var sha256sumLine = regexp.MustCompile(`^([0-9a-f]{64})  (\S+)\n$`)
+
+func TestChecksum(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checksum-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	releaseNotes := notes.ReleaseNoteList{
+		1: &notes.ReleaseNote{PrNumber: 1, Text: "First note", Markdown: "First note"},
+	}
+
+	// with -output, the digest is written to a sibling .sha256 file
+	o := &options{
+		format:   "markdown",
+		output:   filepath.Join(dir, "notes.md"),
+		checksum: true,
+		logger:   log.NewNopLogger(),
+	}
+	require.NoError(t, o.WriteReleaseNotes(releaseNotes))
+
+	content, err := ioutil.ReadFile(o.output)
+	require.NoError(t, err)
+	digest := sha256.Sum256(content)
+	checksum, err := ioutil.ReadFile(o.output + ".sha256")
+	require.NoError(t, err)
+	match := sha256sumLine.FindStringSubmatch(string(checksum))
+	require.NotNil(t, match, "%q is not in the format of sha256sum", checksum)
+	require.Equal(t, hex.EncodeToString(digest[:]), match[1])
+	require.Equal(t, "notes.md", match[2])
+
+	if sha256sum, err := exec.LookPath("sha256sum"); err == nil {
+		cmd := exec.Command(sha256sum, "-c", "notes.md.sha256")
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	// without -output, the digest of the temporary file is printed to stderr
+	stderr, err := ioutil.TempFile(dir, "stderr-")
+	require.NoError(t, err)
+	defer stderr.Close()
+	defer func(f *os.File) { os.Stderr = f }(os.Stderr)
+	os.Stderr = stderr
+
+	o = &options{format: "markdown", tempDir: dir, checksum: true, logger: log.NewNopLogger()}
+	require.NoError(t, o.WriteReleaseNotes(releaseNotes))
+
+	printed, err := ioutil.ReadFile(stderr.Name())
+	require.NoError(t, err)
+	match = sha256sumLine.FindStringSubmatch(string(printed))
+	require.NotNil(t, match, "%q is not in the format of sha256sum", printed)
+	require.Equal(t, hex.EncodeToString(digest[:]), match[1])
+	require.Equal(t, dir, filepath.Dir(match[2]))
+}
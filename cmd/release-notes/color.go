@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/go-kit/kit/log/term"
+)
+
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+)
+
+// useColor decides whether output written to f is colorized. This is the
+// single place which implements the -color policy, so that the logs and the
+// preview always agree.
+func useColor(mode string, f *os.File) bool {
+	switch mode {
+	case colorAlways:
+		return true
+	case colorNever:
+		return false
+	default:
+		if _, ok := os.LookupEnv("NO_COLOR"); ok {
+			return false
+		}
+		return isTerminal(f)
+	}
+}
+
+// newColorLogger returns a logfmt logger writing to w which colors the lines
+// by their level
+func newColorLogger(w io.Writer) log.Logger {
+	return term.NewColorLogger(log.NewSyncWriter(w), log.NewLogfmtLogger, func(keyvals ...interface{}) term.FgBgColor {
+		for i := 0; i+1 < len(keyvals); i += 2 {
+			if keyvals[i] != level.Key() {
+				continue
+			}
+			switch fmt.Sprint(keyvals[i+1]) {
+			case "error":
+				return term.FgBgColor{Fg: term.Red}
+			case "warn":
+				return term.FgBgColor{Fg: term.Yellow}
+			case "debug":
+				return term.FgBgColor{Fg: term.Gray}
+			}
+		}
+		return term.FgBgColor{}
+	})
+}
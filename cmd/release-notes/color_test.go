@@ -0,0 +1,49 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUseColor(t *testing.T) {
+	// /dev/null is a character device like a terminal, a regular file is not
+	terminal, err := os.Open(os.DevNull)
+	require.NoError(t, err)
+	defer terminal.Close()
+
+	file, err := ioutil.TempFile("", "color-")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	if value, ok := os.LookupEnv("NO_COLOR"); ok {
+		defer os.Setenv("NO_COLOR", value)
+	} else {
+		defer os.Unsetenv("NO_COLOR")
+	}
+
+	for _, tc := range []struct {
+		mode     string
+		noColor  bool
+		f        *os.File
+		expected bool
+	}{
+		{mode: colorAuto, f: terminal, expected: true},
+		{mode: colorAuto, f: file, expected: false},
+		{mode: colorAuto, noColor: true, f: terminal, expected: false},
+		{mode: colorAlways, f: file, expected: true},
+		{mode: colorAlways, noColor: true, f: file, expected: true},
+		{mode: colorNever, f: terminal, expected: false},
+		{mode: colorNever, noColor: true, f: terminal, expected: false},
+	} {
+		if tc.noColor {
+			require.NoError(t, os.Setenv("NO_COLOR", "1"))
+		} else {
+			require.NoError(t, os.Unsetenv("NO_COLOR"))
+		}
+		require.Equal(t, tc.expected, useColor(tc.mode, tc.f), "%s with NO_COLOR=%v on %s", tc.mode, tc.noColor, tc.f.Name())
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/google/go-github/v27/github"
+	"gopkg.in/yaml.v2"
+
+	"k8s.io/release/pkg/notes"
+)
+
+// coveragePath returns the path of the file which records the commit range
+// covered by the JSON output at path. The file uses the format of -range-file.
+func coveragePath(path string) string {
+	return path + ".range.yaml"
+}
+
+// readCoverage reads the covered commit range of the JSON output at path. A
+// nil range is returned if none was recorded.
+func readCoverage(path string) (*releaseRange, error) {
+	content, err := ioutil.ReadFile(coveragePath(path))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the covered range: %v", err)
+	}
+
+	r := &releaseRange{}
+	if err := yaml.Unmarshal(content, r); err != nil {
+		return nil, fmt.Errorf("unable to parse the covered range %s: %v", coveragePath(path), err)
+	}
+	return r, nil
+}
+
+// writeCoverage records the covered commit range of the JSON output at path
+func writeCoverage(path string, r releaseRange) error {
+	content, err := yaml.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(coveragePath(path), content, 0644)
+}
+
+// checkCoverageGap compares the range recorded for the existing JSON output
+// with the start of the current range. Gaps are an error, unless -fill-gaps is
+// set, in which case the notes of the gap are returned. The start of the
+// covered range is remembered, so that the recorded range stays contiguous.
+func (o *options) checkCoverageGap(client *github.Client, opts ...notes.GithubApiOption) (notes.ReleaseNoteList, error) {
+	o.coveredStart = o.startSHA
+	if o.format != "json" || o.output == "" {
+		return nil, nil
+	}
+
+	covered, err := readCoverage(o.outputPath())
+	if err != nil || covered == nil {
+		return nil, err
+	}
+
+	gap, err := notes.RangeGap(client, covered.EndSHA, o.startSHA, opts...)
+	if err != nil {
+		return nil, err
+	}
+	o.coveredStart = covered.StartSHA
+	if !gap {
+		return nil, nil
+	}
+
+	if !o.fillGaps {
+		return nil, fmt.Errorf(
+			"the existing notes end at %s, which leaves a gap before the start %s. Use -fill-gaps to fetch the notes of the gap",
+			covered.EndSHA, o.startSHA,
+		)
+	}
+
+	level.Info(o.logger).Log("msg", "fetching the notes of the gap", "start", covered.EndSHA, "end", o.startSHA)
+	return notes.ListReleaseNotes(client, o.logger, o.branch, covered.EndSHA, o.startSHA, o.requiredAuthor, o.releaseVersion, opts...)
+}
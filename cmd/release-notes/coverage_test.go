@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCoverage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "coverage-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	output := filepath.Join(dir, "notes.json")
+
+	covered, err := readCoverage(output)
+	require.NoError(t, err)
+	require.Nil(t, covered)
+
+	require.NoError(t, writeCoverage(output, releaseRange{StartSHA: "first", EndSHA: "end"}))
+	covered, err = readCoverage(output)
+	require.NoError(t, err)
+	require.Equal(t, &releaseRange{StartSHA: "first", EndSHA: "end"}, covered)
+
+	require.NoError(t, ioutil.WriteFile(coveragePath(output), []byte("start_sha: [\n"), 0644))
+	_, err = readCoverage(output)
+	require.Error(t, err)
+}
+
+func TestCheckCoverageGap(t *testing.T) {
+	listed := false
+	client, server := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "/compare/"):
+			statuses := map[string]string{
+				"end...end":   "identical",
+				"end...newer": "ahead",
+			}
+			fmt.Fprintf(w, `{"status": %q}`, statuses[path.Base(r.URL.Path)])
+		case strings.Contains(r.URL.Path, "/git/commits/"):
+			fmt.Fprintf(w, `{"sha": %q, "committer": {"date": "2019-01-01T00:00:00Z"}}`, path.Base(r.URL.Path))
+		case strings.HasSuffix(r.URL.Path, "/commits"):
+			listed = true
+			fmt.Fprint(w, `[]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	for _, tc := range []struct {
+		name          string
+		recorded      bool
+		start         string
+		fillGaps      bool
+		err           bool
+		listed        bool
+		expectedStart string
+	}{
+		{name: "missing range file", start: "newer", expectedStart: "newer"},
+		{name: "contiguous range", recorded: true, start: "end", expectedStart: "first"},
+		{name: "gap", recorded: true, start: "newer", err: true},
+		{name: "filled gap", recorded: true, start: "newer", fillGaps: true, listed: true, expectedStart: "first"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "coverage-")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+
+			o := &options{
+				format:   "json",
+				output:   filepath.Join(dir, "notes.json"),
+				startSHA: tc.start,
+				fillGaps: tc.fillGaps,
+				logger:   log.NewNopLogger(),
+			}
+			if tc.recorded {
+				require.NoError(t, writeCoverage(o.output, releaseRange{StartSHA: "first", EndSHA: "end"}))
+			}
+
+			listed = false
+			_, err = o.checkCoverageGap(client)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.listed, listed)
+			require.Equal(t, tc.expectedStart, o.coveredStart)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// machineDateLayout is the default layout of dates which are read by
+	// tools, like in the logs
+	machineDateLayout = time.RFC3339
+
+	// previewDateLayout is the default layout of dates in the preview
+	previewDateLayout = "Jan 2, 2006"
+
+	// filenameDateLayout is the default layout of the {date} placeholder of
+	// the -output path
+	filenameDateLayout = "2006-01-02"
+)
+
+// dateFormatProbe is the time which -date-format layouts are checked with. All
+// of its elements differ, so that a layout which contains any element of the
+// reference time renders differently than the layout itself.
+var dateFormatProbe = time.Date(2019, time.November, 23, 21, 34, 56, 0, time.UTC)
+
+// validateDateFormat rejects layouts which do not contain any element of the
+// reference time, like "YYYY-MM-DD", or which render line breaks
+func validateDateFormat(layout string) error {
+	formatted := dateFormatProbe.Format(layout)
+	if formatted == layout {
+		return fmt.Errorf("-date-format %q does not contain any element of the reference time Mon Jan 2 15:04:05 MST 2006", layout)
+	}
+	if strings.ContainsAny(formatted, "\r\n") {
+		return fmt.Errorf("-date-format %q must not contain line breaks", layout)
+	}
+	return nil
+}
+
+// dateLayout returns the -date-format layout, or the provided default layout
+// of the context if it is not set
+func (o *options) dateLayout(defaultLayout string) string {
+	if o.dateFormat != "" {
+		return o.dateFormat
+	}
+	return defaultLayout
+}
+
+// formatDate renders the time with the layout of dateLayout
+func (o *options) formatDate(t time.Time, defaultLayout string) string {
+	return t.Format(o.dateLayout(defaultLayout))
+}
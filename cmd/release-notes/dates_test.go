@@ -0,0 +1,31 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDateFormat(t *testing.T) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02", "Jan 2, 2006", "Monday", "15:04"} {
+		require.NoError(t, validateDateFormat(layout), layout)
+	}
+	for _, layout := range []string{"YYYY-MM-DD", "date", "2006-01-02\n15:04"} {
+		require.Error(t, validateDateFormat(layout), layout)
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	date := time.Date(2019, time.January, 7, 12, 30, 0, 0, time.UTC)
+
+	o := &options{}
+	require.Equal(t, "2019-01-07T12:30:00Z", o.formatDate(date, machineDateLayout))
+	require.Equal(t, "Jan 7, 2019", o.formatDate(date, previewDateLayout))
+	require.Equal(t, "2019-01-07", o.formatDate(date, filenameDateLayout))
+
+	// the -date-format applies to every context
+	o.dateFormat = "02.01.2006 15:04"
+	require.Equal(t, "07.01.2019 12:30", o.formatDate(date, machineDateLayout))
+	require.Equal(t, "07.01.2019 12:30", o.formatDate(date, previewDateLayout))
+}
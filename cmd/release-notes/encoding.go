@@ -0,0 +1,79 @@
+package main
+
+import (
+	"os"
+	"unicode/utf8"
+
+	"github.com/go-kit/kit/log/level"
+)
+
+const (
+	// encodingUTF8 leaves the rendered output as it is
+	encodingUTF8 = "utf-8"
+
+	// encodingLatin1 transcodes the rendered output to ISO 8859-1, for legacy
+	// consumers which cannot read UTF-8
+	encodingLatin1 = "iso-8859-1"
+)
+
+// utf8BOM is the byte order mark which precedes the output with -output-bom
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// latin1Replacement replaces the runes which ISO 8859-1 cannot represent
+const latin1Replacement = '?'
+
+// encodeLatin1 transcodes UTF-8 to ISO 8859-1 and returns the number of runes
+// which are replaced because ISO 8859-1 cannot represent them, like emoji or
+// invalid UTF-8
+func encodeLatin1(content []byte) ([]byte, int) {
+	encoded := make([]byte, 0, len(content))
+	replaced := 0
+	for len(content) > 0 {
+		r, size := utf8.DecodeRune(content)
+		content = content[size:]
+		if (r == utf8.RuneError && size <= 1) || r > 0xFF {
+			encoded = append(encoded, latin1Replacement)
+			replaced++
+			continue
+		}
+		encoded = append(encoded, byte(r))
+	}
+	return encoded, replaced
+}
+
+// encodeOutput rewrites the rendered output in the -output-encoding,
+// preceded by a byte order mark with -output-bom. The runes which cannot be
+// represented are replaced and logged as a warning.
+func (o *options) encodeOutput(output *os.File) error {
+	if o.outputEncoding == encodingUTF8 && !o.outputBOM {
+		return nil
+	}
+
+	content, err := renderedBody(output)
+	if err != nil {
+		return err
+	}
+	switch o.outputEncoding {
+	case encodingLatin1:
+		var replaced int
+		content, replaced = encodeLatin1(content)
+		if replaced > 0 {
+			level.Warn(o.logger).Log(
+				"msg", "replaced the characters which the output encoding cannot represent",
+				"encoding", o.outputEncoding, "replacement", string(latin1Replacement), "characters", replaced,
+			)
+		}
+	}
+	if o.outputBOM {
+		content = append(append([]byte{}, utf8BOM...), content...)
+	}
+
+	if err := output.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := output.Seek(0, 0); err != nil {
+		return err
+	}
+	_, err = output.Write(content)
+	return err
+}
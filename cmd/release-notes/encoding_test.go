@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeLatin1(t *testing.T) {
+	encoded, replaced := encodeLatin1([]byte("Größe 🆕 naïve\xff"))
+	require.Equal(t, []byte("Gr\xf6\xdfe ? na\xefve?"), encoded)
+	require.Equal(t, 2, replaced)
+
+	encoded, replaced = encodeLatin1([]byte("plain ASCII"))
+	require.Equal(t, []byte("plain ASCII"), encoded)
+	require.Zero(t, replaced)
+}
+
+func TestEncodeOutput(t *testing.T) {
+	encode := func(encoding string, bom bool) ([]byte, string) {
+		output, err := ioutil.TempFile("", "release-notes-encoding-")
+		require.NoError(t, err)
+		defer os.Remove(output.Name())
+		defer output.Close()
+		_, err = output.WriteString("- 🆕 Fix the café\n")
+		require.NoError(t, err)
+
+		logs := &bytes.Buffer{}
+		o := &options{outputEncoding: encoding, outputBOM: bom, logger: log.NewLogfmtLogger(logs)}
+		require.NoError(t, o.encodeOutput(output))
+
+		content, err := ioutil.ReadFile(output.Name())
+		require.NoError(t, err)
+		return content, logs.String()
+	}
+
+	content, logs := encode(encodingUTF8, false)
+	require.Equal(t, "- 🆕 Fix the café\n", string(content))
+	require.Empty(t, logs)
+
+	content, logs = encode(encodingLatin1, false)
+	require.Equal(t, []byte("- ? Fix the caf\xe9\n"), content)
+	require.Contains(t, logs, "level=warn")
+	require.Contains(t, logs, "characters=1")
+
+	content, _ = encode(encodingUTF8, true)
+	require.Equal(t, "\xef\xbb\xbf- 🆕 Fix the café\n", string(content))
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// envPrefix is the prefix of the environment variables every flag falls back
+// to, like RELEASE_NOTES_START_SHA for -start-sha
+const envPrefix = "RELEASE_NOTES_"
+
+// prefixedEnvName returns the prefixed environment variable of the flag with
+// the provided name. Dashes become underscores and camel case words are
+// separated, so that -requiredAuthor maps to RELEASE_NOTES_REQUIRED_AUTHOR.
+func prefixedEnvName(flagName string) string {
+	var b strings.Builder
+	b.WriteString(envPrefix)
+	for i, r := range flagName {
+		switch {
+		case r == '-' || r == '.':
+			b.WriteRune('_')
+		case unicode.IsUpper(r) && i > 0:
+			b.WriteRune('_')
+			b.WriteRune(r)
+		default:
+			b.WriteRune(unicode.ToUpper(r))
+		}
+	}
+	return b.String()
+}
+
+// applyPrefixedEnv sets every flag whose prefixed environment variable is set
+// to its value. The defaults of the flags, which may come from the legacy
+// unprefixed variables, are replaced, so that the values passed on the command
+// line still take precedence when the flags are parsed.
+func applyPrefixedEnv(flags *flag.FlagSet) error {
+	var err error
+	flags.VisitAll(func(f *flag.Flag) {
+		name := prefixedEnvName(f.Name)
+		value, ok := os.LookupEnv(name)
+		if !ok || err != nil {
+			return
+		}
+		if setErr := f.Value.Set(value); setErr != nil {
+			err = fmt.Errorf("invalid value %q of %s for -%s: %v", value, name, f.Name, setErr)
+			return
+		}
+		// the values of a repeated flag are replaced by the command line
+		if r, ok := f.Value.(*repeatedFlag); ok {
+			r.isDefault = true
+		}
+		f.DefValue = f.Value.String()
+	})
+	return err
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefixedEnvName(t *testing.T) {
+	require.Equal(t, "RELEASE_NOTES_START_SHA", prefixedEnvName("start-sha"))
+	require.Equal(t, "RELEASE_NOTES_REQUIRED_AUTHOR", prefixedEnvName("requiredAuthor"))
+	require.Equal(t, "RELEASE_NOTES_FORMAT", prefixedEnvName("format"))
+}
+
+func TestPrefixedEnv(t *testing.T) {
+	env := map[string]string{
+		"RELEASE_NOTES_START_SHA":  "prefixed",
+		"START_SHA":                "legacy",
+		"END_SHA":                  "legacy",
+		"RELEASE_NOTES_TIMEOUT":    "5m",
+		"RELEASE_NOTES_SKIP_EMPTY": "true",
+	}
+	for key, value := range env {
+		previous, ok := os.LookupEnv(key)
+		require.NoError(t, os.Setenv(key, value))
+		defer func(key, previous string, ok bool) {
+			if ok {
+				os.Setenv(key, previous)
+			} else {
+				os.Unsetenv(key)
+			}
+		}(key, previous, ok)
+	}
+
+	// the prefixed variable takes precedence over the legacy one
+	o := &options{}
+	require.NoError(t, o.BindFlags().Parse([]string{}))
+	require.Equal(t, "prefixed", o.startSHA)
+	require.Equal(t, "legacy", o.endSHA)
+	require.Equal(t, 5*time.Minute, o.timeout)
+	require.True(t, o.skipEmpty)
+
+	// and the flags take precedence over both
+	o = &options{}
+	require.NoError(t, o.BindFlags().Parse([]string{"-start-sha", "flag", "-skip-empty=false"}))
+	require.Equal(t, "flag", o.startSHA)
+	require.False(t, o.skipEmpty)
+
+	// invalid values are an error
+	require.NoError(t, os.Setenv("RELEASE_NOTES_TIMEOUT", "soon"))
+	flags := flag.NewFlagSet("release-notes", flag.ContinueOnError)
+	flags.Duration("timeout", 0, "")
+	require.Error(t, applyPrefixedEnv(flags))
+}
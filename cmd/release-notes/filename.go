@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// outputPlaceholder matches the placeholders within the -output path, like
+// {version}
+var outputPlaceholder = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// outputPlaceholders returns the values of all supported placeholders
+func (o *options) outputPlaceholders() map[string]string {
+	return map[string]string{
+		"version": o.releaseVersion,
+		"date":    o.formatDate(time.Now(), filenameDateLayout),
+		"org":     o.githubOrg,
+		"repo":    o.githubRepo,
+	}
+}
+
+// validateOutputTemplate checks that the -output path only contains supported
+// placeholders which all have a value.
+func (o *options) validateOutputTemplate() error {
+	values := o.outputPlaceholders()
+	for _, match := range outputPlaceholder.FindAllStringSubmatch(o.output, -1) {
+		value, ok := values[match[1]]
+		if !ok {
+			return fmt.Errorf("unknown placeholder %s in -output, supported are {version}, {date}, {org} and {repo}", match[0])
+		}
+		if value == "" {
+			return fmt.Errorf("the placeholder %s in -output has no value", match[0])
+		}
+		if strings.ContainsRune(value, filepath.Separator) {
+			return fmt.Errorf("the value %q of the placeholder %s in -output must not contain a path separator", value, match[0])
+		}
+	}
+	return nil
+}
+
+// outputPath returns the -output path with all placeholders expanded
+func (o *options) outputPath() string {
+	values := o.outputPlaceholders()
+	return outputPlaceholder.ReplaceAllStringFunc(o.output, func(placeholder string) string {
+		return values[placeholder[1:len(placeholder)-1]]
+	})
+}
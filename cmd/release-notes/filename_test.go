@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputPath(t *testing.T) {
+	o := &options{releaseVersion: "v1.16.0", githubOrg: "kubernetes", githubRepo: "release"}
+	today := time.Now().Format("2006-01-02")
+
+	for _, tc := range []struct {
+		output   string
+		expected string
+	}{
+		{"notes.md", "notes.md"},
+		{"notes-{version}.md", "notes-v1.16.0.md"},
+		{"notes-{date}.md", "notes-" + today + ".md"},
+		{"{org}/notes.md", "kubernetes/notes.md"},
+		{"{repo}-notes.md", "release-notes.md"},
+		{"{org}/{repo}/{version}/{date}.json", "kubernetes/release/v1.16.0/" + today + ".json"},
+	} {
+		o.output = tc.output
+		require.NoError(t, o.validateOutputTemplate(), tc.output)
+		require.Equal(t, tc.expected, o.outputPath(), tc.output)
+	}
+
+	o.dateFormat = "20060102"
+	o.output = "notes-{date}.md"
+	require.Equal(t, "notes-"+time.Now().Format("20060102")+".md", o.outputPath())
+}
+
+func TestValidateOutputTemplate(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		opts   *options
+		output string
+	}{
+		{"unknown placeholder", &options{githubOrg: "kubernetes"}, "notes-{branch}.md"},
+		{"empty placeholder", &options{}, "notes-{}.md"},
+		{"version without value", &options{}, "notes-{version}.md"},
+		{"org without value", &options{githubRepo: "release"}, "{org}/{repo}.md"},
+		{"repo without value", &options{githubOrg: "kubernetes"}, "{org}/{repo}.md"},
+		{"date with path separator", &options{dateFormat: "2006/01/02"}, "notes-{date}.md"},
+	} {
+		tc.opts.output = tc.output
+		require.Error(t, tc.opts.validateOutputTemplate(), tc.name)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/go-kit/kit/log/level"
+
+	"k8s.io/release/pkg/notes"
+)
+
+// writeMarkdownOutput renders the notes of the JSON output to -markdown-output.
+// With -render-only-changed, only the sections of the existing markdown whose
+// notes differ from the previous notes of the JSON output are rewritten, and
+// the whole document is rendered if the markdown cannot be updated.
+func (o *options) writeMarkdownOutput(previous, current notes.KeyedReleaseNotes) error {
+	list, err := current.List()
+	if err != nil {
+		return err
+	}
+
+	if o.renderOnlyChanged && previous != nil {
+		existing, err := ioutil.ReadFile(o.markdownOutput)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if len(existing) > 0 {
+			previousList, err := previous.List()
+			if err != nil {
+				return err
+			}
+			updated, ok, err := notes.UpdateMarkdown(existing, previousList, list, o.documentOptions()...)
+			if err != nil {
+				return err
+			}
+			if ok {
+				level.Info(o.logger).Log("msg", "rewrote the changed sections of the markdown output", "path", o.markdownOutput)
+				return ioutil.WriteFile(o.markdownOutput, updated, 0644)
+			}
+			level.Info(o.logger).Log("msg", "the markdown output cannot be updated in place, rendering it completely", "path", o.markdownOutput)
+		}
+	}
+
+	content, err := notes.RenderToBytes(list, "markdown", o.documentOptions()...)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(o.markdownOutput, content, 0644)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/release/pkg/notes"
+)
+
+func TestRenderOnlyChanged(t *testing.T) {
+	dir, err := ioutil.TempDir("", "render-only-changed-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	o := &options{
+		format:            "json",
+		output:            filepath.Join(dir, "notes.json"),
+		markdownOutput:    filepath.Join(dir, "notes.md"),
+		renderOnlyChanged: true,
+		stableAnchors:     true,
+		logger:            log.NewNopLogger(),
+	}
+	feature := &notes.ReleaseNote{PrNumber: 1, PrUrl: "https://github.com/kubernetes/kubernetes/pull/1", Text: "a feature", Markdown: "a feature", Feature: true}
+	bug := &notes.ReleaseNote{PrNumber: 2, PrUrl: "https://github.com/kubernetes/kubernetes/pull/2", Text: "a bug fix", Markdown: "a bug fix", Kinds: []string{"bug"}}
+	require.NoError(t, o.WriteReleaseNotes(notes.ReleaseNoteList{1: feature, 2: bug}))
+
+	markdown, err := ioutil.ReadFile(o.markdownOutput)
+	require.NoError(t, err)
+	require.Contains(t, string(markdown), "- a feature\n")
+	edited := strings.Replace(string(markdown), "- a feature\n", "- a feature, edited\n", 1)
+	require.NoError(t, ioutil.WriteFile(o.markdownOutput, []byte(edited), 0644))
+
+	// the next run only changes the bug fix, whose section is rewritten
+	fixed := &notes.ReleaseNote{PrNumber: 2, PrUrl: "https://github.com/kubernetes/kubernetes/pull/2", Text: "a better bug fix", Markdown: "a better bug fix", Kinds: []string{"bug"}}
+	require.NoError(t, o.WriteReleaseNotes(notes.ReleaseNoteList{2: fixed}))
+
+	markdown, err = ioutil.ReadFile(o.markdownOutput)
+	require.NoError(t, err)
+	require.Contains(t, string(markdown), "- a feature, edited\n")
+	require.Contains(t, string(markdown), "- a better bug fix\n")
+	require.NotContains(t, string(markdown), "- a bug fix\n")
+
+	// without -render-only-changed, the whole document is rendered again
+	o.renderOnlyChanged = false
+	require.NoError(t, o.WriteReleaseNotes(notes.ReleaseNoteList{2: fixed}))
+	markdown, err = ioutil.ReadFile(o.markdownOutput)
+	require.NoError(t, err)
+	require.Contains(t, string(markdown), "- a feature\n")
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"k8s.io/release/pkg/notes"
+)
+
+// isTerminal returns true if the provided file is a character device, like an
+// interactive terminal
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
+// reviewReleaseNotes prints every release note to out and asks whether it
+// should be kept, skipped or edited. The answers are read from in and the
+// resulting list only contains the notes which have been kept or edited. The
+// markdown of edited notes is regenerated by the markdown function.
+func reviewReleaseNotes(in io.Reader, out io.Writer, releaseNotes notes.ReleaseNoteList, markdown func(*notes.ReleaseNote) string) (notes.ReleaseNoteList, error) {
+	numbers := []int{}
+	for number := range releaseNotes {
+		numbers = append(numbers, number)
+	}
+	sort.Ints(numbers)
+
+	reader := bufio.NewReader(in)
+	readLine := func() (string, error) {
+		line, err := reader.ReadString('\n')
+		if err == io.EOF && line != "" {
+			err = nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("review aborted: %v", err)
+		}
+		return strings.TrimSpace(line), nil
+	}
+
+	reviewed := notes.ReleaseNoteList{}
+	for i, number := range numbers {
+		note := releaseNotes[number]
+		fmt.Fprintf(out, "\n[%d/%d] #%d by @%s\n%s\n", i+1, len(numbers), number, note.Author, note.Text)
+
+	prompt:
+		for {
+			fmt.Fprint(out, "[k]eep, [s]kip or [e]dit? (default: keep) ")
+			answer, err := readLine()
+			if err != nil {
+				return nil, err
+			}
+
+			switch strings.ToLower(answer) {
+			case "", "k", "keep":
+				reviewed[number] = note
+				break prompt
+			case "s", "skip":
+				break prompt
+			case "e", "edit":
+				fmt.Fprint(out, "new note text: ")
+				text, err := readLine()
+				if err != nil {
+					return nil, err
+				}
+				if text == "" {
+					continue
+				}
+				note.Text = text
+				note.Markdown = markdown(note)
+				reviewed[number] = note
+				break prompt
+			default:
+				fmt.Fprintf(out, "unknown answer %q\n", answer)
+			}
+		}
+	}
+
+	return reviewed, nil
+}
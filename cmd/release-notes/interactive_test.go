@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/release/pkg/notes"
+)
+
+func TestReviewReleaseNotes(t *testing.T) {
+	list := func() notes.ReleaseNoteList {
+		releaseNotes := notes.ReleaseNoteList{}
+		for _, number := range []int{1, 2, 3} {
+			releaseNotes[number] = &notes.ReleaseNote{
+				PrNumber: number,
+				Text:     "original",
+				// wrapped markdown no longer contains the text verbatim
+				Markdown: "orig-\ninal",
+			}
+		}
+		return releaseNotes
+	}
+	markdown := func(note *notes.ReleaseNote) string { return "rendered " + note.Text }
+
+	for _, tc := range []struct {
+		name     string
+		script   string
+		expected map[int]string
+		err      bool
+	}{
+		{
+			name:     "keep all by default",
+			script:   "\n\nk\n",
+			expected: map[int]string{1: "orig-\ninal", 2: "orig-\ninal", 3: "orig-\ninal"},
+		},
+		{
+			name:     "skip and edit",
+			script:   "s\ne\nedited text\nkeep\n",
+			expected: map[int]string{2: "rendered edited text", 3: "orig-\ninal"},
+		},
+		{
+			name:     "empty edit asks again",
+			script:   "e\n\nskip\nx\nk\nk",
+			expected: map[int]string{2: "orig-\ninal", 3: "orig-\ninal"},
+		},
+		{
+			name:   "aborted review",
+			script: "k\n",
+			err:    true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			reviewed, err := reviewReleaseNotes(strings.NewReader(tc.script), out, list(), markdown)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			result := map[int]string{}
+			for number, note := range reviewed {
+				result[number] = note.Markdown
+			}
+			require.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestNoteMarkdownKeepsCollapsedPRs(t *testing.T) {
+	o := &options{githubOrg: "kubernetes", githubRepo: "kubernetes", dedupeIdentical: true}
+	note := &notes.ReleaseNote{
+		PrNumber:  1,
+		PrNumbers: []int{1, 2},
+		PrUrl:     "https://github.com/kubernetes/kubernetes/pull/1",
+		Author:    "foo",
+		AuthorUrl: "https://github.com/foo",
+		Text:      "edited text",
+	}
+	require.Equal(t,
+		"edited text ([#1](https://github.com/kubernetes/kubernetes/pull/1), [@foo](https://github.com/foo))\n\n  Also in [#2](https://github.com/kubernetes/kubernetes/pull/2)",
+		o.noteMarkdown(note),
+	)
+}
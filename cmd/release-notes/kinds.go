@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+
+	"gopkg.in/yaml.v2"
+
+	"k8s.io/release/pkg/notes"
+)
+
+// loadKindRules reads an ordered YAML list of title patterns and the kinds
+// they classify notes into, like `- {pattern: "^(?i)fix", kind: bug}`
+func loadKindRules(path string) ([]notes.KindRule, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read kind rules file: %v", err)
+	}
+
+	entries := []struct {
+		Pattern string `yaml:"pattern"`
+		Kind    string `yaml:"kind"`
+	}{}
+	if err := yaml.Unmarshal(content, &entries); err != nil {
+		return nil, fmt.Errorf("unable to parse kind rules file %s: %v", path, err)
+	}
+
+	rules := []notes.KindRule{}
+	for _, entry := range entries {
+		if entry.Pattern == "" || entry.Kind == "" {
+			return nil, fmt.Errorf("every rule of the kind rules file %s requires a pattern and a kind", path)
+		}
+		pattern, err := regexp.Compile(entry.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q in kind rules file %s: %v", entry.Pattern, path, err)
+		}
+		rules = append(rules, notes.KindRule{Pattern: pattern, Kind: entry.Kind})
+	}
+	return rules, nil
+}
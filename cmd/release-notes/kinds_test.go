@@ -0,0 +1,40 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/release/pkg/notes"
+)
+
+func TestLoadKindRules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kind-rules-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "rules.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(
+		"- pattern: \"(?i)^cleanup\"\n  kind: cleanup\n- {pattern: \"(?i)^fix\", kind: bug}\n",
+	), 0644))
+
+	rules, err := loadKindRules(path)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	require.Equal(t, "cleanup", notes.KindFromTitle("Cleanup the kubelet", rules))
+	require.Equal(t, "bug", notes.KindFromTitle("fix the kubelet", rules))
+	require.Equal(t, "", notes.KindFromTitle("Add a flag", rules))
+
+	for _, content := range []string{
+		"- {pattern: \"(\", kind: bug}",
+		"- {pattern: \"^fix\"}",
+		"pattern: fix",
+	} {
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+		_, err := loadKindRules(path)
+		require.Error(t, err, content)
+	}
+}
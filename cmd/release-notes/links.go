@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// verifyLinksConcurrency bounds the number of links which are checked at once,
+// in addition to the -host-concurrency of the requests per host
+const verifyLinksConcurrency = 8
+
+// linkURL matches the http(s) URLs within the rendered output, which are
+// delimited by whitespace, markup or quotes
+var linkURL = regexp.MustCompile("https?://[^\\s<>()\\[\\]{}\"'`]+")
+
+// brokenLink is a link which did not respond with a 2xx status
+type brokenLink struct {
+	URL    string
+	Status string
+}
+
+// extractLinks returns the unique http(s) URLs within the content, in the
+// order of their first occurrence
+func extractLinks(content []byte) []string {
+	links := []string{}
+	seen := map[string]struct{}{}
+	for _, match := range linkURL.FindAllString(string(content), -1) {
+		// the HTML and DocBook formats escape the URLs
+		link := strings.TrimRight(html.UnescapeString(match), ".,;:!?")
+		if _, ok := seen[link]; ok {
+			continue
+		}
+		seen[link] = struct{}{}
+		links = append(links, link)
+	}
+	return links
+}
+
+// checkLink requests the link and returns its status, or the error of the
+// request. Servers which do not support HEAD requests are sent a GET request.
+func checkLink(client *http.Client, link string) (int, string) {
+	status, text := 0, ""
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		req, err := http.NewRequest(method, link, nil)
+		if err != nil {
+			return 0, err.Error()
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, err.Error()
+		}
+		resp.Body.Close()
+
+		status, text = resp.StatusCode, resp.Status
+		if status != http.StatusMethodNotAllowed && status != http.StatusNotImplemented {
+			break
+		}
+	}
+	return status, text
+}
+
+// verifyLinks requests all links and returns the ones which did not respond
+// with a 2xx status, in the order of the links
+func verifyLinks(client *http.Client, links []string) []brokenLink {
+	results := make([]*brokenLink, len(links))
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, verifyLinksConcurrency)
+	for i, link := range links {
+		wg.Add(1)
+		go func(i int, link string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			if status, text := checkLink(client, link); status < 200 || status > 299 {
+				results[i] = &brokenLink{URL: link, Status: text}
+			}
+		}(i, link)
+	}
+	wg.Wait()
+
+	broken := []brokenLink{}
+	for _, result := range results {
+		if result != nil {
+			broken = append(broken, *result)
+		}
+	}
+	return broken
+}
+
+// writeBrokenLinks writes a line for every broken link to w, which is the tab
+// separated status and URL, like "404 Not Found\thttps://github.com/foo"
+func writeBrokenLinks(broken []brokenLink, w io.Writer) error {
+	for _, link := range broken {
+		if _, err := fmt.Fprintf(w, "%s\t%s\n", link.Status, link.URL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractLinks(t *testing.T) {
+	content := []byte(`- Fixed a bug ([#1](https://github.com/kubernetes/kubernetes/pull/1), [@jdoe](https://github.com/jdoe))
+- See <https://kubernetes.io/docs>.
+<a href="https://example.com/?a=1&amp;b=2">link</a>
+{"pr_url": "https://github.com/kubernetes/kubernetes/pull/1"}
+`)
+	require.Equal(t, []string{
+		"https://github.com/kubernetes/kubernetes/pull/1",
+		"https://github.com/jdoe",
+		"https://kubernetes.io/docs",
+		"https://example.com/?a=1&b=2",
+	}, extractLinks(content))
+}
+
+func TestVerifyLinks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+		case "/redirect":
+			http.Redirect(w, r, "/ok", http.StatusMovedPermanently)
+		case "/get-only":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	links := []string{}
+	for _, path := range []string{"/ok", "/missing", "/redirect", "/get-only"} {
+		links = append(links, server.URL+path)
+	}
+	links = append(links, "http://127.0.0.1:0/unreachable")
+
+	broken := verifyLinks(&http.Client{}, links)
+	require.Len(t, broken, 2)
+	require.Equal(t, brokenLink{URL: server.URL + "/missing", Status: "404 Not Found"}, broken[0])
+	require.Equal(t, "http://127.0.0.1:0/unreachable", broken[1].URL)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, writeBrokenLinks(broken[:1], out))
+	require.Equal(t, fmt.Sprintf("404 Not Found\t%s/missing\n", server.URL), out.String())
+}
@@ -1,39 +1,181 @@
 package main
 
 import (
+	"bytes"
 	"context"
-	"encoding/json"
-	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
 	"github.com/google/go-github/v27/github"
 	"github.com/kolide/kit/env"
+	"github.com/pkg/errors"
 	"golang.org/x/oauth2"
 
 	"k8s.io/release/pkg/notes"
 )
 
 type options struct {
-	githubToken    string
-	githubOrg      string
-	githubRepo     string
-	output         string
-	branch         string
-	startSHA       string
-	endSHA         string
-	startRev       string
-	endRev         string
-	releaseVersion string
-	format         string
-	requiredAuthor string
-	debug          bool
-	logger         log.Logger
-	version        bool
+	githubToken         string
+	githubOrg           string
+	githubRepo          string
+	githubBaseURL       string
+	githubGraphQLURL    string
+	githubHostAllowlist string
+	output              string
+	outputDir           string
+	tempDir             string
+	postRenderCommand   string
+	transformCommand    string
+	postRenderOutput    string
+	splitBy             string
+	skipEmpty           bool
+	branch              string
+	startSHA            string
+	endSHA              string
+	startRev            string
+	endRev              string
+	startTag            string
+	endTag              string
+	maxRangeCommits     int
+	baseRef             string
+	releaseVersion      string
+	rangeFile           string
+	rangesFile          string
+	input               string
+	mergeStrategy       string
+	discoverRange       bool
+	branchPattern       string
+	format              string
+	requiredAuthor      string
+	authorField         string
+	requiredTeam        string
+	securityLabels      string
+	includeDescription  bool
+	linkIssues          bool
+	includeSubtasks     bool
+	resolveSeeTitle     bool
+	seeTitlePhrases     string
+	descriptionMaxChars int
+	noteSource          string
+	retry5xx            bool
+	maxRetries          int
+	timeout             time.Duration
+	requestTimeout      time.Duration
+	hostConcurrency     int
+	githubAccept        string
+	tokenExpiryWarn     time.Duration
+	createRelease       bool
+	releaseDraft        bool
+	releasePrerelease   bool
+	createDiscussion    bool
+	discussionRepo      string
+	discussionCategory  string
+	stream              bool
+	compact             bool
+	prNumberRegex       string
+	validateOutput      bool
+	checksum            bool
+	outputEncoding      string
+	outputBOM           bool
+	warningsFile        string
+	warnings            *notes.Warnings
+	verifyLinks         bool
+	interactive         bool
+	preview             bool
+	contributors        bool
+	firstTimers         []string
+	contributorsFormat  string
+	indexMarkdown       string
+	flagFirstTime       bool
+	labelLegend         bool
+	labelColors         map[string]string
+	trackBranches       string
+	ownershipFile       string
+	inferSIGBudget      int
+	inferKind           bool
+	kindRulesFile       string
+	kindRules           []notes.KindRule
+	excludeDocs         bool
+	docsLabels          string
+	docsPaths           string
+	docsPathBudget      int
+	pathFilter          string
+	pathFilterBudget    int
+	topReacted          int
+	reactionsBudget     int
+	showStats           bool
+	statsBudget         int
+	sortBy              string
+	withinSectionSort   string
+	parsedSortKeys      []notes.SortKey
+	collapseDeps        bool
+	pruneEmptySections  bool
+	otherSectionTitle   string
+	patchBuckets        bool
+	sigOwners           map[string]string
+	excludePRs          string
+	excludeAuthor       string
+	excludeTitles       *repeatedFlag
+	parsedTitlePatterns []*regexp.Regexp
+	suppressReverted    bool
+	dedupeIdentical     bool
+	checkpointFile      string
+	checkpointInterval  int
+	estimate            bool
+	normalize           bool
+	requireMerged       bool
+	stripMarkdown       bool
+	fillGaps            bool
+	coveredStart        string
+	maxNoteLength       int
+	strictNotes         bool
+	failOnUnlabeled     bool
+	requireLabels       string
+	wrapNote            int
+	annotateNew         bool
+	markdownOutput      string
+	maxFileBytes        int
+	renderOnlyChanged   bool
+	kindPriority        string
+	groupBy             string
+	areaPriority        string
+	layout              string
+	stableAnchors       bool
+	markdownFlavor      string
+	dateFormat          string
+	enforceStyle        bool
+	reportStyle         bool
+	noteTemplate        string
+	prLinkFormat        string
+	parsedNoteTemplate  *template.Template
+	badges              string
+	badgeColors         string
+	parsedBadgeColors   map[string]string
+	excludedPRs         []int
+	debug               bool
+	verbosity           int
+	quiet               bool
+	color               string
+	logger              log.Logger
+	githubClient        *github.Client
+	version             bool
 }
 
 func (o *options) BindFlags() *flag.FlagSet {
@@ -63,13 +205,95 @@ func (o *options) BindFlags() *flag.FlagSet {
 		"Name of github repository",
 	)
 
+	// githubBaseURL is the REST API endpoint of a GitHub Enterprise instance.
+	flags.StringVar(
+		&o.githubBaseURL,
+		"github-base-url",
+		env.String("GITHUB_BASE_URL", ""),
+		"The REST API URL of a GitHub Enterprise instance, like https://github.example.com/api/v3/. Defaults to github.com",
+	)
+
+	// githubGraphQLURL is the GraphQL API endpoint of a GitHub Enterprise
+	// instance.
+	flags.StringVar(
+		&o.githubGraphQLURL,
+		"github-graphql-url",
+		env.String("GITHUB_GRAPHQL_URL", ""),
+		"The GraphQL API URL of a GitHub Enterprise instance, like https://github.example.com/api/graphql. Defaults to github.com. It is only used by -create-discussion, the notes are fetched via the REST API",
+	)
+
+	// githubHostAllowlist are additional hosts which may receive the token.
+	flags.StringVar(
+		&o.githubHostAllowlist,
+		"github-hostname-allowlist",
+		env.String("GITHUB_HOSTNAME_ALLOWLIST", ""),
+		"Comma separated hostnames which the GitHub token may be sent to, in addition to github.com, api.github.com, uploads.github.com and the hosts of -github-base-url and -github-graphql-url. Requests to any other host are refused",
+	)
+
 	// output contains the path on the filesystem to where the resultant
 	// release notes should be printed.
 	flags.StringVar(
 		&o.output,
 		"output",
 		env.String("OUTPUT", ""),
-		"The path to the where the release notes will be printed",
+		"The path to the where the release notes will be printed. May contain the placeholders {version}, {date}, {org} and {repo}",
+	)
+
+	// transformCommand changes the fetched notes before they are rendered.
+	flags.StringVar(
+		&o.transformCommand,
+		"transform-command",
+		env.String("TRANSFORM_COMMAND", ""),
+		"A command to transform the notes before they are rendered, like a script applying organization specific changes. The command line is run with sh, so that paths and arguments with spaces can be quoted. The notes are piped to its standard input as JSON and the notes it writes to its standard output as JSON are rendered instead",
+	)
+
+	// postRenderCommand converts the markdown, like into a PDF with pandoc.
+	flags.StringVar(
+		&o.postRenderCommand,
+		"post-render-command",
+		env.String("POST_RENDER_COMMAND", ""),
+		"A command to convert the rendered markdown, like a script calling pandoc. The command line is run with sh, so that paths and arguments with spaces can be quoted, with the paths of the rendered file and of -post-render-output appended as arguments and the markdown piped to its standard input",
+	)
+
+	// postRenderOutput is the target path passed to the postRenderCommand.
+	flags.StringVar(
+		&o.postRenderOutput,
+		"post-render-output",
+		env.String("POST_RENDER_OUTPUT", ""),
+		"The target path which is passed to -post-render-command, like notes.pdf",
+	)
+
+	// outputDir is the directory where split release notes are written.
+	flags.StringVar(
+		&o.outputDir,
+		"output-dir",
+		env.String("OUTPUT_DIR", ""),
+		"The directory where the release notes are written as one file per group, as selected by -split-by, or as one file per note with -format hugo",
+	)
+
+	// tempDir is where the repository is cloned and the temporary output
+	// file is created.
+	flags.StringVar(
+		&o.tempDir,
+		"temp-dir",
+		env.String("TEMP_DIR", ""),
+		"The existing, writable directory where the repository is cloned to resolve -start-rev and -end-rev, and where the release notes are written without -output. Defaults to the default directory for temporary files of the OS",
+	)
+
+	// splitBy selects how the release notes are split into files.
+	flags.StringVar(
+		&o.splitBy,
+		"split-by",
+		env.String("SPLIT_BY", "kind"),
+		"How to split the release notes written to -output-dir (options: kind)",
+	)
+
+	// skipEmpty omits the files of groups without notes.
+	flags.BoolVar(
+		&o.skipEmpty,
+		"skip-empty",
+		env.Bool("SKIP_EMPTY", false),
+		"Do not write the files of groups without notes to -output-dir",
 	)
 
 	// branch is which branch to scrape.
@@ -115,6 +339,39 @@ func (o *options) BindFlags() *flag.FlagSet {
 		"The git revision to end at. Can be used as alternative to end-sha.",
 	)
 
+	// startTag and endTag are tags which are resolved to the start and end
+	// SHA with the API, so that no clone is needed.
+	flags.StringVar(
+		&o.startTag,
+		"start-tag",
+		env.String("START_TAG", ""),
+		"The tag to start at, which is resolved with the GitHub API instead of a clone, like -start-rev would. Falls back to resolving it in a clone if the API lookup fails",
+	)
+	flags.StringVar(
+		&o.endTag,
+		"end-tag",
+		env.String("END_TAG", ""),
+		"The tag to end at, which is resolved with the GitHub API instead of a clone, like -end-rev would. Falls back to resolving it in a clone if the API lookup fails",
+	)
+
+	// maxRangeCommits guards against walking a wrong range, like one starting
+	// at the root commit, which would exhaust the rate limit.
+	flags.IntVar(
+		&o.maxRangeCommits,
+		"max-range-commits",
+		env.Int("MAX_RANGE_COMMITS", 20000),
+		"Refuse to fetch the notes of a range with more commits than this, which is most likely wrong. The commits are counted with a single comparison up front. Set to 0 to disable the limit",
+	)
+
+	// baseRef is the ref which the branch forked from. The start is set to
+	// the merge-base of both.
+	flags.StringVar(
+		&o.baseRef,
+		"base-ref",
+		env.String("BASE_REF", ""),
+		"The git ref the branch forked from, like the previous release branch. The notes start at the merge-base of it and -branch. Can be used as alternative to start-sha.",
+	)
+
 	// releaseVersion is the version number you want to tag the notes with.
 	flags.StringVar(
 		&o.releaseVersion,
@@ -123,12 +380,64 @@ func (o *options) BindFlags() *flag.FlagSet {
 		"Which release version to tag the entries as.",
 	)
 
+	// rangeFile contains the commit range of a release as written by the
+	// release tooling.
+	flags.StringVar(
+		&o.rangeFile,
+		"range-file",
+		env.String("RANGE_FILE", ""),
+		"A JSON or YAML file with the start_sha, end_sha and optionally release_version of the release. Explicitly set flags, including -start-rev, -end-rev, -start-tag and -end-tag, take precedence",
+	)
+
+	// rangesFile lists the commit ranges of several releases which are
+	// generated in one run.
+	flags.StringVar(
+		&o.rangesFile,
+		"ranges-file",
+		env.String("RANGES_FILE", ""),
+		"A JSON or YAML list of releases with the start_sha, end_sha, output and optionally release_version of each, which are generated in sequence",
+	)
+
+	// input combines the notes of previous JSON outputs instead of fetching
+	// them from GitHub.
+	flags.StringVar(
+		&o.input,
+		"input",
+		env.String("INPUT", ""),
+		"Comma separated JSON files of previous runs to merge and render instead of fetching the notes from GitHub",
+	)
+
+	// mergeStrategy resolves notes for the same PR in different -input files.
+	flags.StringVar(
+		&o.mergeStrategy,
+		"merge-strategy",
+		env.String("MERGE_STRATEGY", string(notes.MergeStrategyError)),
+		"How to resolve different notes for the same PR in the -input files (options: error, first, last, prefer-newest). `prefer-newest` keeps the note whose PR was updated last, which also applies to the notes of the existing JSON -output",
+	)
+
+	// discoverRange derives the commit range from the release branch of the
+	// release version.
+	flags.BoolVar(
+		&o.discoverRange,
+		"discover-range",
+		env.Bool("DISCOVER_RANGE", false),
+		"Discover the release branch of -release-version and use the commits since it forked from -branch. Explicitly set SHAs take precedence",
+	)
+
+	// branchPattern is the naming convention of the release branches.
+	flags.StringVar(
+		&o.branchPattern,
+		"release-branch-pattern",
+		env.String("RELEASE_BRANCH_PATTERN", notes.DefaultReleaseBranchPattern),
+		"The name of the release branches with the {major} and {minor} placeholders, used by -discover-range",
+	)
+
 	// format is the output format to produce the notes in.
 	flags.StringVar(
 		&o.format,
 		"format",
 		env.String("FORMAT", "markdown"),
-		"The format for notes output (options: markdown, json)",
+		"The format for notes output (options: "+strings.Join(notes.Formats, ", ")+"). The hugo format writes a content file with front matter per note and an index page linking them to -output-dir",
 	)
 
 	flags.StringVar(
@@ -138,225 +447,2677 @@ func (o *options) BindFlags() *flag.FlagSet {
 		"Only commits from this GitHub user are considered. Set to empty string to include all users",
 	)
 
+	// securityLabels mark the PRs which are listed in the security section.
+	flags.StringVar(
+		&o.securityLabels,
+		"security-labels",
+		env.String("SECURITY_LABELS", strings.Join(notes.DefaultSecurityLabels, ",")),
+		"Comma separated PR labels which list the note in the Security section at the top of the document",
+	)
+
+	// requiredTeam accepts the commits of all members of a team.
+	flags.StringVar(
+		&o.requiredTeam,
+		"required-team",
+		env.String("REQUIRED_TEAM", ""),
+		"Only commits from members of this GitHub team are considered, like release-bots within -github-org or org/release-bots. Replaces -requiredAuthor and requires a token with the read:org scope",
+	)
+
+	// authorField selects which user of a commit must be the required author.
+	flags.StringVar(
+		&o.authorField,
+		"author-field",
+		env.String("AUTHOR_FIELD", string(notes.AuthorFieldEither)),
+		"The user of a commit which is compared against -requiredAuthor (options: author, committer, either)",
+	)
+
+	// includeDescription adds the first paragraph of the PR description to
+	// every note.
 	flags.BoolVar(
-		&o.debug,
-		"debug",
-		env.Bool("DEBUG", false),
-		"Enable debug logging",
+		&o.includeDescription,
+		"include-description",
+		env.Bool("INCLUDE_DESCRIPTION", false),
+		"Include the first paragraph of the PR description with every note",
 	)
 
+	// linkIssues appends the issues fixed by the PR to every note.
 	flags.BoolVar(
-		&o.version,
-		"version",
-		false,
-		"Print version information",
+		&o.linkIssues,
+		"link-issues",
+		env.Bool("LINK_ISSUES", false),
+		"Append links to the issues fixed by the PR, like `Fixes #1234`, to every note",
 	)
 
-	return flags
-}
+	// includeSubtasks renders the task lists of the PR descriptions, like the
+	// tracking issues of an enhancement, under every note.
+	flags.BoolVar(
+		&o.includeSubtasks,
+		"include-subtasks",
+		env.Bool("INCLUDE_SUBTASKS", false),
+		"Render the task list items of the PR description, like `- [x] #1234`, as a task list under every note. The task lists are omitted with -markdown-flavor commonmark, which lacks them",
+	)
 
-func (o *options) GetReleaseNotes() (notes.ReleaseNoteList, error) {
-	// Create the GitHub API client
-	ctx := context.Background()
-	httpClient := oauth2.NewClient(ctx, oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: o.githubToken},
-	))
-	githubClient := github.NewClient(httpClient)
+	// resolveSeeTitle replaces the notes which only refer to the PR title,
+	// like "See PR title.", by the title.
+	flags.BoolVar(
+		&o.resolveSeeTitle,
+		"resolve-see-title",
+		env.Bool("RESOLVE_SEE_TITLE", true),
+		"Replace the notes which only refer to the PR title, like `See PR title.`, by the title of the PR. Use -resolve-see-title=false to keep them as written",
+	)
 
-	// Fetch a list of fully-contextualized release notes
-	level.Info(o.logger).Log("msg", "fetching all commits. this might take a while...")
+	// seeTitlePhrases are the notes replaced with -resolve-see-title.
+	flags.StringVar(
+		&o.seeTitlePhrases,
+		"see-title-phrases",
+		env.String("SEE_TITLE_PHRASES", strings.Join(notes.DefaultSeeTitlePhrases, ",")),
+		"Comma separated notes which refer to the PR title, matched ignoring case and trailing punctuation",
+	)
 
-	opts := []notes.GithubApiOption{notes.WithContext(ctx)}
-	if o.githubOrg != "" {
-		opts = append(opts, notes.WithOrg(o.githubOrg))
-	}
-	if o.githubRepo != "" {
-		opts = append(opts, notes.WithRepo(o.githubRepo))
-	}
+	// descriptionMaxChars limits the length of the included PR description.
+	flags.IntVar(
+		&o.descriptionMaxChars,
+		"description-max-chars",
+		env.Int("DESCRIPTION_MAX_CHARS", 280),
+		"The maximum number of characters of the PR description to include. Set to 0 to disable truncation",
+	)
 
-	releaseNotes, err := notes.ListReleaseNotes(githubClient, o.logger, o.branch, o.startSHA, o.endSHA, o.requiredAuthor, o.releaseVersion, opts...)
-	if err != nil {
-		level.Error(o.logger).Log("msg", "error generating release notes", "err", err)
-		return nil, err
-	}
+	// normalize cleans up the whitespace of the notes.
+	flags.BoolVar(
+		&o.normalize,
+		"normalize",
+		env.Bool("NORMALIZE", true),
+		"Normalize line endings and trim or collapse superfluous whitespace of the notes. Set to false to preserve notes byte-exact",
+	)
 
-	return releaseNotes, nil
-}
+	// requireMerged ignores the PRs associated with a commit which are not
+	// merged.
+	flags.BoolVar(
+		&o.requireMerged,
+		"require-merged",
+		env.Bool("REQUIRE_MERGED", true),
+		"Ignore the PRs associated with a commit which are not merged, like another PR containing the same commit. Set to false to use the first associated PR",
+	)
 
-func (o *options) WriteReleaseNotes(releaseNotes notes.ReleaseNoteList) error {
-	level.Info(o.logger).Log("msg", "got the commits, performing rendering")
+	// fillGaps fetches the notes between the range recorded for the existing
+	// JSON output and the current range.
+	flags.BoolVar(
+		&o.fillGaps,
+		"fill-gaps",
+		env.Bool("FILL_GAPS", false),
+		"Fetch the notes of the commits between the range recorded next to the existing JSON output and -start-sha, instead of failing",
+	)
 
-	// Open a handle to the file which will contain the release notes output
-	var output *os.File
-	var err error
-	var existingNotes notes.ReleaseNoteList
+	// stripMarkdown flattens the text of the notes to plain text.
+	flags.BoolVar(
+		&o.stripMarkdown,
+		"strip-markdown",
+		env.Bool("STRIP_MARKDOWN", false),
+		"Flatten the markdown in the text of the notes to plain text, like links to \"text (url)\". Requires -format json, as the markdown format does not render the text",
+	)
 
-	if o.output != "" {
-		output, err = os.OpenFile(o.output, os.O_RDWR|os.O_CREATE, 0644)
-		if err != nil {
-			level.Error(o.logger).Log("msg", "error opening the supplied output file", "err", err)
-			return err
-		}
-	} else {
-		output, err = ioutil.TempFile("", "release-notes-")
-		if err != nil {
-			level.Error(o.logger).Log("msg", "error creating a temporary file to write the release notes to", "err", err)
-			return err
-		}
-	}
+	// suppressReverted drops the notes of PRs reverted within the range.
+	flags.BoolVar(
+		&o.suppressReverted,
+		"suppress-reverted-in-range",
+		env.Bool("SUPPRESS_REVERTED_IN_RANGE", false),
+		"Drop the notes of commits which are reverted within the same range, as well as the notes of the reverts",
+	)
 
-	// Contextualized release notes can be printed in a variety of formats
-	switch o.format {
-	case "json":
-		byteValue, _ := ioutil.ReadAll(output)
+	// dedupeIdentical collapses notes with identical text.
+	flags.BoolVar(
+		&o.dedupeIdentical,
+		"dedupe-identical-text",
+		env.Bool("DEDUPE_IDENTICAL_TEXT", false),
+		"Collapse notes with identical text into the note of the lowest PR number, which links all other PRs",
+	)
 
-		if len(byteValue) > 0 {
-			if err := json.Unmarshal(byteValue, &existingNotes); err != nil {
-				level.Error(o.logger).Log("msg", "error unmarshalling existing notes", "err", err)
-				return err
-			}
-		}
+	// estimate reports the API requests of the run instead of fetching the
+	// notes.
+	flags.BoolVar(
+		&o.estimate,
+		"estimate",
+		env.Bool("ESTIMATE", false),
+		"Only estimate the GitHub API requests needed to fetch the notes of the range and compare them with the remaining rate limit, without fetching any note",
+	)
 
-		if len(existingNotes) > 0 {
-			output.Truncate(0)
-			output.Seek(0, 0)
+	// checkpointFile makes long runs restartable.
+	flags.StringVar(
+		&o.checkpointFile,
+		"checkpoint-file",
+		env.String("CHECKPOINT_FILE", ""),
+		"Periodically save the progress to this file and resume from it after an interruption. The file is removed once the notes are complete",
+	)
 
-			for i := 0; i < len(existingNotes); i++ {
-				_, ok := releaseNotes[existingNotes[i].PrNumber]
-				if !ok {
-					releaseNotes[existingNotes[i].PrNumber] = existingNotes[i]
-				}
-			}
-		}
+	// checkpointInterval is the number of commits between two checkpoints.
+	flags.IntVar(
+		&o.checkpointInterval,
+		"checkpoint-interval",
+		env.Int("CHECKPOINT_INTERVAL", notes.DefaultCheckpointInterval),
+		"The number of commits to process between two saves of -checkpoint-file",
+	)
 
-		enc := json.NewEncoder(output)
-		enc.SetIndent("", "  ")
-		if err := enc.Encode(releaseNotes); err != nil {
-			level.Error(o.logger).Log("msg", "error encoding JSON output", "err", err)
-			os.Exit(1)
-		}
-	case "markdown":
-		doc, err := notes.CreateDocument(releaseNotes)
-		if err != nil {
-			level.Error(o.logger).Log("msg", "error creating release note document", "err", err)
-			return err
-		}
+	// excludePRs lists PRs whose notes are dropped after fetching.
+	flags.StringVar(
+		&o.excludePRs,
+		"exclude-prs",
+		env.String("EXCLUDE_PRS", ""),
+		"Comma separated list of PR numbers whose release notes are excluded, also from the notes collapsed by -dedupe-identical-text and the ones merged from an existing JSON output",
+	)
 
-		if err := notes.RenderMarkdown(doc, output); err != nil {
-			level.Error(o.logger).Log("msg", "error rendering release note document to markdown", "err", err)
-			return err
-		}
+	// excludeAuthor lists PR authors whose notes are dropped after fetching.
+	flags.StringVar(
+		&o.excludeAuthor,
+		"exclude-author",
+		env.String("EXCLUDE_AUTHOR", ""),
+		"Comma separated GitHub logins, like bot accounts, whose release notes are excluded. The logins are compared against the author of the note, which is the author of its PR, case-insensitively. Composes with -requiredAuthor, which filters the commits",
+	)
 
-	default:
-		errString := fmt.Sprintf("%q is an unsupported format", o.format)
-		level.Error(o.logger).Log("msg", errString)
-		return errors.New(errString)
-	}
+	// excludeTitles drops the notes whose PR title matches, like automated
+	// version bumps.
+	o.excludeTitles = newRepeatedFlag(env.String("EXCLUDE_TITLE_PATTERN", ""))
+	flags.Var(
+		o.excludeTitles,
+		"exclude-title-pattern",
+		"A regular expression, like \"^Update Go to\", which excludes the release notes whose PR title matches it. May be given several times; the number of notes every pattern removed is logged",
+	)
 
-	level.Info(o.logger).Log(
-		"msg", "release notes written to file",
-		"path", output.Name(),
-		"format", o.format,
+	// trackBranches lists the branches which are checked for containing the
+	// commit of every note.
+	flags.StringVar(
+		&o.trackBranches,
+		"track-branches",
+		env.String("TRACK_BRANCHES", ""),
+		"Comma separated list of branches, like release-1.19,release-1.20, to annotate every note with the branches containing it",
 	)
-	return nil
-}
 
-func parseOptions(args []string, logger log.Logger) (*options, error) {
-	opts := &options{}
-	flags := opts.BindFlags()
+	// noteSource selects where the release notes are extracted from.
+	flags.StringVar(
+		&o.noteSource,
+		"note-source",
+		env.String("NOTE_SOURCE", string(notes.NoteSourceReleaseNote)),
+		"Where to extract the release notes from (options: release-note, conventional, commit-body)",
+	)
 
-	// Parse the args.
-	if err := flags.Parse(args); err != nil {
-		return nil, err
-	}
+	// retry5xx enables retrying GitHub API requests which failed with a server
+	// error.
+	flags.BoolVar(
+		&o.retry5xx,
+		"retry-5xx",
+		env.Bool("RETRY_5XX", true),
+		"Retry GitHub API requests which failed with a 5xx status code",
+	)
 
-	if opts.version {
-		return nil, errors.New("version")
-	}
+	// maxRetries is the maximum amount of retries for a single request.
+	flags.IntVar(
+		&o.maxRetries,
+		"max-retries",
+		env.Int("MAX_RETRIES", 3),
+		"The maximum number of retries for a failed GitHub API request",
+	)
 
-	// The GitHub Token is required.
-	if opts.githubToken == "" {
-		return nil, errors.New("GitHub token must be set via -github-token or $GITHUB_TOKEN")
-	}
+	// timeout is the overall deadline for fetching the release notes.
+	flags.DurationVar(
+		&o.timeout,
+		"timeout",
+		env.Duration("TIMEOUT", 0),
+		"The overall timeout for fetching the release notes, like 30m. Set to 0 to disable",
+	)
 
-	// The start SHA is required.
-	if opts.startSHA == "" && opts.startRev == "" {
-		return nil, errors.New("The starting commit hash must be set via -start-sha, $START_SHA, -start-rev or $START_REV")
-	}
+	// requestTimeout is the deadline for a single GitHub API request.
+	flags.DurationVar(
+		&o.requestTimeout,
+		"request-timeout",
+		env.Duration("REQUEST_TIMEOUT", 0),
+		"The timeout for a single GitHub API request, like 30s. Timed out requests are retried. Set to 0 to disable",
+	)
 
-	// The end SHA is required.
-	if opts.endSHA == "" && opts.endRev == "" {
-		return nil, errors.New("The ending commit hash must be set via -end-sha, $END_SHA, -end-rev or $END_REV")
-	}
+	// hostConcurrency bounds the GitHub API requests in flight at the same
+	// time.
+	flags.IntVar(
+		&o.hostConcurrency,
+		"host-concurrency",
+		env.Int("HOST_CONCURRENCY", 0),
+		"The maximum number of GitHub API requests in flight at the same time, to stay below the secondary rate limits. Set to 0 to disable",
+	)
 
-	// Check if we have to parse a revision
-	tmpDir := ""
-	if opts.startRev != "" || opts.endRev != "" {
-		level.Info(logger).Log("msg", "cloning repository to discover start or end sha")
-		dir, err := notes.CloneTempRepository(opts.githubOrg, opts.githubRepo)
-		if err != nil {
-			return nil, err
-		}
-		defer os.RemoveAll(dir)
-		tmpDir = dir
-	}
-	if tmpDir != "" {
-		if opts.startRev != "" {
-			sha, err := notes.RevParse(opts.startRev, tmpDir)
-			if err != nil {
-				return nil, err
-			}
-			level.Info(logger).Log("msg", "using found start SHA: "+sha)
-			opts.startSHA = sha
-		}
-		if opts.endRev != "" {
-			sha, err := notes.RevParse(opts.endRev, tmpDir)
-			if err != nil {
-				return nil, err
-			}
-			level.Info(logger).Log("msg", "using found end SHA: "+sha)
-			opts.endSHA = sha
-		}
-	}
+	// githubAccept overrides the media type accepted from the GitHub API.
+	flags.StringVar(
+		&o.githubAccept,
+		"github-accept",
+		env.String("GITHUB_ACCEPT", ""),
+		"The media type to accept on all GitHub API requests, like application/vnd.github.symmetra-preview+json for preview features. Defaults to the media types of the GitHub client",
+	)
 
-	// Add appropriate log filtering
-	if opts.debug {
-		logger = level.NewFilter(logger, level.AllowDebug())
-	} else {
-		logger = level.NewFilter(logger, level.AllowInfo())
-	}
-	logger = log.With(logger, "timestamp", log.DefaultTimestamp, "caller", log.DefaultCaller)
-	opts.logger = logger
+	// tokenExpiryWarn is the window before the expiration of the GitHub token
+	// in which a warning is logged.
+	flags.DurationVar(
+		&o.tokenExpiryWarn,
+		"token-expiry-warn",
+		env.Duration("TOKEN_EXPIRY_WARN", 7*24*time.Hour),
+		"Log a warning if the GitHub token expires within this duration, like 168h. Set to 0 to disable the check",
+	)
 
-	return opts, nil
-}
+	// stream renders the markdown without assembling the whole document in
+	// memory first.
+	flags.BoolVar(
+		&o.stream,
+		"stream",
+		env.Bool("STREAM", false),
+		"Render markdown incrementally to keep memory usage low for huge commit ranges",
+	)
 
-func run(logger log.Logger, args []string) error {
-	// Parse the CLI options and enforce required defaults
-	opts, err := parseOptions(args, logger)
-	if err != nil && err.Error() == "version" {
-		fmt.Println("nicolaferraro")
-		return nil
-	} else if err != nil {
-		level.Error(logger).Log("msg", "error parsing options", "err", err)
-		return err
-	}
-	logger = opts.logger
+	// compact omits the blank lines between the notes of the markdown, which
+	// some wikis render as large gaps.
+	flags.BoolVar(
+		&o.compact,
+		"compact",
+		env.Bool("COMPACT", false),
+		"Render the markdown without blank lines between and within the notes of a section. The sections are still separated by a blank line",
+	)
 
-	// get the release notes
-	releaseNotes, err := opts.GetReleaseNotes()
-	if err != nil {
-		return err
-	}
+	// ownershipFile maps directory prefixes to SIGs, which is used to infer
+	// the SIGs of PRs without sig labels.
+	flags.StringVar(
+		&o.ownershipFile,
+		"ownership-file",
+		env.String("OWNERSHIP_FILE", ""),
+		"A YAML file mapping directory prefixes to SIGs, like `pkg/kubelet/: node`. The SIGs of PRs without sig labels are inferred from the files they change, which costs one additional API request per PR",
+	)
 
-	err = opts.WriteReleaseNotes(releaseNotes)
-	if err != nil {
-		level.Error(logger).Log("msg", "error writing to file", "err", err)
-		return err
-	}
+	// inferSIGBudget bounds the API requests spent on the SIG inference.
+	flags.IntVar(
+		&o.inferSIGBudget,
+		"infer-sig-budget",
+		env.Int("INFER_SIG_BUDGET", 0),
+		"Infer the SIGs of at most this many PRs without sig labels and leave the rest uncategorized. Set to 0 for no limit",
+	)
 
-	return nil
-}
+	// inferKind classifies the notes of PRs without kind labels by their
+	// title.
+	flags.BoolVar(
+		&o.inferKind,
+		"infer-kind",
+		env.Bool("INFER_KIND", false),
+		"Classify the notes of PRs without kind labels into feature, bug or deprecation by their title, like \"Fix ...\". The kind is marked as inferred in the JSON output",
+	)
+
+	// kindRulesFile overrides the title heuristics of -infer-kind.
+	flags.StringVar(
+		&o.kindRulesFile,
+		"kind-rules-file",
+		env.String("KIND_RULES_FILE", ""),
+		"A YAML list of title patterns and kinds, like `- {pattern: \"(?i)^fix\", kind: bug}`, which replaces the default heuristics of -infer-kind. The first matching pattern wins",
+	)
+
+	// excludeDocs drops the notes of documentation only PRs.
+	flags.BoolVar(
+		&o.excludeDocs,
+		"exclude-docs",
+		env.Bool("EXCLUDE_DOCS", false),
+		"Drop the notes of documentation only PRs, which carry one of the -docs-labels or, with -docs-paths, only change files below those paths",
+	)
+
+	// docsLabels mark documentation only PRs.
+	flags.StringVar(
+		&o.docsLabels,
+		"docs-labels",
+		env.String("DOCS_LABELS", strings.Join(notes.DefaultDocsLabels, ",")),
+		"Comma separated list of kind, area or sig labels which mark documentation only PRs for -exclude-docs",
+	)
+
+	// docsPaths opts into checking the changed files of every PR.
+	flags.StringVar(
+		&o.docsPaths,
+		"docs-paths",
+		env.String("DOCS_PATHS", ""),
+		"Comma separated list of directory prefixes, like docs/. With -exclude-docs, PRs which only change files below them are dropped as well, which costs one additional API request per PR",
+	)
+
+	// docsPathBudget bounds the API requests spent on the docs path check.
+	flags.IntVar(
+		&o.docsPathBudget,
+		"docs-path-budget",
+		env.Int("DOCS_PATH_BUDGET", 0),
+		"Check the changed files of at most this many PRs for -docs-paths. Set to 0 for no limit",
+	)
+
+	// pathFilter scopes the notes to the PRs changing some paths.
+	flags.StringVar(
+		&o.pathFilter,
+		"path-filter",
+		env.String("PATH_FILTER", ""),
+		"Comma separated list of glob patterns, like pkg/kubelet/ or cmd/*/main.go. Only the notes of PRs which change a file matching one of them, or a file below a matching directory, are kept, which costs one additional API request per PR",
+	)
+
+	// pathFilterBudget bounds the API requests spent on the path filter.
+	flags.IntVar(
+		&o.pathFilterBudget,
+		"path-filter-budget",
+		env.Int("PATH_FILTER_BUDGET", 0),
+		"Check the changed files of at most this many PRs for -path-filter and keep the notes of the remaining ones. Set to 0 for no limit",
+	)
+
+	// topReacted highlights the notes with the most reactions.
+	flags.IntVar(
+		&o.topReacted,
+		"top-reacted",
+		env.Int("TOP_REACTED", 0),
+		"Prepend a section with this many notes whose PRs got the most reactions to the markdown output, which costs one additional API request per PR. Set to 0 to disable",
+	)
+
+	// reactionsBudget bounds the API requests spent on the reactions.
+	flags.IntVar(
+		&o.reactionsBudget,
+		"reactions-budget",
+		env.Int("REACTIONS_BUDGET", 0),
+		"Fetch the reactions of at most this many PRs for -top-reacted. Set to 0 for no limit",
+	)
+
+	// showStats appends the additions and deletions of the PRs to the notes.
+	flags.BoolVar(
+		&o.showStats,
+		"show-stats",
+		env.Bool("SHOW_STATS", false),
+		"Fetch the additions, deletions and changed files of every PR into the stats of its note, and append them to the markdown of the notes, like \"(+120 −30)\". Notes which are not read from the PR cost one additional API request per PR",
+	)
+
+	// statsBudget bounds the API requests spent on the stats.
+	flags.IntVar(
+		&o.statsBudget,
+		"stats-budget",
+		env.Int("STATS_BUDGET", 0),
+		"Fetch the stats of at most this many PRs with an additional API request for -show-stats or -sort-by size. Set to 0 for no limit",
+	)
+
+	// sortBy orders the notes within every section.
+	flags.StringVar(
+		&o.sortBy,
+		"sort-by",
+		env.String("SORT_BY", string(notes.SortByNumber)),
+		"The order of the notes within every section, after -kind-priority (options: number, size). Sorting by size fetches the stats of the PRs like -show-stats and lists the largest changes first",
+	)
+
+	// withinSectionSort orders the notes within every section by several keys.
+	flags.StringVar(
+		&o.withinSectionSort,
+		"within-section-sort",
+		env.String("WITHIN_SECTION_SORT", ""),
+		"Comma separated keys the notes within every section and group, like a SIG, are ordered by, after -kind-priority and before -sort-by, like \"kind,merged-at\" (options: kind, merged-at, number, size). Notes without a value of a key, like notes without a kind, come last",
+	)
+
+	// collapseDeps lists the dependency updates in a collapsed section.
+	flags.BoolVar(
+		&o.collapseDeps,
+		"collapse-deps",
+		env.Bool("COLLAPSE_DEPS", false),
+		"List the notes of dependency updates, which are labeled \"dependencies\" or titled like \"Bump foo from 1.0 to 1.1\", in a single \"Dependency Updates\" section at the end, collapsed behind a summary like \"3 dependencies updated\" in markdown. Security and action required updates keep their sections",
+	)
+
+	// otherSectionTitle is the heading of the notes without a SIG or kind
+	// section.
+	flags.StringVar(
+		&o.otherSectionTitle,
+		"other-section-title",
+		env.String("OTHER_SECTION_TITLE", "Other Notable Changes"),
+		"The title of the last section, which lists the notes that belong to no SIG and have no kind with a section of their own",
+	)
+
+	// patchBuckets groups the markdown by the patch release of the notes.
+	flags.BoolVar(
+		&o.patchBuckets,
+		"patch-buckets",
+		env.Bool("PATCH_BUCKETS", false),
+		"Group the markdown under a heading per release version of the notes, like `## v1.20.1`, for the changelog of a minor series. The version of every fetched note is the first tag of the series of -release-version which contains its commit, resolved in a local clone; the versions of the -ranges-file or -input are kept. Without tags, the notes are rendered as a single release",
+	)
+
+	// pruneEmptySections omits the sections without notes.
+	flags.BoolVar(
+		&o.pruneEmptySections,
+		"prune-empty-sections",
+		env.Bool("PRUNE_EMPTY_SECTIONS", true),
+		"Omit the sections without notes, like the ones emptied by -exclude-prs, from the markdown and HTML output. Set to false to render the headings of all sections, so that every release has the same skeleton",
+	)
+
+	// prNumberRegex overrides how the PR number is found in commit messages.
+	flags.StringVar(
+		&o.prNumberRegex,
+		"pr-number-regex",
+		env.String("PR_NUMBER_REGEX", ""),
+		"A regular expression with a capture group to extract the PR number from commit messages. Defaults to the Kubernetes merge message conventions, like `(#1234)`",
+	)
+
+	// layout selects the arrangement of the sections of the markdown.
+	flags.StringVar(
+		&o.layout,
+		"layout",
+		env.String("LAYOUT", string(notes.LayoutFlat)),
+		"The arrangement of the markdown sections (options: flat, kubernetes). `kubernetes` mirrors the Kubernetes CHANGELOG with urgent upgrade notes and changes by kind",
+	)
+
+	// stableAnchors precedes every heading with an anchor which does not
+	// depend on the heading text.
+	flags.BoolVar(
+		&o.stableAnchors,
+		"stable-anchors",
+		env.Bool("STABLE_ANCHORS", false),
+		"Precede every markdown heading with an anchor derived from the SIG or kind instead of the heading text, so that inbound links survive wording changes",
+	)
+
+	// enforceStyle capitalizes the rendered notes and ends them with a period.
+	flags.BoolVar(
+		&o.enforceStyle,
+		"enforce-style",
+		env.Bool("ENFORCE_STYLE", false),
+		"Render every note starting with a capital letter and ending with a period. The notes in the JSON output are not changed",
+	)
+
+	// reportStyle lists the notes which violate the style instead.
+	flags.BoolVar(
+		&o.reportStyle,
+		"report-style-violations",
+		env.Bool("REPORT_STYLE_VIOLATIONS", false),
+		"Warn about every note which does not start with a capital letter or end with a period, so that the authors can fix them",
+	)
+
+	// markdownFlavor is the markdown dialect of the output.
+	flags.StringVar(
+		&o.markdownFlavor,
+		"markdown-flavor",
+		env.String("MARKDOWN_FLAVOR", string(notes.FlavorGFM)),
+		"The markdown dialect to render for (options: gfm, commonmark). commonmark renders bare URLs as autolinks and restricts the -stable-anchors to ASCII",
+	)
+
+	// noteTemplate renders every note instead of its default markdown.
+	flags.StringVar(
+		&o.noteTemplate,
+		"note-template",
+		env.String("NOTE_TEMPLATE", ""),
+		"A Go template to render every note with, like \"{{.Text}} (#{{.Number}}, @{{.Author}})\". The fields are Number, URL, Text, Markdown, Author, AuthorURL, SIGs, Kinds and Kind",
+	)
+
+	// prLinkFormat is the text of the links to the PRs of the notes.
+	flags.StringVar(
+		&o.prLinkFormat,
+		"pr-link-format",
+		env.String("PR_LINK_FORMAT", notes.DefaultPRLinkFormat),
+		"The text of the links to the PRs of the markdown and HTML notes, like \"PR #{number}\" or \"{org}/{repo}#{number}\". {org}, {repo} and {number} are replaced by the organization, repository and number of the PR",
+	)
+
+	// kindPriority orders the notes within every section by their kind.
+	flags.StringVar(
+		&o.kindPriority,
+		"kind-priority",
+		env.String("KIND_PRIORITY", ""),
+		"Comma separated list of kinds, like `feature,bug`, to order the notes within every section by. Notes without a kind are listed last",
+	)
+
+	// groupBy selects the labels the notes of the flat layout are grouped by.
+	flags.StringVar(
+		&o.groupBy,
+		"group-by",
+		env.String("GROUP_BY", string(notes.GroupBySIG)),
+		"The labels to group the notes of the flat layout by (options: sig, area). `area` lists every note under a single one of its area/* labels, selected by -area-priority, and the notes without an area like the notes without a SIG",
+	)
+
+	// areaPriority selects the area of notes with multiple areas.
+	flags.StringVar(
+		&o.areaPriority,
+		"area-priority",
+		env.String("AREA_PRIORITY", ""),
+		"Comma separated list of areas, like `kubelet,kubectl`, which selects the area a note with multiple area labels is listed under with -group-by area. Areas which are not listed come after the listed ones in alphabetical order",
+	)
+
+	// badges prepends the counts of the notes to the markdown output.
+	flags.StringVar(
+		&o.badges,
+		"badges",
+		env.String("BADGES", ""),
+		"Prepend badges with the note counts per kind to the markdown output (options: shields, static). `static` renders plain text for offline use",
+	)
+
+	// badgeColors overrides the colors of the shields badges.
+	flags.StringVar(
+		&o.badgeColors,
+		"badge-colors",
+		env.String("BADGE_COLORS", ""),
+		"Comma separated list of kind=color pairs overriding the badge colors, like `feature=brightgreen,total=blue`",
+	)
+
+	// contributors appends a section listing all authors and co-authors.
+	flags.BoolVar(
+		&o.contributors,
+		"contributors",
+		env.Bool("CONTRIBUTORS", false),
+		"Append a section listing all authors and co-authors to the markdown output",
+	)
+
+	// indexMarkdown is the markdown document indexed by -format index-json.
+	flags.StringVar(
+		&o.indexMarkdown,
+		"index-markdown",
+		env.String("INDEX_MARKDOWN", ""),
+		"The file which -format index-json writes the markdown notes to. The JSON index lists the sections of this file with their anchors, note counts and byte ranges",
+	)
+
+	// contributorsFormat is the format of the -format contributors report.
+	flags.StringVar(
+		&o.contributorsFormat,
+		"contributors-format",
+		env.String("CONTRIBUTORS_FORMAT", "markdown"),
+		"The format of the report written by -format contributors (options: markdown, json)",
+	)
+
+	// flagFirstTime marks the contributors without earlier merged PRs.
+	flags.BoolVar(
+		&o.flagFirstTime,
+		"flag-first-time",
+		env.Bool("FLAG_FIRST_TIME", false),
+		"Flag the contributors who had no PR merged before in the -format contributors report. Uses one search API request per contributor",
+	)
+
+	// labelLegend appends a legend of the label colors to the HTML output.
+	flags.BoolVar(
+		&o.labelLegend,
+		"label-legend",
+		env.Bool("LABEL_LEGEND", false),
+		"Append a legend of the labels of the notes, colored like on GitHub, to the HTML output",
+	)
+
+	// maxNoteLength warns about notes which are longer than this.
+	flags.IntVar(
+		&o.maxNoteLength,
+		"max-note-length",
+		env.Int("MAX_NOTE_LENGTH", 0),
+		"Warn about notes whose text is longer than this number of characters. Set to 0 to disable",
+	)
+
+	// strictNotes turns the -max-note-length warnings into an error.
+	flags.BoolVar(
+		&o.strictNotes,
+		"strict-notes",
+		env.Bool("STRICT_NOTES", false),
+		"Fail instead of warn if a note exceeds -max-note-length",
+	)
+
+	// failOnUnlabeled fails if notes lack one of the -require-labels.
+	flags.BoolVar(
+		&o.failOnUnlabeled,
+		"fail-on-unlabeled",
+		env.Bool("FAIL_ON_UNLABELED", false),
+		"Fail if any note lacks a label of the dimensions of -require-labels. The PRs are listed to stderr, one per line as the tab separated PR number, missing dimensions and PR URL",
+	)
+
+	// requireLabels are the label dimensions checked by -fail-on-unlabeled.
+	flags.StringVar(
+		&o.requireLabels,
+		"require-labels",
+		env.String("REQUIRE_LABELS", "sig,kind"),
+		fmt.Sprintf("Comma separated label dimensions which every note must have a label of with -fail-on-unlabeled (options: %s)", strings.Join(labelDimensions, ", ")),
+	)
+
+	// wrapNote soft-wraps the markdown of the notes.
+	flags.IntVar(
+		&o.wrapNote,
+		"wrap-note",
+		env.Int("WRAP_NOTE", 0),
+		"Soft-wrap the markdown of every note at this column. Set to 0 to disable",
+	)
+
+	// annotateNew marks the notes which were not part of the merged JSON
+	// output of a previous run.
+	flags.BoolVar(
+		&o.annotateNew,
+		"annotate-new",
+		env.Bool("ANNOTATE_NEW", false),
+		"Mark the notes which are new compared to the existing JSON output file, so that they are highlighted when rendered to markdown",
+	)
+
+	// maxFileBytes splits outputs for size constrained sinks.
+	flags.IntVar(
+		&o.maxFileBytes,
+		"max-file-bytes",
+		env.Int("MAX_FILE_BYTES", 0),
+		"Split an -output larger than this many bytes into the files <output>.1, <output>.2, etc. at the boundaries of its sections, or of its notes for -format json, and replace it with the index <output>.index.json of the parts. Every part gets its own -checksum file, and the parts of a JSON output are merged like the whole file by the next run. Set to 0 to never split",
+	)
+
+	// markdownOutput renders the JSON output to markdown as well.
+	flags.StringVar(
+		&o.markdownOutput,
+		"markdown-output",
+		env.String("MARKDOWN_OUTPUT", ""),
+		"With -format json, also render all notes of the JSON -output, including the merged ones, to this markdown file",
+	)
+
+	// renderOnlyChanged speeds up the rendering of huge documents.
+	flags.BoolVar(
+		&o.renderOnlyChanged,
+		"render-only-changed",
+		env.Bool("RENDER_ONLY_CHANGED", false),
+		"Only rewrite the sections of the existing -markdown-output whose notes changed in the JSON -output, which are found by their -stable-anchors. The whole document is rendered if the markdown has no anchors or a new section appears",
+	)
+
+	// validateOutput validates the JSON output against the embedded schema.
+	flags.BoolVar(
+		&o.validateOutput,
+		"validate-output",
+		env.Bool("VALIDATE_OUTPUT", false),
+		"Validate the JSON output against the release notes JSON schema",
+	)
+
+	// createRelease publishes the markdown as the body of the GitHub release
+	// of the release version.
+	flags.BoolVar(
+		&o.createRelease,
+		"create-release",
+		env.Bool("CREATE_RELEASE", false),
+		"Create or update the GitHub release of the -release-version tag with the markdown notes as body. Requires a token with write access",
+	)
+
+	// releaseDraft creates the release as draft, which is not visible to the
+	// public until it is published.
+	flags.BoolVar(
+		&o.releaseDraft,
+		"release-draft",
+		env.Bool("RELEASE_DRAFT", false),
+		"Mark the release created by -create-release as draft",
+	)
+
+	// releasePrerelease marks the created release as not production ready.
+	flags.BoolVar(
+		&o.releasePrerelease,
+		"release-prerelease",
+		env.Bool("RELEASE_PRERELEASE", false),
+		"Mark the release created by -create-release as prerelease",
+	)
+
+	// createDiscussion publishes the markdown as a GitHub Discussion of the
+	// release version.
+	flags.BoolVar(
+		&o.createDiscussion,
+		"create-discussion",
+		env.Bool("CREATE_DISCUSSION", false),
+		"Create a GitHub Discussion titled -release-version with the markdown notes as body via the GraphQL API, or update the body of the discussion with this title. Requires a token with write access",
+	)
+
+	// discussionRepo is the repository of the created discussion.
+	flags.StringVar(
+		&o.discussionRepo,
+		"discussion-repo",
+		env.String("DISCUSSION_REPO", ""),
+		"The org/repo of the discussion created by -create-discussion, like kubernetes/community. Defaults to -github-org and -github-repo",
+	)
+
+	// discussionCategory is the category of the created discussion.
+	flags.StringVar(
+		&o.discussionCategory,
+		"discussion-category",
+		env.String("DISCUSSION_CATEGORY", "Announcements"),
+		"The name of the discussion category of the discussion created by -create-discussion",
+	)
+
+	// checksum writes the SHA256 digest of the output next to it.
+	flags.BoolVar(
+		&o.checksum,
+		"checksum",
+		env.Bool("CHECKSUM", false),
+		"Write the SHA256 digest of the output to a sibling .sha256 file. Without -output, the digest is printed to stderr",
+	)
+
+	// outputEncoding and outputBOM accommodate legacy consumers which cannot
+	// read plain UTF-8.
+	flags.StringVar(
+		&o.outputEncoding,
+		"output-encoding",
+		env.String("OUTPUT_ENCODING", encodingUTF8),
+		"The character encoding of the output (options: utf-8, iso-8859-1). Characters which iso-8859-1 cannot represent are replaced by a question mark and counted in a warning",
+	)
+	flags.BoolVar(
+		&o.outputBOM,
+		"output-bom",
+		env.Bool("OUTPUT_BOM", false),
+		"Precede the UTF-8 output with a byte order mark",
+	)
+
+	// warningsFile collects the warnings of the run for their authors.
+	flags.StringVar(
+		&o.warningsFile,
+		"warnings-file",
+		env.String("WARNINGS_FILE", ""),
+		"Write every warning of the run, like malformed notes, skipped commits and unlabeled PRs, to this file as a JSON array of objects with the PR number or commit, the category and the message, in addition to logging them",
+	)
+
+	// verifyLinks checks that all links of the output are reachable.
+	flags.BoolVar(
+		&o.verifyLinks,
+		"verify-links",
+		env.Bool("VERIFY_LINKS", false),
+		"Send a HEAD request to every link of the rendered output and fail if any does not respond with a 2xx status. The broken links are listed to stderr, one per line as the tab separated status and URL. The requests are bounded by -host-concurrency",
+	)
+
+	// interactive asks for every note whether it should be kept, skipped or
+	// edited before writing the release notes.
+	flags.BoolVar(
+		&o.interactive,
+		"interactive",
+		false,
+		"Review every note on the terminal before writing the release notes",
+	)
+
+	// preview prints a human readable version of the notes to stderr.
+	flags.BoolVar(
+		&o.preview,
+		"preview",
+		env.Bool("PREVIEW", false),
+		"Print a colorized preview of the release notes to stderr, with the time since every note was merged and its merge date in -date-format, like \"merged 3 days ago on Jan 7, 2019\". Colors are disabled if stderr is not a terminal or NO_COLOR is set",
+	)
+
+	flags.BoolVar(
+		&o.debug,
+		"debug",
+		env.Bool("DEBUG", false),
+		"Enable debug logging",
+	)
+
+	// verbosity traces why every commit does or does not result in a note.
+	flags.IntVar(
+		&o.verbosity,
+		"v",
+		env.Int("VERBOSITY", 0),
+		"Trace the processing of every commit at the debug level, which -v implies: 1 logs the decision taken for every commit, like why it was skipped, and 2 every check it went through, like the matched author and the release note block of its PR. The trace never contains the token or the PR bodies",
+	)
+
+	flags.BoolVar(
+		&o.quiet,
+		"quiet",
+		env.Bool("QUIET", false),
+		"Only log errors. Cannot be combined with -debug or -v",
+	)
+
+	// color governs the colors of both the logs and the preview.
+	flags.StringVar(
+		&o.color,
+		"color",
+		env.String("COLOR", colorAuto),
+		"When to colorize the logs and the preview (options: auto, always, never). With auto, colors are used if stderr is a terminal and NO_COLOR is not set",
+	)
+
+	// dateFormat is the layout of all rendered dates.
+	flags.StringVar(
+		&o.dateFormat,
+		"date-format",
+		env.String("DATE_FORMAT", ""),
+		"The Go reference time layout of all rendered dates, like \"2006-01-02 15:04\" for the {date} placeholder of -output, the preview and the logs. Defaults to 2006-01-02 for -output, \"Jan 2, 2006\" for the preview and RFC 3339 for the logs",
+	)
+
+	flags.BoolVar(
+		&o.version,
+		"version",
+		false,
+		"Print version information",
+	)
+
+	// the prefixed environment variables take precedence over the legacy
+	// unprefixed ones, and fail like the invalid values of those
+	if err := applyPrefixedEnv(flags); err != nil {
+		fmt.Fprintf(os.Stderr, "env: %v\n", err)
+		os.Exit(1)
+	}
+
+	return flags
+}
+
+// ReadInputReleaseNotes merges the release notes of the -input files, which
+// works without access to GitHub.
+func (o *options) ReadInputReleaseNotes() (notes.ReleaseNoteList, error) {
+	lists := []notes.ReleaseNoteList{}
+	for _, path := range strings.Split(o.input, ",") {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+		list, err := notes.ReadReleaseNotes(path)
+		if err != nil {
+			level.Error(o.logger).Log("msg", "error reading input release notes", "err", err)
+			return nil, err
+		}
+		lists = append(lists, list)
+	}
+
+	releaseNotes, err := notes.CombineReleaseNotes(lists, notes.MergeStrategy(o.mergeStrategy))
+	if err != nil {
+		level.Error(o.logger).Log("msg", "error merging input release notes", "err", err)
+		return nil, err
+	}
+	level.Info(o.logger).Log("msg", "merged input release notes", "files", len(lists), "notes", len(releaseNotes))
+
+	// the token is only needed to publish the release or discussion
+	if o.createRelease || o.createDiscussion {
+		ctx := context.Background()
+		client, err := o.newGithubClient(ctx)
+		if err != nil {
+			level.Error(o.logger).Log("msg", "error creating the GitHub client", "err", err)
+			return nil, err
+		}
+		if err := o.preflight(ctx, client); err != nil {
+			level.Error(o.logger).Log("msg", "preflight check of the GitHub token failed", "err", err)
+			return nil, err
+		}
+	}
+
+	if o.patchBuckets && o.rangesFile == "" {
+		o.resolvePatchVersions(releaseNotes)
+	}
+
+	if err := o.filterReleaseNotes(releaseNotes); err != nil {
+		return nil, err
+	}
+	return releaseNotes, nil
+}
+
+func (o *options) GetReleaseNotes() (notes.ReleaseNoteList, error) {
+	// Create the GitHub API client
+	ctx := context.Background()
+	if o.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.timeout)
+		defer cancel()
+	}
+	githubClient, err := o.newGithubClient(ctx)
+	if err != nil {
+		level.Error(o.logger).Log("msg", "error creating the GitHub client", "err", err)
+		return nil, err
+	}
+
+	if err := o.preflight(ctx, githubClient); err != nil {
+		level.Error(o.logger).Log("msg", "preflight check of the GitHub token failed", "err", err)
+		return nil, err
+	}
+
+	// Fetch a list of fully-contextualized release notes
+	level.Info(o.logger).Log("msg", "fetching all commits. this might take a while...")
+
+	opts := o.githubApiOptions(ctx)
+
+	if err := o.resolveRange(githubClient, opts...); err != nil {
+		return nil, err
+	}
+
+	// ranges taken from another branch silently yield the wrong notes
+	if err := notes.VerifyRangeOnBranch(githubClient, o.branch, o.startSHA, o.endSHA, opts...); err != nil {
+		level.Error(o.logger).Log("msg", "the commit range is not part of the branch", "err", err)
+		return nil, err
+	}
+
+	if o.requiredTeam != "" {
+		members, err := o.requiredTeamMembers(githubClient, opts...)
+		if err != nil {
+			level.Error(o.logger).Log("msg", "error resolving the members of the required team", "err", err)
+			return nil, err
+		}
+		o.requiredAuthor = ""
+		opts = append(opts, notes.WithRequiredAuthors(members))
+	}
+
+	gapNotes, err := o.checkCoverageGap(githubClient, opts...)
+	if err != nil {
+		level.Error(o.logger).Log("msg", "error checking the range of the existing notes", "err", err)
+		return nil, err
+	}
+
+	progress := make(chan notes.Progress, 100)
+	done := make(chan struct{})
+	go func() {
+		logProgress(o.logger, progress)
+		close(done)
+	}()
+	opts = append(opts, notes.WithProgress(progress))
+
+	// the range was verified before the coverage gap was checked
+	releaseNotes, err := notes.GenerateReleaseNotes(ctx, githubClient, notes.GenerateOptions{
+		Logger:         o.logger,
+		Branch:         o.branch,
+		StartSHA:       o.startSHA,
+		EndSHA:         o.endSHA,
+		RequiredAuthor: o.requiredAuthor,
+		ReleaseVersion: o.releaseVersion,
+		Filter: func(releaseNotes notes.ReleaseNoteList) error {
+			for number, note := range gapNotes {
+				if _, ok := releaseNotes[number]; !ok {
+					releaseNotes[number] = note
+				}
+			}
+			return o.filterReleaseNotes(releaseNotes)
+		},
+		GithubApiOptions: opts,
+	})
+	close(progress)
+	<-done
+	if err != nil {
+		level.Error(o.logger).Log("msg", "error generating release notes", "err", err)
+		return nil, err
+	}
+	return releaseNotes, nil
+}
+
+// githubApiOptions returns the options of the GitHub API requests which fetch
+// the notes
+func (o *options) githubApiOptions(ctx context.Context) []notes.GithubApiOption {
+	opts := []notes.GithubApiOption{notes.WithContext(ctx)}
+	if o.githubOrg != "" {
+		opts = append(opts, notes.WithOrg(o.githubOrg))
+	}
+	if o.githubRepo != "" {
+		opts = append(opts, notes.WithRepo(o.githubRepo))
+	}
+	if o.includeDescription {
+		opts = append(opts, notes.WithDescription(o.descriptionMaxChars))
+	}
+	if o.verbosity > 0 {
+		opts = append(opts, notes.WithVerbosity(o.verbosity))
+	}
+	if o.warnings != nil {
+		opts = append(opts, notes.WithWarnings(o.warnings))
+	}
+	if o.linkIssues {
+		opts = append(opts, notes.WithLinkIssues())
+	}
+	if o.includeSubtasks {
+		opts = append(opts, notes.WithSubtasks())
+	}
+	if o.resolveSeeTitle {
+		opts = append(opts, notes.WithResolveSeeTitle(splitList(o.seeTitlePhrases)))
+	}
+	if o.maxRangeCommits > 0 {
+		opts = append(opts, notes.WithMaxRangeCommits(o.maxRangeCommits))
+	}
+	opts = append(opts, notes.WithNoteSource(notes.NoteSource(o.noteSource)))
+	opts = append(opts, notes.WithAuthorField(notes.AuthorField(o.authorField)))
+	opts = append(opts, notes.WithSecurityLabels(splitList(o.securityLabels)))
+	opts = append(opts, notes.WithNormalizeWhitespace(o.normalize))
+	if o.requireMerged {
+		opts = append(opts, notes.WithRequireMerged())
+	}
+	if o.wrapNote > 0 {
+		opts = append(opts, notes.WithWrapNotes(o.wrapNote))
+	}
+	if o.trackBranches != "" {
+		opts = append(opts, notes.WithTrackBranches(strings.Split(o.trackBranches, ",")))
+	}
+	if o.suppressReverted {
+		opts = append(opts, notes.WithSuppressRevertedInRange())
+	}
+	if o.dedupeIdentical {
+		opts = append(opts, notes.WithDedupeIdenticalText())
+	}
+	if o.inferKind {
+		opts = append(opts, notes.WithInferKind(o.kindRules))
+	}
+	if len(o.sigOwners) > 0 {
+		opts = append(opts, notes.WithSIGOwnership(o.sigOwners))
+		if o.inferSIGBudget > 0 {
+			opts = append(opts, notes.WithInferSIGBudget(o.inferSIGBudget))
+		}
+	}
+	if o.excludeDocs {
+		opts = append(opts, notes.WithExcludeDocs(splitList(o.docsLabels)))
+		if paths := splitList(o.docsPaths); len(paths) > 0 {
+			opts = append(opts, notes.WithDocsPaths(paths, o.docsPathBudget))
+		}
+	}
+	if patterns := splitList(o.pathFilter); len(patterns) > 0 {
+		opts = append(opts, notes.WithPathFilter(patterns, o.pathFilterBudget))
+	}
+	if o.topReacted > 0 {
+		opts = append(opts, notes.WithReactions(o.reactionsBudget))
+	}
+	if o.showStats || notes.NoteOrder(o.sortBy) == notes.SortBySize || o.sortsBySize() {
+		opts = append(opts, notes.WithStats(o.statsBudget))
+	}
+	if o.checkpointFile != "" {
+		opts = append(opts, notes.WithCheckpoint(o.checkpointFile, o.checkpointInterval))
+	}
+	if o.prNumberRegex != "" {
+		opts = append(opts, notes.WithPRNumberRegex(regexp.MustCompile(o.prNumberRegex)))
+	}
+	return opts
+}
+
+// resolveRange discovers the range of the release branch or computes the
+// merge-base as start, if requested
+func (o *options) resolveRange(client *github.Client, opts ...notes.GithubApiOption) error {
+	if o.discoverRange {
+		if err := o.discoverReleaseBranchRange(client, opts...); err != nil {
+			level.Error(o.logger).Log("msg", "error discovering the release branch range", "err", err)
+			return err
+		}
+	}
+
+	// the merge-base was computed locally if the repository was cloned
+	if o.baseRef != "" && o.startSHA == "" {
+		sha, err := notes.MergeBase(client, o.baseRef, o.branch, opts...)
+		if err != nil {
+			level.Error(o.logger).Log("msg", "error computing the merge-base", "err", err)
+			return err
+		}
+		level.Info(o.logger).Log("msg", "using merge-base as start SHA", "base", o.baseRef, "branch", o.branch, "sha", sha)
+		o.startSHA = sha
+	}
+	return nil
+}
+
+// filterReleaseNotes drops the notes of the excluded PRs and authors and
+// checks the note lengths, no matter whether the notes were fetched or read
+// from -input
+func (o *options) filterReleaseNotes(releaseNotes notes.ReleaseNoteList) error {
+	for number, note := range releaseNotes {
+		if o.excludeNotePRs(note) {
+			level.Info(o.logger).Log("msg", "excluding release note", "pr", number)
+			delete(releaseNotes, number)
+		}
+	}
+
+	o.excludeByTitle(releaseNotes)
+
+	for _, author := range splitList(o.excludeAuthor) {
+		for number, note := range releaseNotes {
+			if strings.EqualFold(note.Author, author) {
+				level.Info(o.logger).Log("msg", "excluding release note of author", "pr", number, "author", note.Author)
+				delete(releaseNotes, number)
+			}
+		}
+	}
+
+	if o.reportStyle {
+		o.reportStyleViolations(releaseNotes)
+	}
+
+	if o.failOnUnlabeled {
+		if err := o.checkLabels(releaseNotes); err != nil {
+			return err
+		}
+	}
+
+	if o.maxNoteLength > 0 {
+		return o.checkNoteLengths(releaseNotes)
+	}
+	return nil
+}
+
+// excludeNotePRs reports whether the note belongs to one of the -exclude-prs.
+// The excluded PRs are dropped from the PRs of a collapsed note, whose
+// markdown is regenerated without their links.
+func (o *options) excludeNotePRs(note *notes.ReleaseNote) bool {
+	excluded := map[int]bool{}
+	for _, number := range o.excludedPRs {
+		excluded[number] = true
+	}
+	if excluded[note.PrNumber] {
+		return true
+	}
+
+	kept := []int{}
+	for _, number := range note.PrNumbers {
+		if excluded[number] {
+			level.Info(o.logger).Log("msg", "excluding collapsed PR", "pr", note.PrNumber, "collapsed", number)
+			continue
+		}
+		kept = append(kept, number)
+	}
+	if len(kept) == len(note.PrNumbers) {
+		return false
+	}
+	if len(kept) < 2 {
+		kept = nil
+	}
+	note.PrNumbers = kept
+	note.Markdown = o.noteMarkdown(note)
+	return false
+}
+
+// excludeKeyedNotes drops the notes of the -exclude-prs from the notes of a
+// previous run, which are merged into the JSON output. Only the notes of the
+// -github-org and -github-repo, or the ones without PR URL, are matched.
+func (o *options) excludeKeyedNotes(keyed notes.KeyedReleaseNotes) {
+	for key, note := range keyed {
+		if repository := key[:strings.LastIndex(key, "#")]; repository != "" && repository != o.githubOrg+"/"+o.githubRepo {
+			continue
+		}
+		if o.excludeNotePRs(note) {
+			level.Info(o.logger).Log("msg", "excluding existing release note", "key", key)
+			delete(keyed, key)
+		}
+	}
+}
+
+// checkLabels lists the notes which lack one of the -require-labels to stderr
+// and fails if there are any
+func (o *options) checkLabels(releaseNotes notes.ReleaseNoteList) error {
+	dimensions := splitList(o.requireLabels)
+	for number, note := range releaseNotes {
+		if missing := missingLabels(note, dimensions); len(missing) > 0 {
+			o.warnings.Add(notes.Warning{
+				PrNumber: number,
+				Category: notes.WarningUnlabeled,
+				Message:  "the PR lacks a label of " + strings.Join(missing, ", "),
+			})
+		}
+	}
+	unlabeled, err := writeUnlabeled(releaseNotes, dimensions, os.Stderr)
+	if err != nil {
+		return err
+	}
+	if unlabeled > 0 {
+		return fmt.Errorf("the release notes of %d PRs lack a label of %s", unlabeled, strings.Join(dimensions, ", "))
+	}
+	return nil
+}
+
+// reportStyleViolations warns about every note which does not conform to the
+// style of -enforce-style
+func (o *options) reportStyleViolations(releaseNotes notes.ReleaseNoteList) {
+	numbers := []int{}
+	for number := range releaseNotes {
+		numbers = append(numbers, number)
+	}
+	sort.Ints(numbers)
+
+	for _, number := range numbers {
+		note := releaseNotes[number]
+		violations := notes.StyleViolations(note.Text)
+		if len(violations) == 0 {
+			continue
+		}
+		level.Warn(o.logger).Log(
+			"msg", "release note violates the style",
+			"pr", number,
+			"author", note.Author,
+			"violations", strings.Join(violations, ", "),
+		)
+		o.warnings.Add(notes.Warning{
+			PrNumber: number,
+			Category: notes.WarningStyle,
+			Message:  "the note violates the style: " + strings.Join(violations, ", "),
+		})
+	}
+}
+
+// noteMarkdown regenerates the markdown of a note, like after its text was
+// edited, in the same way as it was created when the notes were fetched
+func (o *options) noteMarkdown(note *notes.ReleaseNote) string {
+	return notes.RegenerateMarkdown(note, o.githubApiOptions(context.Background())...)
+}
+
+// checkNoteLengths warns about every note whose text exceeds the maximum
+// length, or fails with -strict-notes
+func (o *options) checkNoteLengths(releaseNotes notes.ReleaseNoteList) error {
+	tooLong := []string{}
+	for number, note := range releaseNotes {
+		length := utf8.RuneCountInString(note.Text)
+		if length <= o.maxNoteLength {
+			continue
+		}
+		level.Warn(o.logger).Log(
+			"msg", "release note exceeds the maximum length",
+			"pr", number,
+			"length", length,
+			"max", o.maxNoteLength,
+		)
+		o.warnings.Add(notes.Warning{
+			PrNumber: number,
+			Category: notes.WarningTooLong,
+			Message:  fmt.Sprintf("the note of %d characters exceeds the maximum length of %d characters", length, o.maxNoteLength),
+		})
+		tooLong = append(tooLong, "#"+strconv.Itoa(number))
+	}
+
+	if o.strictNotes && len(tooLong) > 0 {
+		sort.Strings(tooLong)
+		return fmt.Errorf("the release notes of %s exceed the maximum length of %d characters", strings.Join(tooLong, ", "), o.maxNoteLength)
+	}
+	return nil
+}
+
+// discoverReleaseBranchRange sets the branch to the release branch of the
+// release version and the commit range to the commits since it forked from
+// the previously set branch. SHAs which were set explicitly are kept.
+func (o *options) discoverReleaseBranchRange(client *github.Client, opts ...notes.GithubApiOption) error {
+	branch, err := notes.ReleaseBranchName(o.releaseVersion, o.branchPattern)
+	if err != nil {
+		return err
+	}
+
+	start, end, err := notes.ReleaseBranchRange(client, o.branch, branch, opts...)
+	if err != nil {
+		return err
+	}
+	level.Info(o.logger).Log(
+		"msg", "discovered release branch range",
+		"branch", branch,
+		"base", o.branch,
+		"start", start,
+		"end", end,
+	)
+
+	o.branch = branch
+	if o.startSHA == "" {
+		o.startSHA = start
+	}
+	if o.endSHA == "" {
+		o.endSHA = end
+	}
+	return nil
+}
+
+// transport returns the http.RoundTripper for the GitHub API requests. The
+// concurrency limit wraps the timeout, so that waiting for a free slot never
+// counts towards -request-timeout, and every retry waits for a slot again.
+func (o *options) transport() http.RoundTripper {
+	transport := http.DefaultTransport
+	if o.requestTimeout > 0 {
+		transport = notes.NewTimeoutTransport(transport, o.requestTimeout)
+	}
+	if o.hostConcurrency > 0 {
+		transport = notes.NewConcurrencyTransport(transport, o.hostConcurrency)
+	}
+	if o.retry5xx {
+		transport = notes.NewRetryTransport(transport, o.maxRetries, o.logger)
+	}
+	if o.githubAccept != "" {
+		transport = notes.NewAcceptTransport(transport, o.githubAccept)
+	}
+	return transport
+}
+
+// allowedHosts returns the hostnames which the GitHub token may be sent to
+func (o *options) allowedHosts() []string {
+	hosts := append([]string{}, notes.DefaultAllowedHosts...)
+	for _, endpoint := range []string{o.githubBaseURL, o.githubGraphQLURL} {
+		// the URLs were validated when parsing the options
+		if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+			hosts = append(hosts, u.Hostname())
+		}
+	}
+	return append(hosts, splitList(o.githubHostAllowlist)...)
+}
+
+// newGithubClient creates a GitHub API client which authenticates with the
+// token and retries or times out requests as configured. It talks to the
+// GitHub Enterprise instance at -github-base-url, if set, and refuses to send
+// the token to any host but the allowed ones.
+func (o *options) newGithubClient(ctx context.Context) (*github.Client, error) {
+	// the client of a batch run is shared by all releases
+	if o.githubClient != nil {
+		return o.githubClient, nil
+	}
+
+	transport := notes.NewHostAllowlistTransport(o.transport(), o.allowedHosts())
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: transport})
+	httpClient := oauth2.NewClient(ctx, oauth2.StaticTokenSource(
+		&oauth2.Token{AccessToken: o.githubToken},
+	))
+	if o.githubBaseURL != "" {
+		return github.NewEnterpriseClient(o.githubBaseURL, o.githubBaseURL, httpClient)
+	}
+	return github.NewClient(httpClient), nil
+}
+
+// preflight checks the GitHub token before any release notes are fetched. It
+// warns if the token expires soon and fails if -create-release or
+// -create-discussion is set but the token lacks the scope to write releases or
+// discussions.
+func (o *options) preflight(ctx context.Context, client *github.Client) error {
+	if o.tokenExpiryWarn <= 0 && !o.createRelease && !o.createDiscussion && o.requiredTeam == "" {
+		return nil
+	}
+
+	_, resp, err := client.RateLimits(ctx)
+	if err != nil {
+		if o.createRelease || o.createDiscussion {
+			return fmt.Errorf("unable to check the GitHub token: %v", err)
+		}
+		level.Warn(o.logger).Log("msg", "unable to check the GitHub token expiration", "err", err)
+		return nil
+	}
+
+	if o.tokenExpiryWarn > 0 {
+		o.checkTokenExpiry(resp.Header)
+	}
+
+	if o.createRelease {
+		// fine-grained tokens do not report their permissions up front
+		scopes, known := notes.TokenScopes(resp.Header)
+		if known && !notes.HasString(scopes, "repo") && !notes.HasString(scopes, "public_repo") {
+			return errors.New("-create-release requires a GitHub token with the repo or public_repo scope")
+		}
+	}
+
+	if o.createDiscussion {
+		scopes, known := notes.TokenScopes(resp.Header)
+		if known && !notes.HasString(scopes, "repo") && !notes.HasString(scopes, "public_repo") {
+			return errors.New("-create-discussion requires a GitHub token with the repo or public_repo scope")
+		}
+	}
+
+	if o.requiredTeam != "" {
+		scopes, known := notes.TokenScopes(resp.Header)
+		if known && !notes.HasString(scopes, "read:org") && !notes.HasString(scopes, "write:org") && !notes.HasString(scopes, "admin:org") {
+			return errors.New("-required-team requires a GitHub token with the read:org scope")
+		}
+	}
+	return nil
+}
+
+// checkTokenExpiry warns if the GitHub token expires within the configured
+// window. Failures are only logged, because the actual requests will surface
+// any problem with the token anyway.
+func (o *options) checkTokenExpiry(header http.Header) {
+	expiration, err := notes.TokenExpiration(header)
+	if err != nil {
+		level.Warn(o.logger).Log("msg", "unable to check the GitHub token expiration", "err", err)
+		return
+	}
+	if expiration.IsZero() {
+		return
+	}
+
+	level.Debug(o.logger).Log("msg", "GitHub token expiration", "expires", o.formatDate(expiration, machineDateLayout))
+	if remaining := time.Until(expiration); remaining < o.tokenExpiryWarn {
+		level.Warn(o.logger).Log(
+			"msg", "GitHub token expires soon, rotate it to not block the release",
+			"expires", o.formatDate(expiration, machineDateLayout),
+			"remaining", remaining.Round(time.Minute),
+		)
+	}
+}
+
+// checkLinks requests every link of the rendered output, lists the broken ones
+// to stderr and fails if there are any. The GitHub token is never sent along.
+func (o *options) checkLinks(output *os.File) error {
+	if _, err := output.Seek(0, 0); err != nil {
+		return err
+	}
+	content, err := ioutil.ReadAll(output)
+	if err != nil {
+		return err
+	}
+
+	links := extractLinks(content)
+	level.Info(o.logger).Log("msg", "verifying the links of the release notes", "links", len(links))
+	broken := verifyLinks(&http.Client{Transport: o.transport()}, links)
+	if len(broken) == 0 {
+		return nil
+	}
+
+	if err := writeBrokenLinks(broken, os.Stderr); err != nil {
+		return err
+	}
+	return fmt.Errorf("%d of %d links are broken", len(broken), len(links))
+}
+
+// requiredTeamMembers resolves the logins of the members of the -required-team
+// once per run
+func (o *options) requiredTeamMembers(client *github.Client, opts ...notes.GithubApiOption) ([]string, error) {
+	org, slug := o.githubOrg, o.requiredTeam
+	if parts := strings.SplitN(o.requiredTeam, "/", 2); len(parts) == 2 {
+		org, slug = parts[0], parts[1]
+	}
+
+	members, err := notes.TeamMembers(client, org, slug, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		return nil, fmt.Errorf("the team %s/%s has no members", org, slug)
+	}
+	level.Info(o.logger).Log("msg", "resolved the members of the required team", "team", org+"/"+slug, "members", len(members))
+	return members, nil
+}
+
+// logProgress logs the progress events of ListReleaseNotes until the channel
+// is closed
+func logProgress(logger log.Logger, progress <-chan notes.Progress) {
+	for event := range progress {
+		switch event.Stage {
+		case notes.ProgressCommits:
+			level.Info(logger).Log("msg", "fetched commits", "page", event.Page, "pages", event.Pages, "commits", event.Commits)
+		case notes.ProgressNotes:
+			level.Debug(logger).Log("msg", "processing commits", "processed", event.Processed, "commits", event.Commits, "notes", event.Notes)
+		}
+	}
+}
+
+// discussionRepository returns the org and repo of -create-discussion
+func (o *options) discussionRepository() (string, string) {
+	if parts := strings.Split(o.discussionRepo, "/"); len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+	return o.githubOrg, o.githubRepo
+}
+
+// renderedBody returns the rendered notes, which are published as the body of
+// the release or discussion. The output was truncated before rendering, so it
+// only contains the notes of this run.
+func renderedBody(output *os.File) ([]byte, error) {
+	if _, err := output.Seek(0, 0); err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(output)
+}
+
+// sortsBySize returns whether the size is one of the -within-section-sort keys,
+// which requires the stats of the PRs
+func (o *options) sortsBySize() bool {
+	for _, key := range o.parsedSortKeys {
+		if key == notes.SortKeySize {
+			return true
+		}
+	}
+	return false
+}
+
+// documentOptions returns the options to organize the notes in the rendered
+// documents
+func (o *options) documentOptions() []notes.DocumentOption {
+	opts := []notes.DocumentOption{notes.WithLayout(notes.DocumentLayout(o.layout))}
+	if o.kindPriority != "" {
+		kinds := []string{}
+		for _, kind := range strings.Split(o.kindPriority, ",") {
+			if kind = strings.TrimSpace(kind); kind != "" {
+				kinds = append(kinds, kind)
+			}
+		}
+		opts = append(opts, notes.WithKindPriority(kinds))
+	}
+	opts = append(opts, notes.WithGroupBy(notes.NoteGrouping(o.groupBy)))
+	if o.areaPriority != "" {
+		areas := []string{}
+		for _, area := range strings.Split(o.areaPriority, ",") {
+			if area = strings.TrimSpace(area); area != "" {
+				areas = append(areas, area)
+			}
+		}
+		opts = append(opts, notes.WithAreaPriority(areas))
+	}
+	if o.stableAnchors {
+		opts = append(opts, notes.WithStableAnchors())
+	}
+	if o.enforceStyle {
+		opts = append(opts, notes.WithEnforceStyle())
+	}
+	opts = append(opts, notes.WithMarkdownFlavor(notes.MarkdownFlavor(o.markdownFlavor)))
+	if o.showStats {
+		opts = append(opts, notes.WithShowStats())
+	}
+	opts = append(opts, notes.WithSortBy(notes.NoteOrder(o.sortBy)))
+	if len(o.parsedSortKeys) > 0 {
+		opts = append(opts, notes.WithSortKeys(o.parsedSortKeys))
+	}
+	if o.collapseDeps {
+		opts = append(opts, notes.WithCollapseDependencies())
+	}
+	if !o.pruneEmptySections {
+		opts = append(opts, notes.WithEmptySections())
+	}
+	if o.otherSectionTitle != "" {
+		opts = append(opts, notes.WithOtherSectionTitle(o.otherSectionTitle))
+	}
+	if o.patchBuckets {
+		opts = append(opts, notes.WithPatchBuckets())
+	}
+	if o.prLinkFormat != "" {
+		opts = append(opts, notes.WithPRLinkFormat(o.prLinkFormat))
+	}
+	if o.compact {
+		opts = append(opts, notes.WithCompact())
+	}
+	if o.warnings != nil {
+		opts = append(opts, notes.WithRenderWarnings(o.warnings))
+	}
+	if o.parsedNoteTemplate != nil {
+		opts = append(opts, notes.WithNoteTemplate(o.parsedNoteTemplate))
+	}
+	if o.labelLegend {
+		opts = append(opts, notes.WithLabelLegend(o.labelColors))
+	}
+	return opts
+}
+
+// renderOptions returns the document options of the -format output, which add
+// the badges, the most reacted notes and the contributors to the markdown and
+// configure the contributors and index-json formats
+func (o *options) renderOptions() []notes.DocumentOption {
+	opts := o.documentOptions()
+	if o.stream {
+		opts = append(opts, notes.WithStream())
+	}
+	if o.badges != "" {
+		opts = append(opts, notes.WithBadges(notes.BadgeStyle(o.badges), o.parsedBadgeColors))
+	}
+	if o.topReacted > 0 {
+		opts = append(opts, notes.WithTopReacted(o.topReacted))
+	}
+	if o.contributors {
+		opts = append(opts, notes.WithContributorsSection())
+	}
+	return append(opts,
+		notes.WithContributorsFormat(o.contributorsFormat),
+		notes.WithFirstTimeContributors(o.firstTimers),
+		notes.WithIndexMarkdown(o.indexMarkdown),
+	)
+}
+
+// WriteSplitReleaseNotes writes the release notes in markdown format to one
+// file per kind within the output directory.
+func (o *options) WriteSplitReleaseNotes(releaseNotes notes.ReleaseNoteList) error {
+	if err := os.MkdirAll(o.outputDir, 0755); err != nil {
+		level.Error(o.logger).Log("msg", "error creating the output directory", "err", err)
+		return err
+	}
+
+	for file, list := range notes.SplitByKind(releaseNotes) {
+		if len(list) == 0 && o.skipEmpty {
+			continue
+		}
+
+		content, err := notes.RenderToBytes(list, "markdown", o.documentOptions()...)
+		if err != nil {
+			level.Error(o.logger).Log("msg", "error rendering release notes", "file", file, "err", err)
+			return err
+		}
+
+		path := filepath.Join(o.outputDir, file)
+		if err := ioutil.WriteFile(path, content, 0644); err != nil {
+			level.Error(o.logger).Log("msg", "error writing release notes", "path", path, "err", err)
+			return err
+		}
+		level.Debug(o.logger).Log("msg", "release notes written to file", "path", path, "notes", len(list))
+	}
+
+	level.Info(o.logger).Log("msg", "release notes written to directory", "path", o.outputDir, "split-by", o.splitBy)
+	return nil
+}
+
+// writeHugoSite writes the content file of every note to the output directory,
+// named after its PR number, as well as an index page linking them.
+func (o *options) writeHugoSite(releaseNotes notes.ReleaseNoteList) error {
+	if err := os.MkdirAll(o.outputDir, 0755); err != nil {
+		level.Error(o.logger).Log("msg", "error creating the output directory", "err", err)
+		return err
+	}
+
+	files := map[string]string{}
+	write := func(file string, render func(w io.Writer) error) error {
+		content := &bytes.Buffer{}
+		if err := render(content); err != nil {
+			level.Error(o.logger).Log("msg", "error rendering release notes", "file", file, "err", err)
+			return err
+		}
+
+		path := filepath.Join(o.outputDir, file)
+		if err := ioutil.WriteFile(path, content.Bytes(), 0644); err != nil {
+			level.Error(o.logger).Log("msg", "error writing release notes", "path", path, "err", err)
+			return err
+		}
+		return nil
+	}
+
+	for _, note := range releaseNotes {
+		file := notes.HugoFileName(note.PrNumber)
+		if commit, ok := files[file]; ok {
+			return fmt.Errorf("the notes of the commits %s and %s would both be written to %s", commit, note.Commit, file)
+		}
+		files[file] = note.Commit
+
+		note := note
+		if err := write(file, func(w io.Writer) error {
+			return notes.RenderHugoNote(note, w, o.documentOptions()...)
+		}); err != nil {
+			return err
+		}
+	}
+
+	title := "Release Notes"
+	if o.releaseVersion != "" {
+		title += " " + o.releaseVersion
+	}
+	if err := write(notes.HugoIndexFile, func(w io.Writer) error {
+		return notes.RenderHugoIndex(releaseNotes, title, w, o.documentOptions()...)
+	}); err != nil {
+		return err
+	}
+
+	level.Info(o.logger).Log("msg", "release notes written to directory", "path", o.outputDir, "format", o.format, "notes", len(releaseNotes))
+	return nil
+}
+
+func (o *options) WriteReleaseNotes(releaseNotes notes.ReleaseNoteList) error {
+	level.Info(o.logger).Log("msg", "got the commits, performing rendering")
+
+	// the label colors are fetched once for the legend and all label chips
+	if o.labelLegend && o.labelColors == nil {
+		client, err := o.newGithubClient(context.Background())
+		if err != nil {
+			level.Error(o.logger).Log("msg", "error creating the GitHub client", "err", err)
+			return err
+		}
+		o.labelColors = notes.LabelColorsOrNone(client, o.logger, notes.WithOrg(o.githubOrg), notes.WithRepo(o.githubRepo))
+	}
+
+	// Open a handle to the file which will contain the release notes output
+	var output *os.File
+	var err error
+	var existingNotes, mergedNotes, previousNotes notes.KeyedReleaseNotes
+
+	if o.output != "" {
+		// JSON output is merged with the notes of the previous run, all other
+		// formats replace the file
+		flags := os.O_RDWR | os.O_CREATE
+		if o.format != "json" {
+			flags |= os.O_TRUNC
+		}
+		output, err = os.OpenFile(o.outputPath(), flags, 0644)
+		if err != nil {
+			level.Error(o.logger).Log("msg", "error opening the supplied output file", "err", err)
+			return err
+		}
+	} else {
+		output, err = ioutil.TempFile(o.tempDir, "release-notes-")
+		if err != nil {
+			level.Error(o.logger).Log("msg", "error creating a temporary file to write the release notes to", "err", err)
+			return err
+		}
+	}
+
+	// Notes of previous runs are merged into the JSON output
+	if o.format == "json" {
+		byteValue, _ := ioutil.ReadAll(output)
+
+		// the output of a previous run may have been split into parts
+		if len(byteValue) == 0 && o.output != "" {
+			if byteValue, err = readSplitJSON(output.Name()); err != nil {
+				level.Error(o.logger).Log("msg", "error reading the parts of the existing notes", "err", err)
+				return err
+			}
+		}
+
+		if len(byteValue) > 0 {
+			existingNotes, err = notes.ParseKeyedReleaseNotes(byteValue)
+			if err != nil {
+				level.Error(o.logger).Log("msg", "error unmarshalling existing notes", "err", err)
+				return err
+			}
+			o.excludeKeyedNotes(existingNotes)
+		}
+
+		// merging updates the existing notes, so the markdown output is
+		// compared with a copy of them
+		if len(byteValue) > 0 && o.renderOnlyChanged {
+			if previousNotes, err = notes.ParseKeyedReleaseNotes(byteValue); err != nil {
+				return err
+			}
+		}
+
+		if len(byteValue) > 0 {
+			if err := output.Truncate(0); err != nil {
+				return err
+			}
+			if _, err := output.Seek(0, 0); err != nil {
+				return err
+			}
+		}
+		// the existing notes may belong to other repositories, so they are
+		// merged by their org/repo#pr key
+		if len(existingNotes) > 0 && notes.MergeStrategy(o.mergeStrategy) == notes.MergeStrategyNewest {
+			mergedNotes = notes.MergeNewestKeyedReleaseNotes(releaseNotes, existingNotes, o.annotateNew)
+		} else if len(existingNotes) > 0 {
+			mergedNotes = notes.MergeKeyedReleaseNotes(releaseNotes, existingNotes, o.annotateNew)
+		}
+	}
+
+	if o.format == "index-json" {
+		if err := o.writeIndexMarkdown(releaseNotes); err != nil {
+			level.Error(o.logger).Log("msg", "error writing the indexed markdown", "err", err)
+			return err
+		}
+	}
+
+	if o.format == "contributors" && o.flagFirstTime && o.firstTimers == nil {
+		if o.firstTimers, err = o.firstTimeContributors(releaseNotes); err != nil {
+			level.Error(o.logger).Log("msg", "error looking up the first-time contributors", "err", err)
+			return err
+		}
+	}
+
+	// Contextualized release notes can be printed in a variety of formats. The
+	// JSON output is rendered in memory, so that it can be validated, all
+	// other formats are written to the output directly.
+	if mergedNotes != nil || o.validateOutput {
+		var content []byte
+		if mergedNotes != nil {
+			content, err = notes.RenderKeyedJSON(mergedNotes)
+		} else {
+			content, err = notes.RenderToBytes(releaseNotes, o.format, o.renderOptions()...)
+		}
+		if err != nil {
+			level.Error(o.logger).Log("msg", "error rendering release notes", "err", err)
+			return err
+		}
+
+		if o.validateOutput {
+			if err := notes.ValidateJSON(content); err != nil {
+				level.Error(o.logger).Log("msg", "JSON output does not conform to the schema", "err", err)
+				return err
+			}
+		}
+
+		if _, err := output.Write(content); err != nil {
+			level.Error(o.logger).Log("msg", "error writing release notes", "err", err)
+			return err
+		}
+	} else if err := notes.Render(releaseNotes, o.format, output, o.renderOptions()...); err != nil {
+		level.Error(o.logger).Log("msg", "error rendering release notes", "err", err)
+		return err
+	}
+
+	if o.markdownOutput != "" {
+		current := mergedNotes
+		if current == nil {
+			current = notes.KeyedReleaseNotes{}
+			for _, note := range releaseNotes {
+				current[notes.NoteKey(note)] = note
+			}
+		}
+		if err := o.writeMarkdownOutput(previousNotes, current); err != nil {
+			level.Error(o.logger).Log("msg", "error writing the markdown output", "err", err)
+			return err
+		}
+	}
+
+	if err := o.encodeOutput(output); err != nil {
+		level.Error(o.logger).Log("msg", "error encoding the release notes", "err", err)
+		return err
+	}
+
+	if o.preview {
+		doc, err := notes.CreateDocument(releaseNotes, o.documentOptions()...)
+		if err != nil {
+			level.Error(o.logger).Log("msg", "error creating release note document", "err", err)
+			return err
+		}
+		if err := renderPreview(doc, releaseNotes, os.Stderr, useColor(o.color, os.Stderr), time.Now(), o.dateLayout(previewDateLayout)); err != nil {
+			level.Error(o.logger).Log("msg", "error rendering release notes preview", "err", err)
+			return err
+		}
+	}
+
+	// the output is split before the checksums are written, so that every
+	// part gets its own
+	var parts []string
+	if o.maxFileBytes > 0 {
+		if parts, err = o.splitOutput(output.Name()); err != nil {
+			level.Error(o.logger).Log("msg", "error splitting the output", "err", err)
+			return err
+		}
+	}
+
+	if o.checksum && len(parts) > 0 {
+		if err := o.writePartChecksums(parts); err != nil {
+			level.Error(o.logger).Log("msg", "error writing the checksum files of the parts", "err", err)
+			return err
+		}
+	} else if o.checksum {
+		digest, err := fileChecksum(output)
+		if err != nil {
+			level.Error(o.logger).Log("msg", "error computing the checksum of the release notes", "err", err)
+			return err
+		}
+		level.Info(o.logger).Log("msg", "computed checksum of the release notes", "sha256", digest)
+
+		if o.output != "" {
+			if err := writeChecksumFile(output.Name(), digest); err != nil {
+				level.Error(o.logger).Log("msg", "error writing the checksum file", "err", err)
+				return err
+			}
+		} else {
+			fmt.Fprintf(os.Stderr, "%s  %s\n", digest, output.Name())
+		}
+	}
+
+	// the covered range lets the next run detect gaps
+	if o.format == "json" && o.output != "" && o.input == "" {
+		if err := writeCoverage(output.Name(), releaseRange{StartSHA: o.coveredStart, EndSHA: o.endSHA}); err != nil {
+			level.Error(o.logger).Log("msg", "error recording the covered range", "err", err)
+			return err
+		}
+	}
+
+	level.Info(o.logger).Log(
+		"msg", "release notes written to file",
+		"path", output.Name(),
+		"format", o.format,
+	)
+
+	// broken links fail the run before the notes are published
+	if o.verifyLinks {
+		if err := o.checkLinks(output); err != nil {
+			level.Error(o.logger).Log("msg", "error verifying the links of the release notes", "err", err)
+			return err
+		}
+	}
+
+	if o.postRenderCommand != "" {
+		if err := o.runPostRenderCommand(output.Name()); err != nil {
+			level.Error(o.logger).Log("msg", "error converting the release notes", "err", err)
+			return err
+		}
+	}
+
+	if o.createRelease {
+		body, err := renderedBody(output)
+		if err != nil {
+			return err
+		}
+
+		client, err := o.newGithubClient(context.Background())
+		if err != nil {
+			return err
+		}
+		release, err := notes.PublishRelease(
+			client, o.releaseVersion, string(body), o.releaseDraft, o.releasePrerelease,
+			notes.WithOrg(o.githubOrg), notes.WithRepo(o.githubRepo),
+		)
+		if err != nil {
+			level.Error(o.logger).Log("msg", "error publishing the GitHub release", "err", err)
+			return err
+		}
+		level.Info(o.logger).Log("msg", "published GitHub release", "url", release.GetHTMLURL())
+	}
+
+	if o.createDiscussion {
+		body, err := renderedBody(output)
+		if err != nil {
+			return err
+		}
+
+		client, err := o.newGithubClient(context.Background())
+		if err != nil {
+			return err
+		}
+		org, repo := o.discussionRepository()
+		endpoint := o.githubGraphQLURL
+		if endpoint == "" {
+			endpoint = notes.DefaultGraphQLURL
+		}
+		url, err := notes.PublishDiscussion(
+			client, endpoint, o.discussionCategory, o.releaseVersion, string(body),
+			notes.WithOrg(org), notes.WithRepo(repo),
+		)
+		if err != nil {
+			level.Error(o.logger).Log("msg", "error publishing the GitHub discussion", "err", err)
+			return err
+		}
+		level.Info(o.logger).Log("msg", "published GitHub discussion", "url", url)
+	}
+
+	// the whole output is published before it is replaced by its parts
+	if len(parts) > 0 {
+		for _, file := range []string{output.Name(), output.Name() + ".sha256"} {
+			if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+				level.Error(o.logger).Log("msg", "error removing the split output", "err", err)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeIndexMarkdown writes the notes in markdown format to the
+// -index-markdown file, which is indexed by -format index-json
+func (o *options) writeIndexMarkdown(releaseNotes notes.ReleaseNoteList) error {
+	content, err := notes.RenderToBytes(releaseNotes, "markdown", o.renderOptions()...)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(o.indexMarkdown, content, 0644)
+}
+
+// firstTimeContributors returns the logins of the contributors of the notes
+// without earlier merged PRs
+func (o *options) firstTimeContributors(releaseNotes notes.ReleaseNoteList) ([]string, error) {
+	client, err := o.newGithubClient(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	contributors := notes.Contributors(releaseNotes)
+	if err := notes.FlagFirstTimeContributors(
+		client, contributors, notes.WithOrg(o.githubOrg), notes.WithRepo(o.githubRepo),
+	); err != nil {
+		return nil, err
+	}
+	logins := []string{}
+	for _, contributor := range contributors {
+		if contributor.FirstTime {
+			logins = append(logins, contributor.Login)
+		}
+	}
+	return logins, nil
+}
+
+// splitList splits a comma separated flag value into its trimmed, non-empty
+// elements
+func splitList(value string) []string {
+	elements := []string{}
+	for _, element := range strings.Split(value, ",") {
+		if element = strings.TrimSpace(element); element != "" {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
+
+// validateAPIURL returns an error unless the GitHub API endpoint is empty or
+// an absolute URL
+func validateAPIURL(endpoint string) error {
+	if endpoint == "" {
+		return nil
+	}
+	if u, err := url.Parse(endpoint); err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("invalid GitHub API URL %q", endpoint)
+	}
+	return nil
+}
+
+// validateTempDir returns an error unless the directory is empty, which is the
+// default directory for temporary files, or an existing directory which files
+// can be created in
+func validateTempDir(dir string) error {
+	if dir == "" {
+		return nil
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("-temp-dir %q: %v", dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("-temp-dir %q is not a directory", dir)
+	}
+	probe, err := ioutil.TempFile(dir, ".release-notes-")
+	if err != nil {
+		return fmt.Errorf("-temp-dir %q is not writable: %v", dir, err)
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
+
+func parseOptions(args []string, logger log.Logger) (*options, error) {
+	opts := &options{}
+	flags := opts.BindFlags()
+
+	// Parse the args.
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if opts.version {
+		return nil, errors.New("version")
+	}
+
+	if opts.quiet && opts.debug {
+		return nil, errors.New("-quiet and -debug are mutually exclusive")
+	}
+
+	if opts.verbosity < 0 {
+		return nil, errors.New("-v must not be negative")
+	}
+	if opts.quiet && opts.verbosity > 0 {
+		return nil, errors.New("-quiet and -v are mutually exclusive")
+	}
+
+	switch opts.color {
+	case colorAuto, colorAlways, colorNever:
+	default:
+		return nil, fmt.Errorf("%q is an unsupported color mode", opts.color)
+	}
+
+	// The stderr logger is replaced to decorate the levels with colors
+	if useColor(opts.color, os.Stderr) {
+		logger = newColorLogger(os.Stderr)
+	}
+
+	// Add appropriate log filtering
+	switch {
+	case opts.debug || opts.verbosity > 0:
+		logger = level.NewFilter(logger, level.AllowDebug())
+	case opts.quiet:
+		logger = level.NewFilter(logger, level.AllowError())
+	default:
+		logger = level.NewFilter(logger, level.AllowInfo())
+	}
+	logger = log.With(logger, "timestamp", log.DefaultTimestamp, "caller", log.DefaultCaller)
+	opts.logger = logger
+
+	switch notes.MergeStrategy(opts.mergeStrategy) {
+	case notes.MergeStrategyError, notes.MergeStrategyFirst, notes.MergeStrategyLast, notes.MergeStrategyNewest:
+	default:
+		return nil, fmt.Errorf("%q is an unsupported merge strategy", opts.mergeStrategy)
+	}
+
+	// The GitHub Token is required, unless input files are merged offline.
+	if opts.githubToken == "" && (opts.input == "" || opts.createRelease || opts.createDiscussion) {
+		return nil, errors.New("GitHub token must be set via -github-token or $GITHUB_TOKEN")
+	}
+
+	// the team filters the commits, which are not fetched with -input
+	if opts.input != "" && opts.requiredTeam != "" {
+		return nil, errors.New("-required-team cannot be combined with -input")
+	}
+
+	// nothing is fetched from the API with -input
+	if opts.estimate && (opts.input != "" || opts.rangesFile != "") {
+		return nil, errors.New("-estimate cannot be combined with -input or -ranges-file")
+	}
+
+	if opts.rangesFile != "" {
+		if opts.startSHA != "" || opts.endSHA != "" || opts.startRev != "" || opts.endRev != "" || opts.startTag != "" || opts.endTag != "" || opts.baseRef != "" ||
+			opts.rangeFile != "" || opts.discoverRange || opts.input != "" || opts.output != "" || opts.outputDir != "" || opts.interactive {
+			return nil, errors.New("-ranges-file cannot be combined with another range, -input, -output, -output-dir or -interactive")
+		}
+	}
+
+	if opts.rangeFile != "" {
+		if err := opts.applyRangeFile(opts.rangeFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.baseRef != "" && (opts.startSHA != "" || opts.startRev != "" || opts.startTag != "" || opts.discoverRange || opts.input != "") {
+		return nil, errors.New("-base-ref cannot be combined with -start-sha, -start-rev, -start-tag, -discover-range or -input")
+	}
+
+	if opts.startTag != "" && (opts.startSHA != "" || opts.startRev != "") {
+		return nil, errors.New("-start-tag cannot be combined with -start-sha or -start-rev")
+	}
+	if opts.endTag != "" && (opts.endSHA != "" || opts.endRev != "") {
+		return nil, errors.New("-end-tag cannot be combined with -end-sha or -end-rev")
+	}
+
+	// The start SHA is required.
+	if opts.startSHA == "" && opts.startRev == "" && opts.startTag == "" && opts.baseRef == "" && !opts.discoverRange && opts.input == "" && opts.rangesFile == "" {
+		return nil, errors.New("The starting commit hash must be set via -start-sha, $START_SHA, -start-rev, $START_REV, -start-tag, -base-ref or -range-file")
+	}
+
+	// The end SHA is required.
+	if opts.endSHA == "" && opts.endRev == "" && opts.endTag == "" && !opts.discoverRange && opts.input == "" && opts.rangesFile == "" {
+		return nil, errors.New("The ending commit hash must be set via -end-sha, $END_SHA, -end-rev, $END_REV, -end-tag or -range-file")
+	}
+
+	switch notes.NoteSource(opts.noteSource) {
+	case notes.NoteSourceReleaseNote, notes.NoteSourceConventional, notes.NoteSourceCommitBody:
+	default:
+		return nil, fmt.Errorf("%q is an unsupported note source", opts.noteSource)
+	}
+
+	switch notes.AuthorField(opts.authorField) {
+	case notes.AuthorFieldAuthor, notes.AuthorFieldCommitter, notes.AuthorFieldEither:
+	default:
+		return nil, fmt.Errorf("%q is an unsupported author field", opts.authorField)
+	}
+
+	for _, number := range strings.Split(opts.excludePRs, ",") {
+		if number = strings.TrimSpace(number); number == "" {
+			continue
+		}
+		pr, err := strconv.Atoi(strings.TrimPrefix(number, "#"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid PR number %q in -exclude-prs", number)
+		}
+		opts.excludedPRs = append(opts.excludedPRs, pr)
+	}
+
+	if opts.prNumberRegex != "" {
+		exp, err := regexp.Compile(opts.prNumberRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -pr-number-regex: %v", err)
+		}
+		if exp.NumSubexp() < 1 {
+			return nil, errors.New("The -pr-number-regex must contain a capture group for the PR number")
+		}
+	}
+
+	if opts.validateOutput && opts.format != "json" {
+		return nil, errors.New("-validate-output is only supported with -format json")
+	}
+
+	for _, endpoint := range []string{opts.githubBaseURL, opts.githubGraphQLURL} {
+		if err := validateAPIURL(endpoint); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, host := range splitList(opts.githubHostAllowlist) {
+		if strings.ContainsAny(host, "/:@") {
+			return nil, fmt.Errorf("-github-hostname-allowlist %q must be a hostname, like github.example.com", host)
+		}
+	}
+
+	switch notes.DocumentLayout(opts.layout) {
+	case notes.LayoutFlat:
+	case notes.LayoutKubernetes:
+		if opts.stream {
+			return nil, errors.New("-stream only supports -layout flat")
+		}
+	default:
+		return nil, fmt.Errorf("%q is an unsupported layout", opts.layout)
+	}
+
+	switch notes.NoteGrouping(opts.groupBy) {
+	case notes.GroupBySIG:
+	case notes.GroupByArea:
+		if notes.DocumentLayout(opts.layout) != notes.LayoutFlat {
+			return nil, errors.New("-group-by area only supports -layout flat")
+		}
+	default:
+		return nil, fmt.Errorf("%q is an unsupported -group-by value", opts.groupBy)
+	}
+
+	if opts.outputDir != "" {
+		if opts.splitBy != "kind" {
+			return nil, fmt.Errorf("%q is an unsupported -split-by value", opts.splitBy)
+		}
+		if opts.output != "" || opts.createRelease || (opts.format != "markdown" && opts.format != "hugo") {
+			return nil, errors.New("-output-dir requires -format markdown or hugo and cannot be combined with -output or -create-release")
+		}
+	}
+
+	if err := validateTempDir(opts.tempDir); err != nil {
+		return nil, err
+	}
+
+	if opts.verifyLinks && opts.outputDir != "" {
+		return nil, errors.New("-verify-links cannot be combined with -output-dir")
+	}
+
+	if opts.format == "hugo" && opts.outputDir == "" {
+		return nil, errors.New("-format hugo requires -output-dir")
+	}
+
+	if opts.fillGaps && opts.checkpointFile != "" {
+		return nil, errors.New("-fill-gaps cannot be combined with -checkpoint-file")
+	}
+
+	if opts.stripMarkdown && opts.format != "json" {
+		return nil, errors.New("-strip-markdown requires -format json")
+	}
+
+	if opts.transformCommand != "" && strings.TrimSpace(opts.transformCommand) == "" {
+		return nil, errors.New("-transform-command must not be blank")
+	}
+
+	if opts.postRenderCommand != "" {
+		if opts.format != "markdown" || opts.outputDir != "" || opts.postRenderOutput == "" {
+			return nil, errors.New("-post-render-command requires -format markdown and -post-render-output, and cannot be combined with -output-dir")
+		}
+		if strings.TrimSpace(opts.postRenderCommand) == "" {
+			return nil, errors.New("-post-render-command must not be blank")
+		}
+	}
+
+	switch opts.outputEncoding {
+	case encodingUTF8:
+	case encodingLatin1:
+		if opts.outputBOM {
+			return nil, errors.New("-output-bom requires -output-encoding utf-8")
+		}
+	default:
+		return nil, fmt.Errorf("%q is an unsupported output encoding", opts.outputEncoding)
+	}
+	if opts.outputEncoding != encodingUTF8 || opts.outputBOM {
+		// JSON is read back by the next run and the published notes must be
+		// UTF-8
+		if opts.format == "json" || opts.outputDir != "" || opts.createRelease || opts.createDiscussion {
+			return nil, errors.New("-output-encoding and -output-bom cannot be combined with -format json, -output-dir, -create-release or -create-discussion")
+		}
+	}
+
+	if opts.createRelease && (opts.format != "markdown" || opts.releaseVersion == "") {
+		return nil, errors.New("-create-release requires -format markdown and -release-version")
+	}
+
+	if opts.createDiscussion {
+		if opts.format != "markdown" || opts.releaseVersion == "" || opts.outputDir != "" {
+			return nil, errors.New("-create-discussion requires -format markdown and -release-version, and cannot be combined with -output-dir")
+		}
+		if parts := strings.Split(opts.discussionRepo, "/"); opts.discussionRepo != "" && (len(parts) != 2 || parts[0] == "" || parts[1] == "") {
+			return nil, fmt.Errorf("-discussion-repo %q is not of the form org/repo", opts.discussionRepo)
+		}
+		if strings.TrimSpace(opts.discussionCategory) == "" {
+			return nil, errors.New("-create-discussion requires a -discussion-category")
+		}
+	}
+
+	if opts.discoverRange {
+		if _, err := notes.ReleaseBranchName(opts.releaseVersion, opts.branchPattern); err != nil {
+			return nil, fmt.Errorf("-discover-range requires a valid -release-version: %v", err)
+		}
+	}
+
+	if err := opts.validateOutputTemplate(); err != nil {
+		return nil, err
+	}
+
+	if opts.kindRulesFile != "" {
+		if !opts.inferKind {
+			return nil, errors.New("-kind-rules-file requires -infer-kind")
+		}
+		rules, err := loadKindRules(opts.kindRulesFile)
+		if err != nil {
+			return nil, err
+		}
+		opts.kindRules = rules
+	}
+
+	if opts.ownershipFile != "" {
+		owners, err := loadOwnershipFile(opts.ownershipFile)
+		if err != nil {
+			return nil, err
+		}
+		opts.sigOwners = owners
+	}
+
+	if opts.dateFormat != "" {
+		if err := validateDateFormat(opts.dateFormat); err != nil {
+			return nil, err
+		}
+	}
+
+	switch notes.MarkdownFlavor(opts.markdownFlavor) {
+	case notes.FlavorGFM, notes.FlavorCommonMark:
+	default:
+		return nil, fmt.Errorf("%q is an unsupported markdown flavor", opts.markdownFlavor)
+	}
+
+	switch notes.BadgeStyle(opts.badges) {
+	case "", notes.BadgeStyleShields, notes.BadgeStyleStatic:
+	default:
+		return nil, fmt.Errorf("%q is an unsupported badge style", opts.badges)
+	}
+
+	if opts.noteTemplate != "" {
+		if opts.format != "markdown" {
+			return nil, errors.New("-note-template requires -format markdown")
+		}
+		tmpl, err := notes.ParseNoteTemplate(opts.noteTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -note-template: %v", err)
+		}
+		opts.parsedNoteTemplate = tmpl
+	}
+
+	titlePatterns, err := compileTitlePatterns(opts.excludeTitles.values)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -exclude-title-pattern: %v", err)
+	}
+	opts.parsedTitlePatterns = titlePatterns
+
+	if opts.warningsFile != "" {
+		opts.warnings = &notes.Warnings{}
+	}
+
+	if err := notes.ValidatePRLinkFormat(opts.prLinkFormat); err != nil {
+		return nil, fmt.Errorf("invalid -pr-link-format: %v", err)
+	}
+
+	opts.parsedBadgeColors = map[string]string{}
+	if opts.badgeColors != "" {
+		for _, pair := range strings.Split(opts.badgeColors, ",") {
+			parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				return nil, fmt.Errorf("invalid kind=color pair %q in -badge-colors", pair)
+			}
+			opts.parsedBadgeColors[parts[0]] = parts[1]
+		}
+	}
+
+	if !notes.HasString(notes.Formats, opts.format) {
+		return nil, fmt.Errorf("%q is an unsupported -format, expected one of %s", opts.format, strings.Join(notes.Formats, ", "))
+	}
+
+	if opts.format == "contributors" {
+		switch opts.contributorsFormat {
+		case "markdown", "json":
+		default:
+			return nil, fmt.Errorf("invalid -contributors-format %q, expected markdown or json", opts.contributorsFormat)
+		}
+	}
+
+	if (opts.format == "index-json") != (opts.indexMarkdown != "") {
+		return nil, errors.New("-format index-json and -index-markdown require each other")
+	}
+
+	if opts.flagFirstTime && opts.format != "contributors" {
+		return nil, errors.New("-flag-first-time requires -format contributors")
+	}
+
+	if opts.labelLegend && opts.format != "html" {
+		return nil, errors.New("-label-legend requires -format html")
+	}
+
+	if opts.annotateNew && (opts.format != "json" || opts.output == "") {
+		return nil, errors.New("-annotate-new requires -format json and an existing -output file to merge with")
+	}
+
+	if opts.maxFileBytes < 0 {
+		return nil, errors.New("-max-file-bytes must not be negative")
+	}
+
+	if opts.maxRangeCommits < 0 {
+		return nil, errors.New("-max-range-commits must not be negative")
+	}
+
+	if opts.maxFileBytes > 0 && (opts.output == "" || (opts.format != "markdown" && opts.format != "json")) {
+		return nil, errors.New("-max-file-bytes requires an -output file of -format markdown or json")
+	}
+
+	if opts.markdownOutput != "" && (opts.format != "json" || opts.output == "") {
+		return nil, errors.New("-markdown-output requires -format json and an -output file")
+	}
+
+	if opts.compact && opts.stream {
+		return nil, errors.New("-compact cannot be combined with -stream")
+	}
+
+	if opts.patchBuckets && (opts.format != "markdown" || opts.stream || opts.stableAnchors) {
+		return nil, errors.New("-patch-buckets requires -format markdown and cannot be combined with -stream or -stable-anchors, whose anchors would repeat in every release")
+	}
+
+	if opts.renderOnlyChanged && (opts.markdownOutput == "" || !opts.stableAnchors) {
+		return nil, errors.New("-render-only-changed requires -markdown-output and -stable-anchors")
+	}
+
+	if opts.interactive && !isTerminal(os.Stdin) {
+		return nil, errors.New("-interactive requires an interactive terminal")
+	}
+
+	if opts.maxNoteLength < 0 || opts.wrapNote < 0 {
+		return nil, errors.New("-max-note-length and -wrap-note must not be negative")
+	}
+
+	if opts.strictNotes && opts.maxNoteLength == 0 {
+		return nil, errors.New("-strict-notes requires -max-note-length")
+	}
+
+	if opts.failOnUnlabeled {
+		dimensions := splitList(opts.requireLabels)
+		if len(dimensions) == 0 {
+			return nil, errors.New("-fail-on-unlabeled requires at least one of -require-labels")
+		}
+		for _, dimension := range dimensions {
+			if !notes.HasString(labelDimensions, dimension) {
+				return nil, fmt.Errorf("-require-labels %q is not supported (options: %s)", dimension, strings.Join(labelDimensions, ", "))
+			}
+		}
+	}
+
+	if opts.inferSIGBudget < 0 {
+		return nil, errors.New("-infer-sig-budget must not be negative")
+	}
+
+	if opts.inferSIGBudget > 0 && opts.ownershipFile == "" {
+		return nil, errors.New("-infer-sig-budget requires -ownership-file")
+	}
+
+	if opts.enforceStyle && opts.reportStyle {
+		return nil, errors.New("-enforce-style and -report-style-violations cannot be combined")
+	}
+
+	if opts.topReacted < 0 || opts.reactionsBudget < 0 {
+		return nil, errors.New("-top-reacted and -reactions-budget must not be negative")
+	}
+
+	if opts.topReacted > 0 && opts.format != "markdown" {
+		return nil, errors.New("-top-reacted requires -format markdown")
+	}
+
+	if opts.reactionsBudget > 0 && opts.topReacted == 0 {
+		return nil, errors.New("-reactions-budget requires -top-reacted")
+	}
+
+	switch notes.NoteOrder(opts.sortBy) {
+	case notes.SortByNumber, notes.SortBySize:
+	default:
+		return nil, fmt.Errorf("%q is an unsupported -sort-by order", opts.sortBy)
+	}
+
+	sortKeys, err := notes.ParseSortKeys(splitList(opts.withinSectionSort))
+	if err != nil {
+		return nil, fmt.Errorf("invalid -within-section-sort: %v", err)
+	}
+	opts.parsedSortKeys = sortKeys
+
+	if opts.statsBudget < 0 {
+		return nil, errors.New("-stats-budget must not be negative")
+	}
+
+	if opts.statsBudget > 0 && !opts.showStats && notes.NoteOrder(opts.sortBy) != notes.SortBySize && !opts.sortsBySize() {
+		return nil, errors.New("-stats-budget requires -show-stats, -sort-by size or the size key of -within-section-sort")
+	}
+
+	if opts.docsPathBudget < 0 {
+		return nil, errors.New("-docs-path-budget must not be negative")
+	}
+
+	if (opts.docsPaths != "" || opts.docsPathBudget > 0) && !opts.excludeDocs {
+		return nil, errors.New("-docs-paths and -docs-path-budget require -exclude-docs")
+	}
+
+	if opts.pathFilterBudget < 0 {
+		return nil, errors.New("-path-filter-budget must not be negative")
+	}
+
+	// the files of the PRs are not known for the notes of -input
+	if opts.pathFilter != "" && opts.input != "" {
+		return nil, errors.New("-path-filter cannot be combined with -input")
+	}
+
+	if opts.checkpointInterval <= 0 {
+		return nil, errors.New("-checkpoint-interval must be positive")
+	}
+
+	if opts.githubAccept != "" {
+		mediaType, _, err := mime.ParseMediaType(opts.githubAccept)
+		if err != nil || !strings.Contains(mediaType, "/") {
+			return nil, fmt.Errorf("-github-accept %q is not a valid media type", opts.githubAccept)
+		}
+	}
+
+	if opts.hostConcurrency < 0 {
+		return nil, errors.New("-host-concurrency must not be negative")
+	}
+
+	if opts.maxRetries < 0 {
+		return nil, errors.New("The maximum number of retries must not be negative")
+	}
+
+	// Tags are resolved with the API, the ones which cannot be are parsed as
+	// revisions below
+	if opts.startTag != "" || opts.endTag != "" {
+		if err := opts.resolveTags(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Check if we have to parse a revision. The merge-base is computed in
+	// the same clone if there is one.
+	tmpDir := ""
+	if opts.startRev != "" || opts.endRev != "" {
+		level.Info(logger).Log("msg", "cloning repository to discover start or end sha")
+		dir, err := notes.CloneTempRepository(opts.githubOrg, opts.githubRepo, opts.tempDir)
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dir)
+		tmpDir = dir
+	}
+	if tmpDir != "" {
+		if opts.startRev != "" {
+			sha, err := notes.RevParse(opts.startRev, tmpDir, logger)
+			if err != nil {
+				return nil, err
+			}
+			level.Info(logger).Log("msg", "using found start SHA: "+sha)
+			opts.startSHA = sha
+		}
+		if opts.endRev != "" {
+			sha, err := notes.RevParse(opts.endRev, tmpDir, logger)
+			if err != nil {
+				return nil, err
+			}
+			level.Info(logger).Log("msg", "using found end SHA: "+sha)
+			opts.endSHA = sha
+		}
+		if opts.baseRef != "" {
+			sha, err := notes.MergeBaseLocal(opts.baseRef, opts.branch, tmpDir)
+			if err != nil {
+				return nil, err
+			}
+			level.Info(logger).Log("msg", "using merge-base as start SHA", "base", opts.baseRef, "branch", opts.branch, "sha", sha)
+			opts.startSHA = sha
+		}
+	}
+
+	return opts, nil
+}
+
+func run(logger log.Logger, args []string) (err error) {
+	// Parse the CLI options and enforce required defaults
+	opts, err := parseOptions(args, logger)
+	if err != nil && err.Error() == "version" {
+		fmt.Println("nicolaferraro")
+		return nil
+	} else if err != nil {
+		level.Error(logger).Log("msg", "error parsing options", "err", err)
+		return err
+	}
+
+	// the warnings are written even if the run fails
+	if opts.warningsFile != "" {
+		defer func() {
+			if writeErr := opts.writeWarnings(); writeErr != nil {
+				level.Error(opts.logger).Log("msg", "error writing the warnings", "err", writeErr)
+				if err == nil {
+					err = writeErr
+				}
+			}
+		}()
+	}
+
+	if opts.rangesFile != "" {
+		ranges, err := loadRangesFile(opts.rangesFile)
+		if err != nil {
+			level.Error(opts.logger).Log("msg", "error loading the ranges file", "err", err)
+			return err
+		}
+		return opts.runBatch(ranges)
+	}
+	if opts.estimate {
+		return opts.estimateRequests()
+	}
+	return opts.generate()
+}
+
+// estimateRequests logs the estimated API requests of fetching the notes and
+// warns if they exceed the remaining rate limit
+func (o *options) estimateRequests() error {
+	ctx := context.Background()
+	if o.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.timeout)
+		defer cancel()
+	}
+	githubClient, err := o.newGithubClient(ctx)
+	if err != nil {
+		level.Error(o.logger).Log("msg", "error creating the GitHub client", "err", err)
+		return err
+	}
+
+	opts := o.githubApiOptions(ctx)
+	if err := o.resolveRange(githubClient, opts...); err != nil {
+		return err
+	}
+
+	estimate, err := notes.EstimateRequests(githubClient, o.startSHA, o.endSHA, opts...)
+	if err != nil {
+		level.Error(o.logger).Log("msg", "error estimating the API requests", "err", err)
+		return err
+	}
+
+	level.Info(o.logger).Log(
+		"msg", "estimated the API requests of fetching the release notes",
+		"commits", estimate.Commits,
+		"requests", estimate.Requests,
+		"remaining", estimate.Remaining,
+		"reset", o.formatDate(estimate.Reset, machineDateLayout),
+	)
+	if !estimate.Sufficient() {
+		level.Warn(o.logger).Log(
+			"msg", "the remaining rate limit is not sufficient, lower the budgets or wait for the reset",
+			"missing", estimate.Requests-estimate.Remaining,
+			"reset", o.formatDate(estimate.Reset, machineDateLayout),
+		)
+	}
+	return nil
+}
+
+// generate fetches or reads the release notes and writes them to the output
+func (o *options) generate() error {
+	// get the release notes
+	var releaseNotes notes.ReleaseNoteList
+	var err error
+	if o.input != "" {
+		releaseNotes, err = o.ReadInputReleaseNotes()
+	} else {
+		releaseNotes, err = o.GetReleaseNotes()
+	}
+	if err != nil {
+		return err
+	}
+
+	if o.transformCommand != "" {
+		releaseNotes, err = o.runTransformCommand(releaseNotes)
+		if err != nil {
+			level.Error(o.logger).Log("msg", "error transforming the release notes", "err", err)
+			return err
+		}
+	}
+
+	if o.stripMarkdown {
+		for _, note := range releaseNotes {
+			note.Text = notes.StripMarkdown(note.Text)
+		}
+	}
+
+	if o.interactive {
+		releaseNotes, err = reviewReleaseNotes(os.Stdin, os.Stderr, releaseNotes, o.noteMarkdown)
+		if err != nil {
+			level.Error(o.logger).Log("msg", "error reviewing release notes", "err", err)
+			return err
+		}
+	}
+
+	if o.outputDir != "" && o.format == "hugo" {
+		err = o.writeHugoSite(releaseNotes)
+	} else if o.outputDir != "" {
+		err = o.WriteSplitReleaseNotes(releaseNotes)
+	} else {
+		err = o.WriteReleaseNotes(releaseNotes)
+	}
+	if err != nil {
+		level.Error(o.logger).Log("msg", "error writing to file", "err", err)
+		return err
+	}
+
+	return nil
+}
 
 func main() {
 	// Use the go-kit structured logger for logging. To learn more about structured
@@ -365,6 +3126,10 @@ func main() {
 	logger := log.NewLogfmtLogger(log.NewSyncWriter(os.Stderr))
 
 	if err := run(logger, os.Args[1:]); err != nil {
+		// the exit code of a failed post-render command is propagated
+		if exitErr, ok := errors.Cause(err).(*exec.ExitError); ok {
+			os.Exit(exitErr.ExitCode())
+		}
 		os.Exit(-1)
 	}
 }
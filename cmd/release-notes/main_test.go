@@ -0,0 +1,301 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-github/v27/github"
+	"github.com/stretchr/testify/require"
+	"k8s.io/release/pkg/notes"
+)
+
+// newTestClient returns a client of the GitHub API of a test server with the
+// handler. The server must be closed by the caller.
+func newTestClient(t *testing.T, handler http.Handler) (*github.Client, *httptest.Server) {
+	server := httptest.NewServer(handler)
+
+	client := github.NewClient(nil)
+	baseURL, err := url.Parse(server.URL + "/")
+	require.NoError(t, err)
+	client.BaseURL = baseURL
+	return client, server
+}
+
+func TestTransportWaitingDoesNotTimeOut(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	o := &options{
+		requestTimeout:  50 * time.Millisecond,
+		hostConcurrency: 1,
+		retry5xx:        true,
+		logger:          log.NewNopLogger(),
+	}
+	client := &http.Client{Transport: o.transport()}
+
+	// the first response occupies the only slot until its body is closed
+	first, err := client.Get(server.URL)
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+	go func() {
+		resp, err := client.Get(server.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		done <- err
+	}()
+
+	time.Sleep(150 * time.Millisecond)
+	select {
+	case err := <-done:
+		t.Fatalf("request did not wait for a free slot: %v", err)
+	default:
+	}
+	require.NoError(t, first.Body.Close())
+	require.NoError(t, <-done)
+}
+
+func TestValidateAPIURL(t *testing.T) {
+	for _, tc := range []struct {
+		endpoint string
+		valid    bool
+	}{
+		{"", true},
+		{"https://github.example.com/api/v3/", true},
+		{"https://github.example.com/api/graphql", true},
+		{"github.example.com/api/v3/", false},
+		{"/api/v3/", false},
+		{"https://", false},
+		{"://github.example.com", false},
+	} {
+		err := validateAPIURL(tc.endpoint)
+		if tc.valid {
+			require.NoError(t, err, tc.endpoint)
+		} else {
+			require.Error(t, err, tc.endpoint)
+		}
+	}
+}
+
+func TestValidateTempDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "release-notes-temp-dir")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "file")
+	require.Nil(t, ioutil.WriteFile(file, nil, 0644))
+
+	require.NoError(t, validateTempDir(""))
+	require.NoError(t, validateTempDir(dir))
+	require.Error(t, validateTempDir(filepath.Join(dir, "missing")))
+	require.Error(t, validateTempDir(file))
+
+	// the probe file is removed
+	files, err := ioutil.ReadDir(dir)
+	require.Nil(t, err)
+	require.Len(t, files, 1)
+}
+
+func TestAllowedHosts(t *testing.T) {
+	o := &options{}
+	require.Equal(t, []string{"github.com", "api.github.com", "uploads.github.com"}, o.allowedHosts())
+
+	o = &options{
+		githubBaseURL:       "https://github.example.com:8443/api/v3/",
+		githubGraphQLURL:    "https://graphql.example.com/api/graphql",
+		githubHostAllowlist: "uploads.example.com, proxy.example.com",
+	}
+	require.Equal(t, []string{
+		"github.com", "api.github.com", "uploads.github.com",
+		"github.example.com", "graphql.example.com",
+		"uploads.example.com", "proxy.example.com",
+	}, o.allowedHosts())
+}
+
+func TestWriteHugoSite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hugo-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	o := &options{outputDir: dir, format: "hugo", releaseVersion: "v1.16.0", logger: log.NewNopLogger()}
+	require.NoError(t, o.writeHugoSite(notes.ReleaseNoteList{
+		1: &notes.ReleaseNote{Commit: "a", PrNumber: 1, Text: "First note", Markdown: "First note"},
+		2: &notes.ReleaseNote{Commit: "b", PrNumber: 2, Text: "Second note", Markdown: "Second note"},
+	}))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+
+	index, err := ioutil.ReadFile(filepath.Join(dir, notes.HugoIndexFile))
+	require.NoError(t, err)
+	require.Contains(t, string(index), "title: Release Notes v1.16.0\n")
+	require.Contains(t, string(index), `- [Second note]({{< relref "2.md" >}})`)
+
+	// notes must not overwrite each other
+	err = o.writeHugoSite(notes.ReleaseNoteList{
+		1: &notes.ReleaseNote{Commit: "a", PrNumber: 1, Text: "First note"},
+		2: &notes.ReleaseNote{Commit: "b", PrNumber: 1, Text: "Second note"},
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "would both be written to 1.md")
+}
+
+func TestPruneEmptySections(t *testing.T) {
+	releaseNotes := func() notes.ReleaseNoteList {
+		return notes.ReleaseNoteList{
+			1: {PrNumber: 1, Markdown: "- Add the feature ([#1](url))", Feature: true},
+			2: {PrNumber: 2, Markdown: "- Fix the bug ([#2](url))", Kinds: []string{"bug"}},
+			3: {PrNumber: 3, Markdown: "- Change something ([#3](url))"},
+		}
+	}
+	render := func(o *options, format string) string {
+		list := releaseNotes()
+		require.NoError(t, o.filterReleaseNotes(list))
+		content, err := notes.RenderToBytes(list, format, o.documentOptions()...)
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	// excluding the only bug fix empties its section
+	o := &options{excludedPRs: []int{2}, pruneEmptySections: true, logger: log.NewNopLogger()}
+	require.Equal(t, "## New Features\n\n"+
+		"- Add the feature ([#1](url))\n\n\n"+
+		"## Other Notable Changes\n\n"+
+		"- Change something ([#3](url))\n\n\n", render(o, "markdown"))
+	require.NotContains(t, render(o, "html"), "Bug Fixes")
+
+	o.pruneEmptySections = false
+	markdown := render(o, "markdown")
+	for _, heading := range []string{"## Security\n", "## Action Required\n", "## API Changes\n", "## Bug Fixes\n"} {
+		require.Contains(t, markdown, heading)
+	}
+	require.Contains(t, markdown, "## New Features\n\n- Add the feature ([#1](url))\n")
+	require.Contains(t, render(o, "html"), "<h2 id=\"bug-fixes\">Bug Fixes</h2>\n")
+
+	// streaming renders the same skeleton
+	stream := &bytes.Buffer{}
+	list := releaseNotes()
+	require.NoError(t, o.filterReleaseNotes(list))
+	require.NoError(t, notes.RenderMarkdownStream(list, stream, o.documentOptions()...))
+	require.Equal(t, markdown, stream.String())
+}
+
+func TestPreflightCreateDiscussion(t *testing.T) {
+	scopes := "read:org"
+	client, server := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-OAuth-Scopes", scopes)
+		fmt.Fprint(w, `{"resources": {}}`)
+	}))
+	defer server.Close()
+
+	o := &options{createDiscussion: true, logger: log.NewNopLogger()}
+	require.EqualError(t, o.preflight(context.Background(), client), "-create-discussion requires a GitHub token with the repo or public_repo scope")
+
+	scopes = "public_repo, read:org"
+	require.NoError(t, o.preflight(context.Background(), client))
+}
+
+func TestEstimateRequests(t *testing.T) {
+	client, server := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/kubernetes/kubernetes/compare/v1.16.0...v1.17.0":
+			fmt.Fprint(w, `{"total_commits": 100, "commits": [{"sha": "a", "commit": {"message": "Fix the tests (#1)"}}]}`)
+		case "/rate_limit":
+			fmt.Fprint(w, `{"resources": {"core": {"limit": 5000, "remaining": 150, "reset": 1571184000}}}`)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	logs := &bytes.Buffer{}
+	o := &options{
+		githubOrg:    "kubernetes",
+		githubRepo:   "kubernetes",
+		startSHA:     "v1.16.0",
+		endSHA:       "v1.17.0",
+		githubClient: client,
+		logger:       log.NewLogfmtLogger(logs),
+	}
+	require.NoError(t, o.estimateRequests())
+	require.Contains(t, logs.String(), "requests=203")
+	require.Contains(t, logs.String(), "missing=53")
+}
+
+func TestExcludeAuthor(t *testing.T) {
+	o := &options{
+		input:         filepath.Join("testdata", "exclude-author", "notes.json"),
+		excludeAuthor: "dependabot, renovate-bot",
+		logger:        log.NewNopLogger(),
+	}
+	releaseNotes, err := o.ReadInputReleaseNotes()
+	require.NoError(t, err)
+	require.Len(t, releaseNotes, 1)
+	require.Equal(t, "someone", releaseNotes[1].Author)
+}
+
+func TestExcludePRs(t *testing.T) {
+	note := func(number int, numbers ...int) *notes.ReleaseNote {
+		return &notes.ReleaseNote{
+			PrNumber:  number,
+			PrNumbers: numbers,
+			PrUrl:     fmt.Sprintf("https://github.com/kubernetes/kubernetes/pull/%d", number),
+			Author:    "foo",
+			AuthorUrl: "https://github.com/foo",
+			Text:      fmt.Sprintf("note %d", number),
+		}
+	}
+	o := &options{githubOrg: "kubernetes", githubRepo: "kubernetes", excludedPRs: []int{2, 5, 7}, logger: log.NewNopLogger()}
+
+	// the excluded PRs of collapsed notes are dropped from their links
+	releaseNotes := notes.ReleaseNoteList{1: note(1, 1, 2, 3), 4: note(4, 4, 5), 7: note(7, 7, 8)}
+	require.NoError(t, o.filterReleaseNotes(releaseNotes))
+	require.Len(t, releaseNotes, 2)
+	require.Equal(t, []int{1, 3}, releaseNotes[1].PrNumbers)
+	require.Equal(t, "note 1 ([#1](https://github.com/kubernetes/kubernetes/pull/1), [@foo](https://github.com/foo))\n\n"+
+		"  Also in [#3](https://github.com/kubernetes/kubernetes/pull/3)", releaseNotes[1].Markdown)
+	require.Nil(t, releaseNotes[4].PrNumbers)
+	require.NotContains(t, releaseNotes[4].Markdown, "Also in")
+
+	// the excluded PRs are dropped from the notes of the previous run too
+	dir, err := ioutil.TempDir("", "exclude-prs-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	other := note(2)
+	other.PrUrl = "https://github.com/kubernetes/release/pull/2"
+	existing, err := notes.RenderKeyedJSON(notes.KeyedReleaseNotes{
+		"kubernetes/kubernetes#2": note(2),
+		"kubernetes/kubernetes#3": note(3),
+		"kubernetes/kubernetes#4": note(4, 4, 5),
+		"kubernetes/release#2":    other,
+	})
+	require.NoError(t, err)
+	o.format = "json"
+	o.output = filepath.Join(dir, "notes.json")
+	require.NoError(t, ioutil.WriteFile(o.output, existing, 0644))
+
+	require.NoError(t, o.WriteReleaseNotes(notes.ReleaseNoteList{1: note(1)}))
+	content, err := ioutil.ReadFile(o.output)
+	require.NoError(t, err)
+	merged, err := notes.ParseKeyedReleaseNotes(content)
+	require.NoError(t, err)
+	require.Len(t, merged, 4)
+	require.NotContains(t, merged, "kubernetes/kubernetes#2")
+	require.Contains(t, merged, "kubernetes/release#2")
+	require.Nil(t, merged["kubernetes/kubernetes#4"].PrNumbers)
+}
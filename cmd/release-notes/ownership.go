@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v2"
+)
+
+// loadOwnershipFile parses a YAML file which maps directory prefixes to the
+// SIGs owning them, like:
+//
+//	pkg/kubelet/: node
+//	pkg/scheduler/: scheduling
+func loadOwnershipFile(path string) (map[string]string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read ownership file: %v", err)
+	}
+
+	owners := map[string]string{}
+	if err := yaml.Unmarshal(content, &owners); err != nil {
+		return nil, fmt.Errorf("unable to parse ownership file %s: %v", path, err)
+	}
+	return owners, nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/release/pkg/notes"
+)
+
+func TestLoadOwnershipFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ownership-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "owners.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(
+		"pkg/kubelet/: node\npkg/scheduler/: scheduling\n",
+	), 0644))
+
+	owners, err := loadOwnershipFile(path)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"pkg/kubelet/": "node", "pkg/scheduler/": "scheduling"}, owners)
+
+	// the notes of files without owner fall back to the uncategorized section
+	releaseNotes := notes.ReleaseNoteList{
+		1: {PrNumber: 1, Markdown: "Fix the kubelet", SIGs: notes.SIGsFromFiles([]string{"pkg/kubelet/kubelet.go"}, owners)},
+		2: {PrNumber: 2, Markdown: "Update the docs", SIGs: notes.SIGsFromFiles([]string{"docs/README.md"}, owners)},
+	}
+	doc, err := notes.CreateDocument(releaseNotes)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"node": {"Fix the kubelet"}}, doc.SIGs)
+	require.Equal(t, []string{"Update the docs"}, doc.Uncategorized)
+
+	_, err = loadOwnershipFile(filepath.Join(dir, "missing.yaml"))
+	require.Error(t, err)
+
+	for _, content := range []string{
+		"- pkg/kubelet/\n- node\n",
+		"pkg/kubelet/: [node\n",
+	} {
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+		_, err := loadOwnershipFile(path)
+		require.Error(t, err, content)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+
+	"github.com/go-kit/kit/log/level"
+
+	"k8s.io/release/pkg/notes"
+)
+
+// resolvePatchVersions tags the fetched notes with the first release of the
+// series of -release-version which contains their commit, for -patch-buckets.
+// The tags are resolved in a temporary clone of the repository. If it cannot
+// be cloned, the notes keep their version, so that they are rendered as a
+// single release.
+func (o *options) resolvePatchVersions(releaseNotes notes.ReleaseNoteList) {
+	level.Info(o.logger).Log("msg", "cloning repository to resolve the releases of the notes")
+	dir, err := notes.CloneTempRepository(o.githubOrg, o.githubRepo, o.tempDir)
+	if err != nil {
+		level.Warn(o.logger).Log("msg", "error cloning the repository, rendering the notes as a single release", "err", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	o.tagPatchVersions(releaseNotes, dir)
+}
+
+// tagPatchVersions sets the release version of every note whose commit is
+// contained in a release tag of the repository in workDir. The notes of the
+// commits after the last tag keep the version they were fetched with.
+func (o *options) tagPatchVersions(releaseNotes notes.ReleaseNoteList, workDir string) {
+	commits := make([]string, 0, len(releaseNotes))
+	for _, note := range releaseNotes {
+		commits = append(commits, note.Commit)
+	}
+
+	tags, err := notes.ContainingTags(workDir, commits, o.releaseVersion)
+	if err != nil {
+		level.Warn(o.logger).Log("msg", "error resolving the release tags, rendering the notes as a single release", "err", err)
+		return
+	}
+
+	for _, note := range releaseNotes {
+		if tag, ok := tags[note.Commit]; ok {
+			note.ReleaseVersion = tag
+		}
+	}
+	level.Info(o.logger).Log("msg", "resolved the releases of the notes", "notes", len(releaseNotes), "tagged", len(tags))
+}
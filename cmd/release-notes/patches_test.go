@@ -0,0 +1,70 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+
+	"k8s.io/release/pkg/notes"
+)
+
+func TestTagPatchVersions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "release-notes-patches")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	commits := []plumbing.Hash{}
+	for _, message := range []string{"first", "second", "third"} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "file"), []byte(message), 0644))
+		_, err := worktree.Add("file")
+		require.NoError(t, err)
+		hash, err := worktree.Commit(message, &git.CommitOptions{
+			Author: &object.Signature{Name: "a", Email: "a@example.com", When: time.Now()},
+		})
+		require.NoError(t, err)
+		commits = append(commits, hash)
+	}
+	_, err = repo.CreateTag("v1.20.1", commits[0], nil)
+	require.NoError(t, err)
+	_, err = repo.CreateTag("v1.20.2", commits[1], nil)
+	require.NoError(t, err)
+
+	releaseNotes := notes.ReleaseNoteList{
+		1: {PrNumber: 1, Commit: commits[0].String(), ReleaseVersion: "v1.20.3"},
+		2: {PrNumber: 2, Commit: commits[1].String(), ReleaseVersion: "v1.20.3"},
+		3: {PrNumber: 3, Commit: commits[2].String(), ReleaseVersion: "v1.20.3"},
+	}
+	o := &options{releaseVersion: "v1.20.3", format: "markdown", patchBuckets: true, logger: log.NewNopLogger()}
+	o.tagPatchVersions(releaseNotes, dir)
+	require.Equal(t, "v1.20.1", releaseNotes[1].ReleaseVersion)
+	require.Equal(t, "v1.20.2", releaseNotes[2].ReleaseVersion)
+	// the commits after the last tag ship in the release being generated
+	require.Equal(t, "v1.20.3", releaseNotes[3].ReleaseVersion)
+
+	content, err := notes.RenderToBytes(releaseNotes, o.format, o.documentOptions()...)
+	require.NoError(t, err)
+	require.Contains(t, string(content), "## v1.20.1\n\n")
+	require.Contains(t, string(content), "## v1.20.3\n\n")
+
+	// without the tags of the series, the notes are a single release
+	o.releaseVersion = "main"
+	for _, note := range releaseNotes {
+		note.ReleaseVersion = ""
+	}
+	o.tagPatchVersions(releaseNotes, dir)
+	for _, note := range releaseNotes {
+		require.Empty(t, note.ReleaseVersion)
+	}
+}
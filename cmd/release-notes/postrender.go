@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+)
+
+// runPostRenderCommand hands the rendered markdown file over to the
+// -post-render-command, like a script calling pandoc. The command line is run
+// with sh, the file is piped to its standard input and its path as well as the
+// -post-render-output path are appended to its arguments. The standard error of the
+// command is logged and a failure of the command wraps an *exec.ExitError, so
+// that its exit code can be propagated.
+func (o *options) runPostRenderCommand(rendered string) error {
+	input, err := os.Open(rendered)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	stderr := &bytes.Buffer{}
+	cmd := shellCommand(o.postRenderCommand, rendered, o.postRenderOutput)
+	cmd.Stdin = input
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = stderr
+
+	level.Info(o.logger).Log("msg", "running post-render command", "command", o.postRenderCommand, "rendered", rendered, "output", o.postRenderOutput)
+	err = cmd.Run()
+
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		level.Warn(o.logger).Log("msg", "post-render command", "stderr", scanner.Text())
+	}
+
+	if err != nil {
+		return errors.Wrapf(err, "post-render command %q failed", o.postRenderCommand)
+	}
+	level.Info(o.logger).Log("msg", "post-render command succeeded", "path", o.postRenderOutput)
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunPostRenderCommand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "post render-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	rendered := filepath.Join(dir, "notes.md")
+	require.NoError(t, ioutil.WriteFile(rendered, []byte("# Release notes\n"), 0644))
+
+	// the script copies its standard input to the output and records its arguments
+	script := filepath.Join(dir, "convert.sh")
+	require.NoError(t, ioutil.WriteFile(script, []byte("cat > \"$3\"\necho \"$@\" > \"$3.args\"\n"), 0644))
+
+	output := filepath.Join(dir, "notes.pdf")
+	o := &options{
+		postRenderCommand: "sh '" + script + "' --standalone",
+		postRenderOutput:  output,
+		logger:            log.NewNopLogger(),
+	}
+	require.NoError(t, o.runPostRenderCommand(rendered))
+
+	converted, err := ioutil.ReadFile(output)
+	require.NoError(t, err)
+	require.Equal(t, "# Release notes\n", string(converted))
+
+	args, err := ioutil.ReadFile(output + ".args")
+	require.NoError(t, err)
+	require.Equal(t, "--standalone "+rendered+" "+output+"\n", string(args))
+
+	failing := filepath.Join(dir, "fail.sh")
+	require.NoError(t, ioutil.WriteFile(failing, []byte("echo failed >&2\nexit 3\n"), 0644))
+	o.postRenderCommand = "sh '" + failing + "'"
+	err = o.runPostRenderCommand(rendered)
+	require.Error(t, err)
+
+	exitErr, ok := errors.Cause(err).(*exec.ExitError)
+	require.True(t, ok)
+	require.Equal(t, 3, exitErr.ExitCode())
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/release/pkg/notes"
+)
+
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiRed   = "\x1b[31m"
+	ansiCyan  = "\x1b[36m"
+)
+
+var (
+	// previewPRLink matches the markdown link to a PR, like [#123](https://...)
+	previewPRLink = regexp.MustCompile(`\[#(\d+)\]\([^)]*\)`)
+
+	// previewAuthorLink matches the markdown link to an author, like [@foo](https://...)
+	previewAuthorLink = regexp.MustCompile(`\[@([^\]]+)\]\([^)]*\)`)
+)
+
+// renderPreview writes a human readable version of the document to w. Links
+// are shortened to their text and, if color is true, section headers are
+// printed bold, PR numbers cyan and action required notes red. Every note of
+// releaseNotes with a merge time is annotated with its age relative to now and
+// the merge date in the dateLayout.
+func renderPreview(doc *notes.Document, releaseNotes notes.ReleaseNoteList, w io.Writer, color bool, now time.Time, dateLayout string) error {
+	markdown := &bytes.Buffer{}
+	if err := notes.RenderMarkdown(doc, markdown); err != nil {
+		return err
+	}
+
+	colorize := func(s, code string) string {
+		if !color {
+			return s
+		}
+		return code + s + ansiReset
+	}
+
+	mergedAt := map[int]time.Time{}
+	for _, note := range releaseNotes {
+		if note.MergedAt.IsZero() {
+			continue
+		}
+		mergedAt[note.PrNumber] = note.MergedAt
+		for _, number := range note.PrNumbers {
+			mergedAt[number] = note.MergedAt
+		}
+	}
+
+	out := &bytes.Buffer{}
+	actionRequired := false
+	scanner := bufio.NewScanner(markdown)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, "#") {
+			// subsections keep the highlighting of their section
+			if strings.HasPrefix(line, "## ") {
+				title := strings.TrimSpace(strings.TrimPrefix(line, "## "))
+				actionRequired = title == "Security" || title == "Action Required" || title == "Urgent Upgrade Notes"
+			}
+			out.WriteString(colorize(line, ansiBold) + "\n")
+			continue
+		}
+
+		annotation := ""
+		if match := previewPRLink.FindStringSubmatch(line); match != nil {
+			number, err := strconv.Atoi(match[1])
+			if t, ok := mergedAt[number]; err == nil && ok {
+				annotation = fmt.Sprintf(" (merged %s on %s)", relativeTime(t, now), t.Format(dateLayout))
+			}
+		}
+
+		line = previewAuthorLink.ReplaceAllString(line, "@$1")
+		if actionRequired && line != "" {
+			line = colorize(line, ansiRed)
+		}
+		line = previewPRLink.ReplaceAllStringFunc(line, func(link string) string {
+			number := "#" + previewPRLink.FindStringSubmatch(link)[1]
+			if !color {
+				return number
+			}
+			// restore the red of action required notes after the PR number
+			reset := ansiReset
+			if actionRequired {
+				reset += ansiRed
+			}
+			return ansiCyan + number + reset
+		})
+		out.WriteString(line + annotation + "\n")
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	_, err := io.Copy(w, out)
+	return err
+}
+
+// relativeTime describes the age of t at now in words, like "3 days ago".
+// Times less than a minute before now, as well as times after now due to clock
+// skew, are "just now".
+func relativeTime(t, now time.Time) string {
+	age := now.Sub(t)
+	unit := func(n int, name string) string {
+		if n == 1 {
+			return fmt.Sprintf("1 %s ago", name)
+		}
+		return fmt.Sprintf("%d %ss ago", n, name)
+	}
+
+	switch day := 24 * time.Hour; {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return unit(int(age/time.Minute), "minute")
+	case age < day:
+		return unit(int(age/time.Hour), "hour")
+	case age < 30*day:
+		return unit(int(age/day), "day")
+	case age < 365*day:
+		return unit(int(age/(30*day)), "month")
+	default:
+		return unit(int(age/(365*day)), "year")
+	}
+}
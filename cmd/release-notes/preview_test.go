@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/release/pkg/notes"
+)
+
+func TestRenderPreview(t *testing.T) {
+	now := time.Date(2019, 1, 10, 12, 0, 0, 0, time.UTC)
+	releaseNotes := notes.ReleaseNoteList{
+		1: &notes.ReleaseNote{
+			PrNumber:       1,
+			ActionRequired: true,
+			Markdown:       "Removed a flag ([#1](https://github.com/kubernetes/kubernetes/pull/1), [@jdoe](https://github.com/jdoe))",
+			MergedAt:       now.Add(-3 * 24 * time.Hour),
+		},
+		2: &notes.ReleaseNote{
+			PrNumber: 2,
+			Kinds:    []string{"bug"},
+			Markdown: "Fixed a bug ([#2](https://github.com/kubernetes/kubernetes/pull/2), [@alice](https://github.com/alice))",
+		},
+	}
+	doc, err := notes.CreateDocument(releaseNotes)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		color    bool
+		contains []string
+	}{
+		{
+			color: false,
+			contains: []string{
+				"## Action Required\n",
+				"- Removed a flag (#1, @jdoe) (merged 3 days ago on Jan 7, 2019)\n",
+				"- Fixed a bug (#2, @alice)\n",
+			},
+		},
+		{
+			color: true,
+			contains: []string{
+				ansiBold + "## Action Required" + ansiReset + "\n",
+				ansiRed + "- Removed a flag (" + ansiCyan + "#1" + ansiReset + ansiRed + ", @jdoe)" + ansiReset + " (merged 3 days ago on Jan 7, 2019)\n",
+				"- Fixed a bug (" + ansiCyan + "#2" + ansiReset + ", @alice)\n",
+			},
+		},
+	} {
+		out := &bytes.Buffer{}
+		require.NoError(t, renderPreview(doc, releaseNotes, out, tc.color, now, previewDateLayout))
+		for _, s := range tc.contains {
+			require.Contains(t, out.String(), s)
+		}
+		if !tc.color {
+			require.NotContains(t, out.String(), "\x1b[")
+		}
+	}
+}
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2019, 1, 10, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		t        time.Time
+		expected string
+	}{
+		{t: now, expected: "just now"},
+		{t: now.Add(time.Hour), expected: "just now"},
+		{t: now.Add(-30 * time.Second), expected: "just now"},
+		{t: now.Add(-time.Minute), expected: "1 minute ago"},
+		{t: now.Add(-5 * time.Hour), expected: "5 hours ago"},
+		{t: now.Add(-36 * time.Hour), expected: "1 day ago"},
+		{t: now.Add(-60 * 24 * time.Hour), expected: "2 months ago"},
+		{t: now.Add(-800 * 24 * time.Hour), expected: "2 years ago"},
+	} {
+		require.Equal(t, tc.expected, relativeTime(tc.t, now), "time %s", tc.t)
+	}
+}
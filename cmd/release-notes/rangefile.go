@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v2"
+)
+
+// releaseRange is the content of a release artifacts metadata file. Since JSON
+// is a subset of YAML, both formats are supported.
+type releaseRange struct {
+	StartSHA       string `yaml:"start_sha"`
+	EndSHA         string `yaml:"end_sha"`
+	ReleaseVersion string `yaml:"release_version,omitempty"`
+}
+
+// applyRangeFile populates the commit range and release version from the
+// provided file. Values which were already set via flags or environment
+// variables take precedence over the ones in the file.
+func (o *options) applyRangeFile(path string) error {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read range file: %v", err)
+	}
+
+	r := releaseRange{}
+	if err := yaml.Unmarshal(content, &r); err != nil {
+		return fmt.Errorf("unable to parse range file %s: %v", path, err)
+	}
+
+	if o.startSHA == "" && o.startRev == "" && o.startTag == "" {
+		o.startSHA = r.StartSHA
+	}
+	if o.endSHA == "" && o.endRev == "" && o.endTag == "" {
+		o.endSHA = r.EndSHA
+	}
+	if o.releaseVersion == "" {
+		o.releaseVersion = r.ReleaseVersion
+	}
+	return nil
+}
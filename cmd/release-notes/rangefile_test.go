@@ -0,0 +1,88 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyRangeFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "range-file-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	const (
+		jsonRange = `{"start_sha": "aaa", "end_sha": "bbb", "release_version": "v1.17.0"}`
+		yamlRange = "start_sha: aaa\nend_sha: bbb\nrelease_version: v1.17.0\n"
+	)
+
+	for _, tc := range []struct {
+		name     string
+		content  string
+		opts     options
+		expected options
+		err      bool
+	}{
+		{
+			name:     "json",
+			content:  jsonRange,
+			expected: options{startSHA: "aaa", endSHA: "bbb", releaseVersion: "v1.17.0"},
+		},
+		{
+			name:     "yaml",
+			content:  yamlRange,
+			expected: options{startSHA: "aaa", endSHA: "bbb", releaseVersion: "v1.17.0"},
+		},
+		{
+			name:     "partial file",
+			content:  "end_sha: bbb\n",
+			expected: options{endSHA: "bbb"},
+		},
+		{
+			name:     "shas and version take precedence",
+			content:  yamlRange,
+			opts:     options{startSHA: "ccc", endSHA: "ddd", releaseVersion: "v1.18.0"},
+			expected: options{startSHA: "ccc", endSHA: "ddd", releaseVersion: "v1.18.0"},
+		},
+		{
+			name:     "revs take precedence",
+			content:  jsonRange,
+			opts:     options{startRev: "v1.16.0", endRev: "HEAD"},
+			expected: options{startRev: "v1.16.0", endRev: "HEAD", releaseVersion: "v1.17.0"},
+		},
+		{
+			name:     "tags take precedence",
+			content:  yamlRange,
+			opts:     options{startTag: "v1.16.0", endTag: "v1.17.0"},
+			expected: options{startTag: "v1.16.0", endTag: "v1.17.0", releaseVersion: "v1.17.0"},
+		},
+		{
+			name:     "only the start is set",
+			content:  jsonRange,
+			opts:     options{startTag: "v1.16.0"},
+			expected: options{startTag: "v1.16.0", endSHA: "bbb", releaseVersion: "v1.17.0"},
+		},
+		{
+			name:    "invalid file",
+			content: "start_sha: [aaa",
+			err:     true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(dir, "range")
+			require.NoError(t, ioutil.WriteFile(path, []byte(tc.content), 0644))
+
+			o := tc.opts
+			err := o.applyRangeFile(path)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, o)
+		})
+	}
+}
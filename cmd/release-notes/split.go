@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/go-kit/kit/log/level"
+
+	"k8s.io/release/pkg/notes"
+)
+
+// outputPart is a file the output was split into
+type outputPart struct {
+	File  string `json:"file"`
+	Bytes int    `json:"bytes"`
+}
+
+// outputIndex lists the files the output was split into, in order
+type outputIndex struct {
+	Parts []outputPart `json:"parts"`
+}
+
+// splitIndexPath returns the path of the index of the parts of the output at
+// path
+func splitIndexPath(path string) string {
+	return path + ".index.json"
+}
+
+// readSplitIndex reads the index of the parts of the output at path, or
+// returns nil if the output was not split
+func readSplitIndex(path string) (*outputIndex, error) {
+	content, err := ioutil.ReadFile(splitIndexPath(path))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	index := &outputIndex{}
+	if err := json.Unmarshal(content, index); err != nil {
+		return nil, fmt.Errorf("unable to parse the index %s: %v", splitIndexPath(path), err)
+	}
+	return index, nil
+}
+
+// readSplitJSON joins the parts of the JSON output at path, which was split by
+// a previous run, into a single JSON object. It returns nil if the output was
+// not split.
+func readSplitJSON(path string) ([]byte, error) {
+	index, err := readSplitIndex(path)
+	if err != nil || index == nil {
+		return nil, err
+	}
+	releaseNotes := notes.KeyedReleaseNotes{}
+	for _, part := range index.Parts {
+		content, err := ioutil.ReadFile(filepath.Join(filepath.Dir(path), part.File))
+		if err != nil {
+			return nil, err
+		}
+		partNotes, err := notes.ParseKeyedReleaseNotes(content)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the part %s of the output: %v", part.File, err)
+		}
+		for key, note := range partNotes {
+			releaseNotes[key] = note
+		}
+	}
+	return notes.RenderKeyedJSON(releaseNotes)
+}
+
+// removeSplitOutput removes the index and the parts of the output at path
+// which were written by a previous run, along with their checksums
+func removeSplitOutput(path string) error {
+	index, err := readSplitIndex(path)
+	if err != nil || index == nil {
+		return err
+	}
+	for _, part := range index.Parts {
+		partPath := filepath.Join(filepath.Dir(path), part.File)
+		for _, file := range []string{partPath, partPath + ".sha256"} {
+			if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return os.Remove(splitIndexPath(path))
+}
+
+// splitOutput splits the output at path into the files path.1, path.2, etc. of
+// at most -max-file-bytes each if it exceeds the limit, writes an index of the
+// parts and returns their paths. The markdown is split at the boundaries of
+// its sections and the JSON into objects of whole notes. The parts of a
+// previous run are removed first, and the output itself is left in place to
+// be published as a whole.
+func (o *options) splitOutput(path string) ([]string, error) {
+	if err := removeSplitOutput(path); err != nil {
+		return nil, err
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(content) <= o.maxFileBytes {
+		return nil, nil
+	}
+
+	var parts [][]byte
+	if o.format == "json" {
+		releaseNotes, err := notes.ParseKeyedReleaseNotes(content)
+		if err != nil {
+			return nil, err
+		}
+		parts, err = notes.SplitKeyedJSON(releaseNotes, o.maxFileBytes)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		parts, err = notes.SplitMarkdown(content, o.maxFileBytes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	index := outputIndex{}
+	paths := []string{}
+	for i, part := range parts {
+		partPath := fmt.Sprintf("%s.%d", path, i+1)
+		if err := ioutil.WriteFile(partPath, part, 0644); err != nil {
+			return nil, err
+		}
+		index.Parts = append(index.Parts, outputPart{File: filepath.Base(partPath), Bytes: len(part)})
+		paths = append(paths, partPath)
+	}
+
+	indexContent, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(splitIndexPath(path), append(indexContent, '\n'), 0644); err != nil {
+		return nil, err
+	}
+	level.Info(o.logger).Log(
+		"msg", "split the output which exceeds the size limit",
+		"path", path,
+		"parts", len(parts),
+		"index", splitIndexPath(path),
+	)
+	return paths, nil
+}
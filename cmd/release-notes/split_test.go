@@ -0,0 +1,79 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/release/pkg/notes"
+)
+
+func TestSplitJSONOutput(t *testing.T) {
+	dir, err := ioutil.TempDir("", "split-output-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	o := &options{
+		format:       "json",
+		output:       filepath.Join(dir, "notes.json"),
+		maxFileBytes: 600,
+		checksum:     true,
+		logger:       log.NewNopLogger(),
+	}
+	note := func(number int) *notes.ReleaseNote {
+		return &notes.ReleaseNote{
+			PrNumber: number,
+			PrUrl:    fmt.Sprintf("https://github.com/kubernetes/kubernetes/pull/%d", number),
+			Text:     fmt.Sprintf("note %d", number),
+		}
+	}
+	require.NoError(t, o.WriteReleaseNotes(notes.ReleaseNoteList{1: note(1), 2: note(2), 3: note(3)}))
+
+	// the output is replaced by its parts, which have a checksum each
+	_, err = os.Stat(o.output)
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(o.output + ".sha256")
+	require.True(t, os.IsNotExist(err))
+	index, err := readSplitIndex(o.output)
+	require.NoError(t, err)
+	require.True(t, len(index.Parts) > 1)
+	for _, part := range index.Parts {
+		content, err := ioutil.ReadFile(filepath.Join(dir, part.File))
+		require.NoError(t, err)
+		require.Len(t, content, part.Bytes)
+		digest := sha256.Sum256(content)
+		checksum, err := ioutil.ReadFile(filepath.Join(dir, part.File+".sha256"))
+		require.NoError(t, err)
+		require.Equal(t, hex.EncodeToString(digest[:])+"  "+part.File+"\n", string(checksum))
+	}
+
+	// the next run merges its notes with the parts
+	require.NoError(t, o.WriteReleaseNotes(notes.ReleaseNoteList{4: note(4)}))
+	content, err := readSplitJSON(o.output)
+	require.NoError(t, err)
+	merged, err := notes.ParseKeyedReleaseNotes(content)
+	require.NoError(t, err)
+	require.Len(t, merged, 4)
+
+	// an output within the limit replaces the parts of the previous run
+	o.maxFileBytes = 1 << 20
+	require.NoError(t, o.WriteReleaseNotes(notes.ReleaseNoteList{5: note(5)}))
+	index, err = readSplitIndex(o.output)
+	require.NoError(t, err)
+	require.Nil(t, index)
+	files, err := filepath.Glob(o.output + ".[0-9]*")
+	require.NoError(t, err)
+	require.Empty(t, files)
+	content, err = ioutil.ReadFile(o.output)
+	require.NoError(t, err)
+	merged, err = notes.ParseKeyedReleaseNotes(content)
+	require.NoError(t, err)
+	require.Len(t, merged, 5)
+}
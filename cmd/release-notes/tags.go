@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/log/level"
+
+	"k8s.io/release/pkg/notes"
+)
+
+// resolveTags sets the start and end SHA to the commits of -start-tag and
+// -end-tag, which are resolved with the GitHub API. A tag which cannot be
+// resolved with the API becomes the -start-rev or -end-rev, so that it is
+// resolved in a clone of the repository instead.
+func (o *options) resolveTags() error {
+	client, err := o.newGithubClient(context.Background())
+	if err != nil {
+		return err
+	}
+	opts := []notes.GithubApiOption{notes.WithOrg(o.githubOrg), notes.WithRepo(o.githubRepo)}
+
+	resolve := func(tag string, sha, rev *string) {
+		resolved, err := notes.TagCommitSHA(client, tag, opts...)
+		if err != nil {
+			level.Warn(o.logger).Log("msg", "error resolving the tag with the API, resolving it in a clone", "tag", tag, "err", err)
+			*rev = tag
+			return
+		}
+		level.Info(o.logger).Log("msg", "resolved the tag", "tag", tag, "sha", resolved)
+		*sha = resolved
+	}
+	if o.startTag != "" {
+		resolve(o.startTag, &o.startSHA, &o.startRev)
+	}
+	if o.endTag != "" {
+		resolve(o.endTag, &o.endSHA, &o.endRev)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveTags(t *testing.T) {
+	client, server := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/kubernetes/kubernetes/git/refs/tags/v1.16.0":
+			fmt.Fprint(w, `{"ref": "refs/tags/v1.16.0", "object": {"type": "tag", "sha": "t16"}}`)
+		case "/repos/kubernetes/kubernetes/git/tags/t16":
+			fmt.Fprint(w, `{"sha": "t16", "object": {"type": "commit", "sha": "aaa"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	o := &options{
+		githubOrg:    "kubernetes",
+		githubRepo:   "kubernetes",
+		startTag:     "v1.16.0",
+		endTag:       "v1.17.0",
+		githubClient: client,
+		logger:       log.NewNopLogger(),
+	}
+	require.NoError(t, o.resolveTags())
+	require.Equal(t, "aaa", o.startSHA)
+	require.Empty(t, o.startRev)
+	// the tag which cannot be resolved with the API is resolved in a clone
+	require.Empty(t, o.endSHA)
+	require.Equal(t, "v1.17.0", o.endRev)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-kit/kit/log/level"
+
+	"k8s.io/release/pkg/notes"
+)
+
+// repeatedFlag collects the values of a flag which may be passed several
+// times. The values from the environment are defaults, which are replaced as
+// a whole by the values passed on the command line.
+type repeatedFlag struct {
+	values    []string
+	isDefault bool
+}
+
+// newRepeatedFlag returns a flag whose default is the single value, if set
+func newRepeatedFlag(value string) *repeatedFlag {
+	r := &repeatedFlag{isDefault: true}
+	if value != "" {
+		r.values = []string{value}
+	}
+	return r
+}
+
+func (r *repeatedFlag) String() string {
+	if r == nil {
+		return ""
+	}
+	return strings.Join(r.values, " ")
+}
+
+func (r *repeatedFlag) Set(value string) error {
+	if r.isDefault {
+		r.values = nil
+		r.isDefault = false
+	}
+	r.values = append(r.values, value)
+	return nil
+}
+
+// compileTitlePatterns compiles the -exclude-title-pattern expressions
+func compileTitlePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		exp, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, exp)
+	}
+	return compiled, nil
+}
+
+// excludeByTitle drops the notes whose PR title matches one of the
+// -exclude-title-pattern expressions, and logs how many notes every pattern
+// removed, so that the patterns can be tuned. A note counts for the first
+// pattern it matches.
+func (o *options) excludeByTitle(releaseNotes notes.ReleaseNoteList) {
+	if len(o.parsedTitlePatterns) == 0 {
+		return
+	}
+
+	numbers := []int{}
+	for number := range releaseNotes {
+		numbers = append(numbers, number)
+	}
+	sort.Ints(numbers)
+
+	removed := make([]int, len(o.parsedTitlePatterns))
+	for _, number := range numbers {
+		note := releaseNotes[number]
+		for i, exp := range o.parsedTitlePatterns {
+			if exp.MatchString(note.PrTitle) {
+				level.Debug(o.logger).Log("msg", "excluding release note by title", "pr", number, "title", note.PrTitle, "pattern", exp.String())
+				delete(releaseNotes, number)
+				removed[i]++
+				break
+			}
+		}
+	}
+
+	for i, exp := range o.parsedTitlePatterns {
+		level.Info(o.logger).Log("msg", "excluded release notes by title", "pattern", exp.String(), "notes", removed[i])
+	}
+}
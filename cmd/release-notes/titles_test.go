@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExcludeByTitle(t *testing.T) {
+	patterns, err := compileTitlePatterns([]string{"^Update Go to", "(?i)^bump image", "^Revert"})
+	require.NoError(t, err)
+
+	logs := &bytes.Buffer{}
+	o := &options{
+		input:               filepath.Join("testdata", "exclude-title", "notes.json"),
+		parsedTitlePatterns: patterns,
+		logger:              log.NewLogfmtLogger(logs),
+	}
+	releaseNotes, err := o.ReadInputReleaseNotes()
+	require.NoError(t, err)
+	require.Len(t, releaseNotes, 1)
+	require.Equal(t, "Fix the kubelet restart loop", releaseNotes[1].PrTitle)
+	require.Contains(t, logs.String(), `pattern="^Update Go to" notes=1`)
+	require.Contains(t, logs.String(), `pattern=^Revert notes=0`)
+
+	_, err = compileTitlePatterns([]string{"("})
+	require.Error(t, err)
+}
+
+func TestRepeatedFlag(t *testing.T) {
+	parse := func(def string, args ...string) []string {
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		patterns := newRepeatedFlag(def)
+		flags.Var(patterns, "exclude-title-pattern", "")
+		require.NoError(t, flags.Parse(args))
+		return patterns.values
+	}
+
+	require.Empty(t, parse(""))
+	require.Equal(t, []string{"^Bump"}, parse("^Bump"))
+	// the command line replaces the default from the environment
+	require.Equal(t, []string{"^Update", "^Revert"}, parse("^Bump", "-exclude-title-pattern", "^Update", "-exclude-title-pattern", "^Revert"))
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+
+	"k8s.io/release/pkg/notes"
+)
+
+// shellCommand returns the command which runs the command line with sh, so that
+// it can quote paths and arguments with spaces. The args are appended to the
+// command line as the positional parameters of the shell, which keeps them
+// intact whatever they contain.
+func shellCommand(command string, args ...string) *exec.Cmd {
+	if len(args) > 0 {
+		command += ` "$@"`
+	}
+	return exec.Command("sh", append([]string{"-c", command, "sh"}, args...)...)
+}
+
+// runTransformCommand hands the fetched notes over to the -transform-command,
+// like a script applying organization specific changes. The command line is run
+// with sh and the notes are piped to
+// the standard input of the command as JSON, in the format of the JSON output,
+// and the notes it writes to its standard output are rendered instead. The
+// standard error of the command is logged and a failure of the command wraps
+// an *exec.ExitError, so that its exit code can be propagated.
+func (o *options) runTransformCommand(releaseNotes notes.ReleaseNoteList) (notes.ReleaseNoteList, error) {
+	input, err := json.Marshal(releaseNotes)
+	if err != nil {
+		return nil, err
+	}
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := shellCommand(o.transformCommand)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	level.Info(o.logger).Log("msg", "running transform command", "command", o.transformCommand, "notes", len(releaseNotes))
+	err = cmd.Run()
+
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		level.Warn(o.logger).Log("msg", "transform command", "stderr", scanner.Text())
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "transform command %q failed", o.transformCommand)
+	}
+
+	transformed := notes.ReleaseNoteList{}
+	if err := json.Unmarshal(stdout.Bytes(), &transformed); err != nil {
+		return nil, fmt.Errorf("transform command %q did not write the notes as JSON to its standard output: %v", o.transformCommand, err)
+	}
+	level.Info(o.logger).Log("msg", "transform command succeeded", "notes", len(transformed))
+	return transformed, nil
+}
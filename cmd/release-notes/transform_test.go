@@ -0,0 +1,49 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/release/pkg/notes"
+)
+
+func TestRunTransformCommand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "transform command-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	releaseNotes := notes.ReleaseNoteList{
+		1: {PrNumber: 1, PrUrl: "https://github.com/kubernetes/kubernetes/pull/1", Text: "fixed the kubelet"},
+	}
+
+	o := &options{transformCommand: "sed 's/the kubelet/the Kubelet/'", logger: log.NewNopLogger()}
+	transformed, err := o.runTransformCommand(releaseNotes)
+	require.NoError(t, err)
+	require.Len(t, transformed, 1)
+	require.Equal(t, "fixed the Kubelet", transformed[1].Text)
+
+	// the hook must write the notes back as JSON
+	invalid := filepath.Join(dir, "invalid.sh")
+	require.NoError(t, ioutil.WriteFile(invalid, []byte("cat > /dev/null\necho done\n"), 0644))
+	o.transformCommand = "sh '" + invalid + "'"
+	_, err = o.runTransformCommand(releaseNotes)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "did not write the notes as JSON")
+
+	failing := filepath.Join(dir, "fail.sh")
+	require.NoError(t, ioutil.WriteFile(failing, []byte("echo failed >&2\nexit 3\n"), 0644))
+	o.transformCommand = "sh '" + failing + "'"
+	_, err = o.runTransformCommand(releaseNotes)
+	require.Error(t, err)
+
+	exitErr, ok := errors.Cause(err).(*exec.ExitError)
+	require.True(t, ok)
+	require.Equal(t, 3, exitErr.ExitCode())
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"k8s.io/release/pkg/notes"
+)
+
+// labelDimensions are the label prefixes which may be required by
+// -require-labels
+var labelDimensions = []string{"sig", "kind", "area"}
+
+// missingLabels returns the required label dimensions which the note has no
+// label of. Kinds which were inferred from the PR title do not count as
+// labels.
+func missingLabels(note *notes.ReleaseNote, dimensions []string) []string {
+	missing := []string{}
+	for _, dimension := range dimensions {
+		var labels []string
+		switch dimension {
+		case "sig":
+			labels = note.SIGs
+		case "kind":
+			if !note.KindInferred {
+				labels = note.Kinds
+			}
+		case "area":
+			labels = note.Areas
+		}
+		if len(labels) == 0 {
+			missing = append(missing, dimension)
+		}
+	}
+	return missing
+}
+
+// writeUnlabeled writes a line for every note which lacks one of the required
+// label dimensions to w and returns their number. The lines are tab separated
+// and contain the PR number, the comma separated missing dimensions and the PR
+// URL, like "123\tsig,kind\thttps://github.com/kubernetes/kubernetes/pull/123".
+func writeUnlabeled(releaseNotes notes.ReleaseNoteList, dimensions []string, w io.Writer) (int, error) {
+	numbers := []int{}
+	for number := range releaseNotes {
+		numbers = append(numbers, number)
+	}
+	sort.Ints(numbers)
+
+	unlabeled := 0
+	for _, number := range numbers {
+		note := releaseNotes[number]
+		missing := missingLabels(note, dimensions)
+		if len(missing) == 0 {
+			continue
+		}
+		unlabeled++
+		if _, err := fmt.Fprintf(w, "%d\t%s\t%s\n", number, strings.Join(missing, ","), note.PrUrl); err != nil {
+			return unlabeled, err
+		}
+	}
+	return unlabeled, nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/release/pkg/notes"
+)
+
+func TestWriteUnlabeled(t *testing.T) {
+	releaseNotes := notes.ReleaseNoteList{
+		1:  &notes.ReleaseNote{PrNumber: 1, PrUrl: "https://github.com/kubernetes/kubernetes/pull/1", SIGs: []string{"node"}, Kinds: []string{"bug"}},
+		2:  &notes.ReleaseNote{PrNumber: 2, PrUrl: "https://github.com/kubernetes/kubernetes/pull/2", Kinds: []string{"bug"}},
+		3:  &notes.ReleaseNote{PrNumber: 3, PrUrl: "https://github.com/kubernetes/kubernetes/pull/3", SIGs: []string{"node"}, Kinds: []string{"bug"}, KindInferred: true},
+		10: &notes.ReleaseNote{PrNumber: 10, PrUrl: "https://github.com/kubernetes/kubernetes/pull/10"},
+	}
+
+	out := &bytes.Buffer{}
+	unlabeled, err := writeUnlabeled(releaseNotes, []string{"sig", "kind"}, out)
+	require.NoError(t, err)
+	require.Equal(t, 3, unlabeled)
+	require.Equal(t, "2\tsig\thttps://github.com/kubernetes/kubernetes/pull/2\n"+
+		"3\tkind\thttps://github.com/kubernetes/kubernetes/pull/3\n"+
+		"10\tsig,kind\thttps://github.com/kubernetes/kubernetes/pull/10\n", out.String())
+
+	out.Reset()
+	unlabeled, err = writeUnlabeled(releaseNotes, []string{"sig"}, out)
+	require.NoError(t, err)
+	require.Equal(t, 2, unlabeled)
+	require.NotContains(t, out.String(), "3\t")
+}
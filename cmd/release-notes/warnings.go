@@ -0,0 +1,22 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/go-kit/kit/log/level"
+)
+
+// writeWarnings writes the warnings collected during the run to the
+// -warnings-file as a JSON array, which is empty if there were none
+func (o *options) writeWarnings() error {
+	content, err := json.MarshalIndent(o.warnings.List(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(o.warningsFile, append(content, '\n'), 0644); err != nil {
+		return err
+	}
+	level.Info(o.logger).Log("msg", "warnings written to file", "path", o.warningsFile, "warnings", len(o.warnings.List()))
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/release/pkg/notes"
+)
+
+func TestWriteWarnings(t *testing.T) {
+	dir, err := ioutil.TempDir("", "release-notes-warnings")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	o := &options{
+		input:         filepath.Join("testdata", "exclude-author", "notes.json"),
+		maxNoteLength: 35,
+		reportStyle:   true,
+		warningsFile:  filepath.Join(dir, "warnings.json"),
+		warnings:      &notes.Warnings{},
+		logger:        log.NewNopLogger(),
+	}
+	_, err = o.ReadInputReleaseNotes()
+	require.NoError(t, err)
+	require.NoError(t, o.writeWarnings())
+
+	content, err := ioutil.ReadFile(o.warningsFile)
+	require.NoError(t, err)
+	warnings := []notes.Warning{}
+	require.NoError(t, json.Unmarshal(content, &warnings))
+	require.Equal(t, []notes.Warning{
+		{PrNumber: 1, Category: notes.WarningStyle, Message: "the note violates the style: does not end with a period"},
+		{PrNumber: 2, Category: notes.WarningStyle, Message: "the note violates the style: does not end with a period"},
+		{PrNumber: 2, Category: notes.WarningTooLong, Message: "the note of 41 characters exceeds the maximum length of 35 characters"},
+		{PrNumber: 3, Category: notes.WarningStyle, Message: "the note violates the style: does not end with a period"},
+	}, warnings)
+
+	// without warnings, the file holds an empty array
+	o.warnings = &notes.Warnings{}
+	require.NoError(t, o.writeWarnings())
+	content, err = ioutil.ReadFile(o.warningsFile)
+	require.NoError(t, err)
+	require.Equal(t, "[]\n", string(content))
+}
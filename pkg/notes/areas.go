@@ -0,0 +1,52 @@
+package notes
+
+import "sort"
+
+// NoteGrouping is the label prefix the notes are grouped by in the flat
+// layout, besides the sections of their kind
+type NoteGrouping string
+
+const (
+	// GroupBySIG lists a note under every SIG it belongs to, or in the
+	// section of notes from multiple SIGs. This is the default.
+	GroupBySIG NoteGrouping = "sig"
+
+	// GroupByArea lists a note under a single one of its area labels, which
+	// is the first one of WithAreaPriority. The notes without an area are
+	// listed like the notes without a SIG.
+	GroupByArea NoteGrouping = "area"
+)
+
+// WithGroupBy allows the caller to group the notes of the flat layout by
+// another label prefix than the SIG. By default, it is GroupBySIG.
+func WithGroupBy(grouping NoteGrouping) DocumentOption {
+	return func(c *documentConfig) {
+		c.groupBy = grouping
+	}
+}
+
+// WithAreaPriority allows the caller to select the area a note with multiple
+// area labels is listed under with GroupByArea. The first area of the list
+// which the note has wins, areas which are not part of the list come after
+// the listed ones in alphabetical order.
+func WithAreaPriority(areas []string) DocumentOption {
+	return func(c *documentConfig) {
+		c.areaPriority = areas
+	}
+}
+
+// primaryArea returns the area a note is listed under with GroupByArea, or an
+// empty string if the note has no area
+func primaryArea(note *ReleaseNote, priority []string) string {
+	for _, area := range priority {
+		if HasString(note.Areas, area) {
+			return area
+		}
+	}
+	if len(note.Areas) == 0 {
+		return ""
+	}
+	areas := append([]string{}, note.Areas...)
+	sort.Strings(areas)
+	return areas[0]
+}
@@ -0,0 +1,72 @@
+package notes
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupByArea(t *testing.T) {
+	notes := ReleaseNoteList{
+		1: &ReleaseNote{PrNumber: 1, Markdown: "fixed the kubelet and kubectl", Areas: []string{"kubectl", "kubelet"}, SIGs: []string{"cli", "node"}, Duplicate: true},
+		2: &ReleaseNote{PrNumber: 2, Markdown: "sped up kubectl and the scheduler", Areas: []string{"scheduler", "kubectl"}},
+		3: &ReleaseNote{PrNumber: 3, Markdown: "tuned the kubelet", Areas: []string{"kubelet"}, SIGs: []string{"node"}},
+		4: &ReleaseNote{PrNumber: 4, Markdown: "fixed a crash", Kinds: []string{"bug"}, SIGs: []string{"node"}},
+		5: &ReleaseNote{PrNumber: 5, Markdown: "changed something", SIGs: []string{"node"}},
+	}
+	opts := []DocumentOption{WithGroupBy(GroupByArea), WithAreaPriority([]string{"kubelet"})}
+
+	doc, err := CreateDocument(notes, opts...)
+	require.Nil(t, err)
+	// a note with several areas is listed once, under the first area of the
+	// priority or else its alphabetically first area
+	require.Equal(t, map[string][]string{
+		"kubelet": {"fixed the kubelet and kubectl", "tuned the kubelet"},
+		"kubectl": {"sped up kubectl and the scheduler"},
+	}, doc.Areas)
+	require.Empty(t, doc.SIGs)
+	require.Empty(t, doc.Duplicates)
+	// the notes without an area are listed like the notes without a SIG
+	require.Equal(t, []string{"fixed a crash"}, doc.BugFixes)
+	require.Equal(t, []string{"changed something"}, doc.Uncategorized)
+
+	expected := `## Notes by Area
+
+### Area Kubectl
+
+- sped up kubectl and the scheduler
+
+### Area Kubelet
+
+- fixed the kubelet and kubectl
+- tuned the kubelet
+
+
+
+## Bug Fixes
+
+- fixed a crash
+
+
+## Other Notable Changes
+
+- changed something
+
+
+`
+	buf := &bytes.Buffer{}
+	require.Nil(t, RenderMarkdown(doc, buf, opts...))
+	require.Equal(t, expected, buf.String())
+
+	buf = &bytes.Buffer{}
+	require.Nil(t, RenderMarkdownStream(notes, buf, opts...))
+	require.Equal(t, expected, buf.String())
+
+	// the skeleton holds the area section instead of the SIG sections
+	buf = &bytes.Buffer{}
+	require.Nil(t, RenderMarkdownStream(ReleaseNoteList{}, buf, append(opts, WithEmptySections())...))
+	require.Contains(t, buf.String(), "## Notes by Area")
+	require.NotContains(t, buf.String(), "## Notes from Individual SIGs")
+	require.NotContains(t, buf.String(), "## Notes From Multiple SIGs")
+}
@@ -0,0 +1,170 @@
+package notes
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/google/go-github/v27/github"
+	"github.com/pkg/errors"
+)
+
+// DefaultReleaseBranchPattern is the naming convention of the Kubernetes
+// release branches, like release-1.20
+const DefaultReleaseBranchPattern = "release-{major}.{minor}"
+
+// WithTrackBranches allows the caller to provide a list of branches, like
+// "release-1.19", which are checked for every note whether they contain the
+// commit of the note.
+func WithTrackBranches(branches []string) GithubApiOption {
+	return func(c *githubApiConfig) {
+		c.trackBranches = branches
+	}
+}
+
+// BranchesContainingCommit returns the subset of branches which contain the
+// commit with the provided SHA. It uses the compare API: a branch contains the
+// commit if the commit is behind or identical to the tip of the branch.
+func BranchesContainingCommit(client *github.Client, sha string, branches []string, opts ...GithubApiOption) ([]string, error) {
+	c := configFromOpts(opts...)
+
+	containing := []string{}
+	for _, branch := range branches {
+		comparison, _, err := client.Repositories.CompareCommits(c.ctx, c.org, c.repo, branch, sha)
+		if err != nil {
+			return nil, err
+		}
+		switch comparison.GetStatus() {
+		case "behind", "identical":
+			containing = append(containing, branch)
+		}
+	}
+	return containing, nil
+}
+
+// VerifyRangeOnBranch checks that the start and end commits are reachable
+// from the tip of the branch, which catches ranges taken from another branch.
+// The error names the commits which are not part of the branch.
+func VerifyRangeOnBranch(client *github.Client, branch, start, end string, opts ...GithubApiOption) error {
+	for _, commit := range []struct{ name, sha string }{{"start", start}, {"end", end}} {
+		containing, err := BranchesContainingCommit(client, commit.sha, []string{branch}, opts...)
+		if err != nil {
+			return classifyError(errors.Wrapf(err, "error checking whether branch %s contains the %s commit %s", branch, commit.name, commit.sha), ErrInvalidRange)
+		}
+		if len(containing) == 0 {
+			return &Error{
+				Cause: ErrInvalidRange,
+				Err:   errors.Errorf("the %s commit %s is not reachable from the tip of branch %s", commit.name, commit.sha, branch),
+			}
+		}
+	}
+	return nil
+}
+
+// RangeGap returns whether there are commits between the end of an already
+// covered range and the start of the next range. There is no gap if the next
+// range starts at or before the covered end, while diverged commits are an
+// error.
+func RangeGap(client *github.Client, coveredEnd, start string, opts ...GithubApiOption) (bool, error) {
+	c := configFromOpts(opts...)
+
+	comparison, _, err := client.Repositories.CompareCommits(c.ctx, c.org, c.repo, coveredEnd, start)
+	if err != nil {
+		return false, classifyError(errors.Wrapf(err, "error comparing %s with %s", start, coveredEnd), ErrInvalidRange)
+	}
+
+	switch comparison.GetStatus() {
+	case "identical", "behind":
+		return false, nil
+	case "ahead":
+		return true, nil
+	default:
+		return false, &Error{
+			Cause: ErrInvalidRange,
+			Err:   errors.Errorf("the start %s is not a descendant of the covered end %s", start, coveredEnd),
+		}
+	}
+}
+
+// trackedBranches returns the tracked branches which contain the commit with
+// the provided SHA. Failures are logged and result in no branches, because the
+// branches are informational only.
+func trackedBranches(client *github.Client, logger log.Logger, sha string, opts ...GithubApiOption) []string {
+	c := configFromOpts(opts...)
+	if len(c.trackBranches) == 0 {
+		return nil
+	}
+
+	branches, err := BranchesContainingCommit(client, sha, c.trackBranches, opts...)
+	if err != nil {
+		level.Warn(logger).Log(
+			"msg", "error checking which tracked branches contain the commit",
+			"sha", sha,
+			"err", err,
+		)
+		c.warnings.Add(Warning{
+			Commit:   sha,
+			Category: WarningFetchFailed,
+			Message:  "error checking which tracked branches contain the commit: " + err.Error(),
+		})
+		return nil
+	}
+	return branches
+}
+
+// branchesSuffix renders a compact list of branches for the markdown of a
+// note, like "[1.19, 1.20]" for the branches release-1.19 and release-1.20
+func branchesSuffix(branches []string) string {
+	short := []string{}
+	for _, branch := range branches {
+		short = append(short, strings.TrimPrefix(branch, "release-"))
+	}
+	return fmt.Sprintf("[%s]", strings.Join(short, ", "))
+}
+
+// ReleaseBranchName returns the name of the release branch for the provided
+// version, like release-1.20 for v1.20.0. The {major} and {minor} placeholders
+// of the pattern are replaced with the respective version parts.
+func ReleaseBranchName(version, pattern string) (string, error) {
+	match := regexp.MustCompile(`^v?(\d+)\.(\d+)`).FindStringSubmatch(version)
+	if match == nil {
+		return "", errors.Errorf("unable to parse the minor version of %q", version)
+	}
+	return strings.NewReplacer("{major}", match[1], "{minor}", match[2]).Replace(pattern), nil
+}
+
+// ReleaseBranchRange returns the range of commits of a release branch since
+// it forked from the base branch: the merge base of both branches as start
+// and the tip of the release branch as end.
+func ReleaseBranchRange(client *github.Client, base, branch string, opts ...GithubApiOption) (start, end string, err error) {
+	c := configFromOpts(opts...)
+
+	start, err = MergeBase(client, base, branch, opts...)
+	if err != nil {
+		return "", "", err
+	}
+
+	tip, _, err := client.Repositories.GetBranch(c.ctx, c.org, c.repo, branch)
+	if err != nil {
+		return "", "", classifyError(errors.Wrapf(err, "error getting branch %s", branch), ErrBranchNotFound)
+	}
+
+	return start, tip.GetCommit().GetSHA(), nil
+}
+
+// MergeBase returns the SHA of the best common ancestor of two git refs, as
+// computed by the compare API.
+func MergeBase(client *github.Client, base, head string, opts ...GithubApiOption) (string, error) {
+	c := configFromOpts(opts...)
+
+	comparison, _, err := client.Repositories.CompareCommits(c.ctx, c.org, c.repo, base, head)
+	if err != nil {
+		return "", classifyError(errors.Wrapf(err, "error comparing %s with %s", head, base), ErrBranchNotFound)
+	}
+	if comparison.GetMergeBaseCommit().GetSHA() == "" {
+		return "", errors.Errorf("%s and %s have no common ancestor", base, head)
+	}
+	return comparison.GetMergeBaseCommit().GetSHA(), nil
+}
@@ -0,0 +1,101 @@
+package notes
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleaseBranchName(t *testing.T) {
+	testCases := []struct {
+		version  string
+		pattern  string
+		expected string
+	}{
+		{version: "v1.20.0", pattern: DefaultReleaseBranchPattern, expected: "release-1.20"},
+		{version: "1.19.3", pattern: DefaultReleaseBranchPattern, expected: "release-1.19"},
+		{version: "v1.21.0-rc.1", pattern: DefaultReleaseBranchPattern, expected: "release-1.21"},
+		{version: "v2.3.0", pattern: "release/v{major}.{minor}.x", expected: "release/v2.3.x"},
+	}
+
+	for _, tc := range testCases {
+		branch, err := ReleaseBranchName(tc.version, tc.pattern)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, branch)
+	}
+
+	_, err := ReleaseBranchName("latest", DefaultReleaseBranchPattern)
+	require.Error(t, err)
+}
+
+func TestBranchesSuffix(t *testing.T) {
+	require.Equal(t, "[1.19, 1.20]", branchesSuffix([]string{"release-1.19", "release-1.20"}))
+	require.Equal(t, "[main]", branchesSuffix([]string{"main"}))
+}
+
+func TestRangeGap(t *testing.T) {
+	client, server := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		statuses := map[string]string{
+			"end...end":     "identical",
+			"end...older":   "behind",
+			"end...newer":   "ahead",
+			"end...feature": "diverged",
+		}
+		fmt.Fprintf(w, `{"status": %q}`, statuses[path.Base(r.URL.Path)])
+	}))
+	defer server.Close()
+
+	for start, expected := range map[string]bool{"end": false, "older": false, "newer": true} {
+		gap, err := RangeGap(client, "end", start)
+		require.Nil(t, err)
+		require.Equal(t, expected, gap, start)
+	}
+
+	_, err := RangeGap(client, "end", "feature")
+	require.NotNil(t, err)
+}
+
+func TestVerifyRangeOnBranch(t *testing.T) {
+	client, server := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the feature commit was not merged into master
+		status := "behind"
+		if strings.HasSuffix(r.URL.Path, "...feature") {
+			status = "diverged"
+		}
+		fmt.Fprintf(w, `{"status": %q}`, status)
+	}))
+	defer server.Close()
+
+	require.Nil(t, VerifyRangeOnBranch(client, "master", "start", "end"))
+
+	err := VerifyRangeOnBranch(client, "master", "start", "feature")
+	require.EqualError(t, err, "the end commit feature is not reachable from the tip of branch master")
+}
+
+func TestMergeBase(t *testing.T) {
+	client, server := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/kubernetes/kubernetes/compare/release-1.15...master":
+			fmt.Fprint(w, `{"merge_base_commit": {"sha": "abc"}}`)
+		case "/repos/kubernetes/kubernetes/compare/orphan...master":
+			fmt.Fprint(w, `{}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	sha, err := MergeBase(client, "release-1.15", "master")
+	require.Nil(t, err)
+	require.Equal(t, "abc", sha)
+
+	_, err = MergeBase(client, "orphan", "master")
+	require.EqualError(t, err, "orphan and master have no common ancestor")
+
+	_, err = MergeBase(client, "missing", "master")
+	require.NotNil(t, err)
+}
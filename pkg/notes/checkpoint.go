@@ -0,0 +1,106 @@
+package notes
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/google/go-github/v27/github"
+	"github.com/pkg/errors"
+)
+
+// DefaultCheckpointInterval is the number of commits which are processed
+// between two flushes of the checkpoint file
+const DefaultCheckpointInterval = 100
+
+// Checkpoint is the progress of a ListReleaseNotes run, which allows resuming
+// an interrupted run instead of starting over.
+type Checkpoint struct {
+	// Start and End identify the range the checkpoint belongs to
+	Start string `json:"start"`
+	End   string `json:"end"`
+
+	// Commit is the SHA of the last commit which was fully processed, in the
+	// order of ListCommits
+	Commit string `json:"commit"`
+
+	// Notes are the notes gathered up to and including Commit
+	Notes ReleaseNoteList `json:"notes"`
+}
+
+// WithCheckpoint allows the caller to make ListReleaseNotes restartable. The
+// progress is flushed to the file at path every interval commits and a run
+// resumes after the last commit recorded in an existing file. The file is
+// removed once all commits are processed. An interval of zero or less selects
+// DefaultCheckpointInterval.
+func WithCheckpoint(path string, interval int) GithubApiOption {
+	return func(c *githubApiConfig) {
+		c.checkpointFile = path
+		if interval > 0 {
+			c.checkpointInterval = interval
+		}
+	}
+}
+
+// ReadCheckpoint reads the checkpoint at path. A nil checkpoint is returned if
+// the file does not exist.
+func ReadCheckpoint(path string) (*Checkpoint, error) {
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading checkpoint %s", path)
+	}
+
+	checkpoint := &Checkpoint{}
+	if err := json.Unmarshal(content, checkpoint); err != nil {
+		return nil, errors.Wrapf(err, "decoding checkpoint %s", path)
+	}
+	if checkpoint.Notes == nil {
+		checkpoint.Notes = ReleaseNoteList{}
+	}
+	return checkpoint, nil
+}
+
+// WriteCheckpoint writes the checkpoint to path. The file is replaced
+// atomically, so that an interruption never leaves a truncated checkpoint.
+func WriteCheckpoint(path string, checkpoint *Checkpoint) error {
+	content, err := json.Marshal(checkpoint)
+	if err != nil {
+		return errors.Wrap(err, "encoding checkpoint")
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return errors.Wrap(err, "creating temporary checkpoint file")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "writing temporary checkpoint file")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "closing temporary checkpoint file")
+	}
+	return errors.Wrapf(os.Rename(tmp.Name(), path), "replacing checkpoint %s", path)
+}
+
+// resumeIndex returns the index of the first commit which still has to be
+// processed according to the checkpoint.
+func resumeIndex(commits []*github.RepositoryCommit, checkpoint *Checkpoint, start, end string) (int, error) {
+	if checkpoint.Start != start || checkpoint.End != end {
+		return 0, errors.Errorf(
+			"checkpoint is for the range %s..%s instead of %s..%s",
+			checkpoint.Start, checkpoint.End, start, end,
+		)
+	}
+	for i, commit := range commits {
+		if commit.GetSHA() == checkpoint.Commit {
+			return i + 1, nil
+		}
+	}
+	return 0, errors.Errorf("checkpoint commit %s is not part of the range", checkpoint.Commit)
+}
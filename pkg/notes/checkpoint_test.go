@@ -0,0 +1,61 @@
+package notes
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-github/v27/github"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpointRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "checkpoint.json")
+
+	checkpoint, err := ReadCheckpoint(path)
+	require.Nil(t, err)
+	require.Nil(t, checkpoint)
+
+	written := &Checkpoint{
+		Start:  "abc",
+		End:    "def",
+		Commit: "bcd",
+		Notes:  ReleaseNoteList{1: &ReleaseNote{PrNumber: 1, Text: "a note"}},
+	}
+	require.Nil(t, WriteCheckpoint(path, written))
+
+	checkpoint, err = ReadCheckpoint(path)
+	require.Nil(t, err)
+	require.Equal(t, written, checkpoint)
+
+	// no temporary files are left behind
+	files, err := ioutil.ReadDir(dir)
+	require.Nil(t, err)
+	require.Len(t, files, 1)
+}
+
+func TestResumeIndex(t *testing.T) {
+	commits := []*github.RepositoryCommit{
+		{SHA: github.String("a")},
+		{SHA: github.String("b")},
+		{SHA: github.String("c")},
+	}
+
+	i, err := resumeIndex(commits, &Checkpoint{Start: "s", End: "e", Commit: "b"}, "s", "e")
+	require.Nil(t, err)
+	require.Equal(t, 2, i)
+
+	i, err = resumeIndex(commits, &Checkpoint{Start: "s", End: "e", Commit: "c"}, "s", "e")
+	require.Nil(t, err)
+	require.Equal(t, 3, i)
+
+	_, err = resumeIndex(commits, &Checkpoint{Start: "s", End: "e", Commit: "x"}, "s", "e")
+	require.NotNil(t, err)
+
+	_, err = resumeIndex(commits, &Checkpoint{Start: "s", End: "other", Commit: "b"}, "s", "e")
+	require.NotNil(t, err)
+}
@@ -0,0 +1,128 @@
+package notes
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/google/go-github/v27/github"
+	"github.com/pkg/errors"
+)
+
+// errNoCommitBodyNote is returned if a commit message neither contains a
+// release note nor indicates that the PR of the commit has one
+var errNoCommitBodyNote = errors.New("no release note found in commit message")
+
+// LabelCommandsFromString returns the arguments of all prow label commands for
+// the given label prefix, like "bug" for a "/kind bug" line if prefix is "kind".
+// Squash merged commits often contain these commands as part of the PR body.
+func LabelCommandsFromString(s, prefix string) []string {
+	exp := regexp.MustCompile(`(?m)^\s*/` + regexp.QuoteMeta(prefix) + `\s+(\S+)\s*$`)
+
+	labels := []string{}
+	for _, match := range exp.FindAllStringSubmatch(s, -1) {
+		if !HasString(labels, match[1]) {
+			labels = append(labels, match[1])
+		}
+	}
+	return labels
+}
+
+// commitMessageBody returns the commit message without its subject line and
+// without prow commands like "/kind bug", which leaves the description
+func commitMessageBody(message string) string {
+	lines := strings.Split(strings.ReplaceAll(message, "\r\n", "\n"), "\n")[1:]
+	body := []string{}
+	for _, line := range lines {
+		if !strings.HasPrefix(strings.TrimSpace(line), "/") {
+			body = append(body, line)
+		}
+	}
+	return strings.Join(body, "\n")
+}
+
+// ReleaseNoteFromCommitBody produces a release note from the ```release-note```
+// stanza within a commit message, which avoids the PR API calls for squash
+// merged commits. Labels are taken from prow commands like "/kind bug" in the
+// message. If the message mentions a release note without containing one, the
+// PR of the commit is fetched as done by ReleaseNoteFromCommit.
+func ReleaseNoteFromCommitBody(commit *github.RepositoryCommit, client *github.Client, logger log.Logger, relVer string, opts ...GithubApiOption) (*ReleaseNote, error) {
+	c := configFromOpts(opts...)
+	message := commit.GetCommit().GetMessage()
+
+	if strings.Contains(message, "/release-note-none") {
+		return nil, errNoCommitBodyNote
+	}
+
+	text, err := NoteTextFromString(message)
+	if err != nil {
+		if strings.Contains(message, "release-note") {
+			level.Debug(logger).Log(
+				"msg", "commit message mentions a release note without containing it, fetching the PR",
+				"sha", commit.GetSHA(),
+			)
+			return ReleaseNoteFromCommit(commit, client, logger, relVer, opts...)
+		}
+		return nil, errNoCommitBodyNote
+	}
+
+	number, err := getPRNumberFromCommit(client, logger, commit, opts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing release note from commit %s", commit.GetSHA())
+	}
+
+	sigs := LabelCommandsFromString(message, "sig")
+	if len(sigs) == 0 {
+		sigs = inferredSIGs(client, logger, number, opts...)
+	}
+	kinds, kindInferred := inferredKinds(LabelCommandsFromString(message, "kind"), strings.SplitN(message, "\n", 2)[0], opts...)
+	isFeature := HasString(kinds, "feature")
+	isActionRequired := strings.Contains(message, "/release-note-action-required") ||
+		stripActionRequired(message) != message
+	isSecurity := false
+	for _, label := range c.securityLabels {
+		parts := strings.SplitN(label, "/", 2)
+		if len(parts) == 2 && HasString(LabelCommandsFromString(message, parts[0]), parts[1]) {
+			isSecurity = true
+		}
+	}
+	isDuplicate := !isActionRequired && !isFeature && len(sigs) > 1
+
+	author := commit.GetAuthor().GetLogin()
+	description := ""
+	if c.includeDescription {
+		description = DescriptionFromString(commitMessageBody(message), c.descriptionMaxChars)
+	}
+
+	note := &ReleaseNote{
+		Commit:         commit.GetSHA(),
+		Text:           text,
+		Documentation:  DocumentationFromString(message),
+		Author:         author,
+		AuthorUrl:      fmt.Sprintf("https://github.com/%s", author),
+		PrUrl:          fmt.Sprintf("https://github.com/%s/%s/pull/%d", c.org, c.repo, number),
+		PrNumber:       number,
+		PrTitle:        strings.SplitN(message, "\n", 2)[0],
+		SIGs:           sigs,
+		Kinds:          kinds,
+		KindInferred:   kindInferred,
+		Areas:          LabelCommandsFromString(message, "area"),
+		Feature:        isFeature,
+		Duplicate:      isDuplicate,
+		ActionRequired: isActionRequired,
+		IsSecurity:     isSecurity,
+		Dependency:     IsDependencyBump(nil, strings.SplitN(message, "\n", 2)[0]),
+		Description:    description,
+		Branches:       trackedBranches(client, logger, commit.GetSHA(), opts...),
+		RelatedIssues:  IssueReferencesFromString(message, c.org, c.repo),
+		MergedAt:       commit.GetCommit().GetCommitter().GetDate(),
+		ReleaseVersion: relVer,
+	}
+	if c.includeSubtasks {
+		note.Subtasks = SubtasksFromString(commitMessageBody(message))
+	}
+	note.Markdown = NoteMarkdown(note, opts...)
+	return note, nil
+}
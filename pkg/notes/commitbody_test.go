@@ -0,0 +1,49 @@
+package notes
+
+import (
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-github/v27/github"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelCommandsFromString(t *testing.T) {
+	message := "Fix the kubelet (#123)\n\n/kind bug\n/kind cleanup\n/sig node\n> /kind feature\n/kind bug\n"
+
+	require.Equal(t, []string{"bug", "cleanup"}, LabelCommandsFromString(message, "kind"))
+	require.Equal(t, []string{"node"}, LabelCommandsFromString(message, "sig"))
+	require.Equal(t, []string{}, LabelCommandsFromString(message, "area"))
+}
+
+func TestReleaseNoteFromCommitBody(t *testing.T) {
+	commit := &github.RepositoryCommit{
+		SHA:    github.String("abc"),
+		Author: &github.User{Login: github.String("jdoe")},
+		Commit: &github.Commit{
+			Message: github.String("Fix the kubelet (#123)\n\n/kind bug\n/sig node\n\n```release-note\nFixed a crash of the kubelet\n```\n"),
+		},
+	}
+
+	note, err := ReleaseNoteFromCommitBody(commit, nil, log.NewNopLogger(), "v1.0.0")
+	require.NoError(t, err)
+	require.Equal(t, "Fixed a crash of the kubelet", note.Text)
+	require.Equal(t, 123, note.PrNumber)
+	require.Equal(t, []string{"bug"}, note.Kinds)
+	require.Equal(t, []string{"node"}, note.SIGs)
+	require.Equal(t, "https://github.com/kubernetes/kubernetes/pull/123", note.PrUrl)
+	require.False(t, note.ActionRequired)
+
+	commit.Commit.Message = github.String("Fix the kubelet (#123)\n\n/kind bug\n\nThe kubelet crashed\non startup.\n\n```release-note\nFixed a crash of the kubelet\n```\n")
+	note, err = ReleaseNoteFromCommitBody(commit, nil, log.NewNopLogger(), "v1.0.0", WithDescription(0))
+	require.NoError(t, err)
+	require.Equal(t, "The kubelet crashed on startup.", note.Description)
+
+	commit.Commit.Message = github.String("Fix the kubelet (#123)\n\n/release-note-none\n")
+	_, err = ReleaseNoteFromCommitBody(commit, nil, log.NewNopLogger(), "v1.0.0")
+	require.Equal(t, errNoCommitBodyNote, err)
+
+	commit.Commit.Message = github.String("Fix the kubelet (#123)")
+	_, err = ReleaseNoteFromCommitBody(commit, nil, log.NewNopLogger(), "v1.0.0")
+	require.Equal(t, errNoCommitBodyNote, err)
+}
@@ -0,0 +1,62 @@
+package notes
+
+import "strings"
+
+// WithCompact allows the caller to render the markdown without blank lines
+// between the notes of a section, as well as within the notes, for renderers
+// which display blank lines as large gaps. The sections are still separated
+// by a single blank line.
+func WithCompact() DocumentOption {
+	return func(c *documentConfig) {
+		c.compact = true
+	}
+}
+
+// compactMarkdown removes the blank lines between and within the list items
+// of the markdown, which makes every list a tight list, and collapses the
+// other runs of blank lines into a single one. Fenced code blocks are kept as
+// they are.
+func compactMarkdown(markdown string) string {
+	lines := strings.Split(markdown, "\n")
+	isListLine := func(line string) bool {
+		return strings.HasPrefix(line, "- ") || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+	}
+	isFence := func(line string) bool {
+		trimmed := strings.TrimSpace(line)
+		return strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")
+	}
+
+	compacted := []string{}
+	fenced := false
+	for i, line := range lines {
+		if isFence(line) {
+			fenced = !fenced
+		}
+		if fenced || strings.TrimSpace(line) != "" {
+			compacted = append(compacted, line)
+			continue
+		}
+
+		// a run of blank lines is kept as a single one, which is dropped if
+		// it is within a list
+		previous := ""
+		if len(compacted) > 0 {
+			previous = compacted[len(compacted)-1]
+		}
+		if strings.TrimSpace(previous) == "" && len(compacted) > 0 {
+			continue
+		}
+		next := ""
+		for _, following := range lines[i+1:] {
+			if strings.TrimSpace(following) != "" {
+				next = following
+				break
+			}
+		}
+		if isListLine(previous) && isListLine(next) {
+			continue
+		}
+		compacted = append(compacted, "")
+	}
+	return strings.Join(compacted, "\n")
+}
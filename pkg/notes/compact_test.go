@@ -0,0 +1,63 @@
+package notes
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompactFixtures(t *testing.T) {
+	notes := ReleaseNoteList{
+		1: {
+			Markdown: "Add the foo flag ([#1](https://github.com/kubernetes/kubernetes/pull/1), [@alice](https://github.com/alice))",
+			PrNumber: 1,
+			Feature:  true,
+		},
+		2: {
+			Markdown: "Add the bar flag ([#2](https://github.com/kubernetes/kubernetes/pull/2), [@bob](https://github.com/bob))\n\n  Courtesy of SIG Node, and SIG Apps",
+			PrNumber: 2,
+			Feature:  true,
+		},
+		3: {
+			Markdown: "Fix the kubelet config ([#3](https://github.com/kubernetes/kubernetes/pull/3), [@carol](https://github.com/carol))\n\n  ```yaml\n  a: 1\n\n  b: 2\n  ```",
+			PrNumber: 3,
+			SIGs:     []string{"node"},
+		},
+		4: {
+			Markdown: "Fix the kubelet restart loop ([#4](https://github.com/kubernetes/kubernetes/pull/4), [@dave](https://github.com/dave))",
+			PrNumber: 4,
+			SIGs:     []string{"node"},
+		},
+		5: {
+			Markdown: "Fix a crash ([#5](https://github.com/kubernetes/kubernetes/pull/5), [@erin](https://github.com/erin))",
+			PrNumber: 5,
+			Kinds:    []string{"bug"},
+		},
+	}
+
+	for fixture, opts := range map[string][]DocumentOption{
+		"compact-normal.md": nil,
+		"compact.md":        {WithCompact()},
+	} {
+		expected, err := ioutil.ReadFile(filepath.Join("testdata", fixture))
+		require.Nil(t, err)
+
+		content, err := RenderToBytes(notes, "markdown", opts...)
+		require.Nil(t, err)
+		require.Equal(t, string(expected), string(content), fixture)
+	}
+}
+
+func TestCompactMarkdown(t *testing.T) {
+	for markdown, expected := range map[string]string{
+		"## A\n\n- a\n\n\n- b\n\n\n\n## B\n\n- c\n": "## A\n\n- a\n- b\n\n## B\n\n- c\n",
+		"- a\n\n  more\n  - sub\n\n- b\n":           "- a\n  more\n  - sub\n- b\n",
+		"- a\n\n  ```\n  x\n\n  y\n  ```\n\n- b\n":  "- a\n  ```\n  x\n\n  y\n  ```\n- b\n",
+		"<details>\n\n- a\n\n</details>\n\n\n":      "<details>\n\n- a\n\n</details>\n",
+	} {
+		require.Equal(t, expected, compactMarkdown(markdown), strings.ReplaceAll(markdown, "\n", `\n`))
+	}
+}
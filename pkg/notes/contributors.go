@@ -0,0 +1,245 @@
+package notes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v27/github"
+	"github.com/pkg/errors"
+)
+
+// CoAuthor is a contributor listed in a `Co-authored-by:` commit trailer
+type CoAuthor struct {
+	// Name is the name as written in the trailer
+	Name string `json:"name"`
+
+	// Email is the email address as written in the trailer
+	Email string `json:"email"`
+
+	// Login is the GitHub username, if it can be derived from the email
+	Login string `json:"login,omitempty"`
+}
+
+// Contributor is a person who authored or co-authored at least one note
+type Contributor struct {
+	// Login is the GitHub username, if known
+	Login string `json:"login,omitempty"`
+
+	// Name is the name of a co-author whose GitHub username is unknown
+	Name string `json:"name,omitempty"`
+
+	// PrNumbers are the PRs the contributor worked on
+	PrNumbers []int `json:"pr_numbers"`
+
+	// FirstTime is set by FlagFirstTimeContributors if none of the
+	// contributor's earlier PRs were merged
+	FirstTime bool `json:"first_time,omitempty"`
+}
+
+// CoAuthorsFromCommitMessage parses all `Co-authored-by:` trailers of a commit
+// message. Co-authors are deduplicated by their GitHub login or, if the login
+// is unknown, by their email address.
+func CoAuthorsFromCommitMessage(message string) []CoAuthor {
+	exp := regexp.MustCompile(`(?mi)^co-authored-by:\s*(?P<name>.*?)\s*<(?P<email>[^>]+)>\s*$`)
+
+	coAuthors := []CoAuthor{}
+	seen := map[string]struct{}{}
+	for _, match := range exp.FindAllStringSubmatch(message, -1) {
+		coAuthor := CoAuthor{
+			Name:  match[1],
+			Email: match[2],
+			Login: loginFromEmail(match[2]),
+		}
+
+		key := strings.ToLower(coAuthor.Email)
+		if coAuthor.Login != "" {
+			key = strings.ToLower(coAuthor.Login)
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		coAuthors = append(coAuthors, coAuthor)
+	}
+	return coAuthors
+}
+
+// loginFromEmail derives the GitHub username from a GitHub noreply address,
+// like 1234+login@users.noreply.github.com. Other addresses cannot be mapped
+// without an API call, so an empty string is returned for them.
+func loginFromEmail(email string) string {
+	exp := regexp.MustCompile(`(?i)^(\d+\+)?(?P<login>[a-z0-9-]+)@users\.noreply\.github\.com$`)
+	match := exp.FindStringSubmatch(strings.TrimSpace(email))
+	if len(match) == 0 {
+		return ""
+	}
+	return match[2]
+}
+
+// Contributors returns the authors and co-authors of the notes, sorted by
+// login or name. Contributors with a login are matched case insensitively.
+func Contributors(notes ReleaseNoteList) []*Contributor {
+	contributors := map[string]*Contributor{}
+	add := func(key string, contributor *Contributor, number int) {
+		key = strings.ToLower(key)
+		existing, ok := contributors[key]
+		if !ok {
+			existing = contributor
+			contributors[key] = existing
+		}
+		for _, n := range existing.PrNumbers {
+			if n == number {
+				return
+			}
+		}
+		existing.PrNumbers = append(existing.PrNumbers, number)
+	}
+
+	for _, note := range sortedNotes(notes) {
+		if note.Author != "" {
+			add(note.Author, &Contributor{Login: note.Author}, note.PrNumber)
+		}
+		for _, coAuthor := range note.CoAuthors {
+			if coAuthor.Login != "" {
+				add(coAuthor.Login, &Contributor{Login: coAuthor.Login}, note.PrNumber)
+			} else {
+				add(coAuthor.Email, &Contributor{Name: coAuthor.Name}, note.PrNumber)
+			}
+		}
+	}
+
+	result := make([]*Contributor, 0, len(contributors))
+	for _, contributor := range contributors {
+		result = append(result, contributor)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return strings.ToLower(result[i].displayName()) < strings.ToLower(result[j].displayName())
+	})
+	return result
+}
+
+// displayName returns the login of the contributor, or the name if the login
+// is unknown
+func (c *Contributor) displayName() string {
+	if c.Login != "" {
+		return c.Login
+	}
+	return c.Name
+}
+
+// RenderContributorsMarkdown writes a "Contributors" section listing the
+// provided contributors to the supplied io.Writer in markdown format.
+func RenderContributorsMarkdown(contributors []*Contributor, w io.Writer) error {
+	if len(contributors) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	b.WriteString("## Contributors\n\n")
+	for _, contributor := range contributors {
+		if contributor.Login != "" {
+			fmt.Fprintf(&b, "- [@%s](https://github.com/%s)\n", contributor.Login, contributor.Login)
+		} else {
+			fmt.Fprintf(&b, "- %s\n", contributor.Name)
+		}
+	}
+	b.WriteString("\n\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// FlagFirstTimeContributors sets FirstTime for every contributor with a login
+// who had no PR merged into the repository before the PRs of the notes. The
+// merged PRs of every contributor are looked up with the search API, which has
+// a low rate limit, so this should only be used for a single report.
+func FlagFirstTimeContributors(client *github.Client, contributors []*Contributor, opts ...GithubApiOption) error {
+	c := configFromOpts(opts...)
+
+	for _, contributor := range contributors {
+		if contributor.Login == "" || len(contributor.PrNumbers) == 0 {
+			continue
+		}
+
+		query := fmt.Sprintf("repo:%s/%s is:pr is:merged author:%s", c.org, c.repo, contributor.Login)
+		result, _, err := client.Search.Issues(c.ctx, query, &github.SearchOptions{
+			Sort:        "created",
+			Order:       "asc",
+			ListOptions: github.ListOptions{PerPage: 100},
+		})
+		if err != nil {
+			return errors.Wrapf(err, "searching the merged PRs of %s", contributor.Login)
+		}
+		contributor.FirstTime = !mergedBefore(result.Issues, contributor.PrNumbers)
+	}
+	return nil
+}
+
+// mergedBefore returns whether any of the merged PRs is not one of the PRs of
+// the release and older than the newest of them
+func mergedBefore(merged []github.Issue, numbers []int) bool {
+	newest := 0
+	inRelease := map[int]struct{}{}
+	for _, number := range numbers {
+		inRelease[number] = struct{}{}
+		if number > newest {
+			newest = number
+		}
+	}
+
+	for _, pr := range merged {
+		if _, ok := inRelease[pr.GetNumber()]; !ok && pr.GetNumber() < newest {
+			return true
+		}
+	}
+	return false
+}
+
+// RenderContributorsReport writes the contributors with the number of their
+// PRs to the supplied io.Writer, either as a markdown list or as JSON.
+func RenderContributorsReport(contributors []*Contributor, format string, w io.Writer) error {
+	switch format {
+	case "json":
+		type contributorReport struct {
+			*Contributor
+			PrCount int `json:"pr_count"`
+		}
+		report := make([]contributorReport, 0, len(contributors))
+		for _, contributor := range contributors {
+			report = append(report, contributorReport{Contributor: contributor, PrCount: len(contributor.PrNumbers)})
+		}
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return errors.Wrap(enc.Encode(report), "error encoding contributors to JSON")
+	case "markdown":
+		var b strings.Builder
+		b.WriteString("## Contributors\n\n")
+		for _, contributor := range contributors {
+			if contributor.Login != "" {
+				fmt.Fprintf(&b, "- [@%s](https://github.com/%s)", contributor.Login, contributor.Login)
+			} else {
+				fmt.Fprintf(&b, "- %s", contributor.Name)
+			}
+
+			prs := "PRs"
+			if len(contributor.PrNumbers) == 1 {
+				prs = "PR"
+			}
+			fmt.Fprintf(&b, " (%d %s)", len(contributor.PrNumbers), prs)
+			if contributor.FirstTime {
+				b.WriteString(" - first-time contributor")
+			}
+			b.WriteString("\n")
+		}
+
+		_, err := io.WriteString(w, b.String())
+		return err
+	default:
+		return errors.Errorf("%q is an unsupported contributors format", format)
+	}
+}
@@ -0,0 +1,125 @@
+package notes
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoAuthorsFromCommitMessage(t *testing.T) {
+	testCases := []struct {
+		name     string
+		message  string
+		expected []CoAuthor
+	}{
+		{
+			name:     "no co-authors",
+			message:  "Fix the build (#123)",
+			expected: []CoAuthor{},
+		},
+		{
+			name:    "single co-author",
+			message: "Fix the build (#123)\n\nCo-authored-by: Jane Doe <12345+jdoe@users.noreply.github.com>",
+			expected: []CoAuthor{
+				{Name: "Jane Doe", Email: "12345+jdoe@users.noreply.github.com", Login: "jdoe"},
+			},
+		},
+		{
+			name: "multiple co-authors with duplicates and unknown logins",
+			message: "Fix the build (#123)\r\n\r\n" +
+				"Co-authored-by: Jane Doe <jdoe@users.noreply.github.com>\r\n" +
+				"co-authored-by: John Smith <john@example.com>\r\n" +
+				"Co-Authored-By: Jane <12345+JDoe@users.noreply.github.com>\r\n" +
+				"Co-authored-by: J. Smith <John@Example.com>\r\n",
+			expected: []CoAuthor{
+				{Name: "Jane Doe", Email: "jdoe@users.noreply.github.com", Login: "jdoe"},
+				{Name: "John Smith", Email: "john@example.com"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, CoAuthorsFromCommitMessage(tc.message))
+		})
+	}
+}
+
+func TestContributors(t *testing.T) {
+	notes := ReleaseNoteList{
+		1: {PrNumber: 1, Author: "alice"},
+		2: {PrNumber: 2, Author: "bob", CoAuthors: []CoAuthor{
+			{Name: "Alice", Email: "alice@users.noreply.github.com", Login: "Alice"},
+			{Name: "Carol", Email: "carol@example.com"},
+		}},
+	}
+
+	contributors := Contributors(notes)
+	require.Equal(t, []*Contributor{
+		{Login: "alice", PrNumbers: []int{1, 2}},
+		{Login: "bob", PrNumbers: []int{2}},
+		{Name: "Carol", PrNumbers: []int{2}},
+	}, contributors)
+
+	out := &bytes.Buffer{}
+	require.NoError(t, RenderContributorsMarkdown(contributors, out))
+	require.Equal(t, "## Contributors\n\n"+
+		"- [@alice](https://github.com/alice)\n"+
+		"- [@bob](https://github.com/bob)\n"+
+		"- Carol\n\n\n", out.String())
+}
+
+func TestFlagFirstTimeContributors(t *testing.T) {
+	client, server := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/search/issues", r.URL.Path)
+		switch r.URL.Query().Get("q") {
+		case "repo:kubernetes/kubernetes is:pr is:merged author:alice":
+			// alice's only earlier PR was merged before the release
+			fmt.Fprint(w, `{"total_count": 2, "items": [{"number": 1}, {"number": 5}]}`)
+		case "repo:kubernetes/kubernetes is:pr is:merged author:bob":
+			// bob's other PR was merged after the release
+			fmt.Fprint(w, `{"total_count": 2, "items": [{"number": 5}, {"number": 9}]}`)
+		default:
+			t.Errorf("unexpected query %q", r.URL.Query().Get("q"))
+		}
+	}))
+	defer server.Close()
+
+	contributors := []*Contributor{
+		{Login: "alice", PrNumbers: []int{5}},
+		{Login: "bob", PrNumbers: []int{5}},
+		{Name: "Carol", PrNumbers: []int{5}},
+	}
+	require.Nil(t, FlagFirstTimeContributors(client, contributors))
+	require.False(t, contributors[0].FirstTime)
+	require.True(t, contributors[1].FirstTime)
+	require.False(t, contributors[2].FirstTime)
+}
+
+func TestRenderContributorsReport(t *testing.T) {
+	contributors := []*Contributor{
+		{Login: "alice", PrNumbers: []int{1, 2}},
+		{Login: "bob", PrNumbers: []int{2}, FirstTime: true},
+		{Name: "Carol", PrNumbers: []int{2}},
+	}
+
+	out := &bytes.Buffer{}
+	require.Nil(t, RenderContributorsReport(contributors, "markdown", out))
+	require.Equal(t, "## Contributors\n\n"+
+		"- [@alice](https://github.com/alice) (2 PRs)\n"+
+		"- [@bob](https://github.com/bob) (1 PR) - first-time contributor\n"+
+		"- Carol (1 PR)\n", out.String())
+
+	out.Reset()
+	require.Nil(t, RenderContributorsReport(contributors, "json", out))
+	require.JSONEq(t, `[
+		{"login": "alice", "pr_numbers": [1, 2], "pr_count": 2},
+		{"login": "bob", "pr_numbers": [2], "first_time": true, "pr_count": 1},
+		{"name": "Carol", "pr_numbers": [2], "pr_count": 1}
+	]`, out.String())
+
+	require.NotNil(t, RenderContributorsReport(contributors, "yaml", out))
+}
@@ -0,0 +1,144 @@
+package notes
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-github/v27/github"
+	"github.com/pkg/errors"
+)
+
+// NoteSource describes where the text of a release note is taken from.
+type NoteSource string
+
+const (
+	// NoteSourceReleaseNote extracts notes from the ```release-note``` stanza in
+	// the PR description. This is the default.
+	NoteSourceReleaseNote NoteSource = "release-note"
+
+	// NoteSourceConventional derives notes from conventional commit messages,
+	// see https://www.conventionalcommits.org
+	NoteSourceConventional NoteSource = "conventional"
+
+	// NoteSourceCommitBody extracts notes from the ```release-note``` stanza in
+	// the commit message, which avoids fetching the PRs for squash merged repos.
+	NoteSourceCommitBody NoteSource = "commit-body"
+)
+
+// ConventionalCommit is the parsed representation of a commit message which
+// follows the conventional commits specification.
+type ConventionalCommit struct {
+	// Type is the commit type, like "feat" or "fix"
+	Type string
+
+	// Scope is the optional scope in parenthesis after the type
+	Scope string
+
+	// Description is the short summary following the colon
+	Description string
+
+	// Breaking indicates that the commit was marked with a "!" or contains a
+	// BREAKING CHANGE footer
+	Breaking bool
+}
+
+// WithNoteSource allows the caller to select where release notes are extracted
+// from. By default, it is NoteSourceReleaseNote.
+func WithNoteSource(source NoteSource) GithubApiOption {
+	return func(c *githubApiConfig) {
+		c.noteSource = source
+	}
+}
+
+// ParseConventionalCommit parses the given commit message as a conventional
+// commit. An error is returned if the subject line does not follow the
+// specification.
+func ParseConventionalCommit(message string) (*ConventionalCommit, error) {
+	message = strings.ReplaceAll(message, "\r", "")
+	lines := strings.SplitN(message, "\n", 2)
+
+	exp := regexp.MustCompile(`^(?P<type>[a-zA-Z]+)(\((?P<scope>[^)]*)\))?(?P<breaking>!)?: (?P<description>.+)$`)
+	match := exp.FindStringSubmatch(strings.TrimSpace(lines[0]))
+	if len(match) == 0 {
+		return nil, errors.New("no matches found when parsing conventional commit")
+	}
+	result := map[string]string{}
+	for i, name := range exp.SubexpNames() {
+		if i != 0 && name != "" {
+			result[name] = match[i]
+		}
+	}
+
+	// Squash merges append the PR number to the subject, which we already link
+	description := regexp.MustCompile(`\s*\(#\d+\)$`).ReplaceAllString(result["description"], "")
+
+	breaking := result["breaking"] == "!"
+	if len(lines) > 1 {
+		footer := regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: `)
+		breaking = breaking || footer.MatchString(lines[1])
+	}
+
+	return &ConventionalCommit{
+		Type:        strings.ToLower(result["type"]),
+		Scope:       result["scope"],
+		Description: strings.TrimSpace(description),
+		Breaking:    breaking,
+	}, nil
+}
+
+// ReleaseNoteFromConventionalCommit produces a release note from a commit
+// message following the conventional commits specification. Only features,
+// fixes and breaking changes are turned into notes, every other commit type
+// results in an error.
+func ReleaseNoteFromConventionalCommit(commit *github.RepositoryCommit, client *github.Client, logger log.Logger, relVer string, opts ...GithubApiOption) (*ReleaseNote, error) {
+	c := configFromOpts(opts...)
+
+	cc, err := ParseConventionalCommit(commit.GetCommit().GetMessage())
+	if err != nil {
+		return nil, err
+	}
+
+	kinds := []string{}
+	switch cc.Type {
+	case "feat":
+		kinds = append(kinds, "feature")
+	case "fix":
+		kinds = append(kinds, "bug")
+	default:
+		if !cc.Breaking {
+			return nil, errors.Errorf("conventional commit type %q is not release note worthy", cc.Type)
+		}
+	}
+
+	number, err := getPRNumberFromCommit(client, logger, commit, opts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing release note from commit %s", commit.GetSHA())
+	}
+
+	text := strings.ReplaceAll(cc.Description, "#", "&#35;")
+	author := commit.GetAuthor().GetLogin()
+	authorUrl := fmt.Sprintf("https://github.com/%s", author)
+	prUrl := fmt.Sprintf("https://github.com/%s/%s/pull/%d", c.org, c.repo, number)
+	branches := trackedBranches(client, logger, commit.GetSHA(), opts...)
+	markdown := noteMarkdown(text, number, prUrl, author, authorUrl, branches)
+
+	return &ReleaseNote{
+		Commit:         commit.GetSHA(),
+		Text:           text,
+		Markdown:       markdown,
+		Author:         author,
+		AuthorUrl:      authorUrl,
+		PrUrl:          prUrl,
+		PrNumber:       number,
+		PrTitle:        strings.SplitN(commit.GetCommit().GetMessage(), "\n", 2)[0],
+		Kinds:          kinds,
+		Feature:        cc.Type == "feat",
+		ActionRequired: cc.Breaking,
+		Dependency:     IsDependencyBump(nil, strings.SplitN(commit.GetCommit().GetMessage(), "\n", 2)[0]),
+		Branches:       branches,
+		MergedAt:       commit.GetCommit().GetCommitter().GetDate(),
+		ReleaseVersion: relVer,
+	}, nil
+}
@@ -0,0 +1,47 @@
+package notes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConventionalCommit(t *testing.T) {
+	testCases := []struct {
+		name     string
+		message  string
+		expected *ConventionalCommit
+	}{
+		{
+			name:     "feature",
+			message:  "feat: add a new flag",
+			expected: &ConventionalCommit{Type: "feat", Description: "add a new flag"},
+		},
+		{
+			name:     "fix with scope and PR suffix",
+			message:  "fix(kubelet): do not crash on startup (#1234)",
+			expected: &ConventionalCommit{Type: "fix", Scope: "kubelet", Description: "do not crash on startup"},
+		},
+		{
+			name:     "breaking via exclamation mark",
+			message:  "feat(api)!: remove the v1beta1 API",
+			expected: &ConventionalCommit{Type: "feat", Scope: "api", Description: "remove the v1beta1 API", Breaking: true},
+		},
+		{
+			name:     "breaking via footer",
+			message:  "refactor: rename the config file\n\nBREAKING CHANGE: the config file is now named foo.yaml",
+			expected: &ConventionalCommit{Type: "refactor", Description: "rename the config file", Breaking: true},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := ParseConventionalCommit(tc.message)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+
+	_, err := ParseConventionalCommit("Merge pull request #76030 from andrewsykim/e2e-legacyscheme")
+	require.Error(t, err)
+}
@@ -0,0 +1,68 @@
+package notes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// WithDedupeIdenticalText allows the caller to collapse notes with identical
+// text into one note listing all of their PRs, instead of keeping only the
+// first of them.
+func WithDedupeIdenticalText() GithubApiOption {
+	return func(c *githubApiConfig) {
+		c.dedupeIdenticalText = true
+	}
+}
+
+// DedupeIdenticalText collapses the notes whose texts are identical after
+// normalizing the whitespace. The note of the lowest PR number is kept, its
+// PrNumbers lists all collapsed PRs and its markdown links the other PRs.
+func DedupeIdenticalText(notes ReleaseNoteList) ReleaseNoteList {
+	numbers := []int{}
+	for number := range notes {
+		numbers = append(numbers, number)
+	}
+	sort.Ints(numbers)
+
+	result := ReleaseNoteList{}
+	canonical := map[string]*ReleaseNote{}
+	for _, number := range numbers {
+		note := notes[number]
+		key := strings.TrimSpace(NormalizeWhitespace(note.Text))
+
+		first, ok := canonical[key]
+		if !ok {
+			canonical[key] = note
+			result[number] = note
+			continue
+		}
+		if len(first.PrNumbers) == 0 {
+			first.PrNumbers = []int{first.PrNumber}
+		}
+		first.PrNumbers = append(first.PrNumbers, note.PrNumber)
+	}
+
+	for _, note := range canonical {
+		if len(note.PrNumbers) == 0 {
+			continue
+		}
+		note.Markdown = alsoInMarkdown(note.Markdown, note.PrNumbers[1:], func(number int) string {
+			return notes[number].PrUrl
+		})
+	}
+	return result
+}
+
+// alsoInMarkdown appends the links to the other PRs of a collapsed note to
+// its markdown.
+func alsoInMarkdown(markdown string, numbers []int, prURL func(int) string) string {
+	if len(numbers) == 0 {
+		return markdown
+	}
+	links := []string{}
+	for _, number := range numbers {
+		links = append(links, fmt.Sprintf("[#%d](%s)", number, prURL(number)))
+	}
+	return fmt.Sprintf("%s\n\n  Also in %s", markdown, strings.Join(links, ", "))
+}
@@ -0,0 +1,65 @@
+package notes
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDedupeIdenticalText(t *testing.T) {
+	note := func(number int, text string) *ReleaseNote {
+		return &ReleaseNote{
+			PrNumber: number,
+			PrUrl:    fmt.Sprintf("https://github.com/kubernetes/kubernetes/pull/%d", number),
+			Text:     text,
+			Markdown: text,
+		}
+	}
+
+	notes := ReleaseNoteList{
+		3: note(3, "Update golang.org/x/net to v0.1.0"),
+		1: note(1, "Update golang.org/x/net  to v0.1.0 "),
+		2: note(2, "Fix a bug"),
+		5: note(5, "Update golang.org/x/net to v0.1.0"),
+	}
+
+	deduped := DedupeIdenticalText(notes)
+	require.Len(t, deduped, 2)
+
+	canonical := deduped[1]
+	require.Equal(t, []int{1, 3, 5}, canonical.PrNumbers)
+	require.Equal(t,
+		"Update golang.org/x/net  to v0.1.0 \n\n  Also in [#3](https://github.com/kubernetes/kubernetes/pull/3), [#5](https://github.com/kubernetes/kubernetes/pull/5)",
+		canonical.Markdown,
+	)
+
+	require.Nil(t, deduped[2].PrNumbers)
+	require.Equal(t, "Fix a bug", deduped[2].Markdown)
+}
+
+func TestRegenerateMarkdown(t *testing.T) {
+	note := &ReleaseNote{
+		PrNumber:  1,
+		PrNumbers: []int{1, 3},
+		PrUrl:     "https://github.com/kubernetes/kubernetes/pull/1",
+		PrTitle:   "Update golang.org/x/net",
+		Author:    "foo",
+		AuthorUrl: "https://github.com/foo",
+		Text:      "Edited  text",
+	}
+
+	markdown := RegenerateMarkdown(note, WithNormalizeWhitespace(true))
+	require.Equal(t,
+		"Edited text ([#1](https://github.com/kubernetes/kubernetes/pull/1), [@foo](https://github.com/foo))\n\n  Also in [#3](https://github.com/kubernetes/kubernetes/pull/3)",
+		markdown,
+	)
+
+	note.Text = "See PR title"
+	markdown = RegenerateMarkdown(note, WithResolveSeeTitle(DefaultSeeTitlePhrases))
+	require.True(t, note.TitleSubstituted)
+	require.Equal(t,
+		"Update golang.org/x/net ([#1](https://github.com/kubernetes/kubernetes/pull/1), [@foo](https://github.com/foo))\n\n  Also in [#3](https://github.com/kubernetes/kubernetes/pull/3)",
+		markdown,
+	)
+}
@@ -0,0 +1,68 @@
+package notes
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-github/v27/github"
+)
+
+// DependencyLabel marks the PRs which update dependencies, like the ones of
+// Dependabot
+const DependencyLabel = "dependencies"
+
+// dependencyBumpTitle matches the titles of dependency updates, like "Bump
+// golang.org/x/net from 0.1.0 to 0.2.0", optionally with a conventional commit
+// type
+var dependencyBumpTitle = regexp.MustCompile(`(?i)^(\w+(\([^)]*\))?!?:\s*)?bump \S+ from \S+ to \S+`)
+
+// IsDependencyBump returns whether a PR with the provided labels and title
+// updates a dependency
+func IsDependencyBump(labels []string, title string) bool {
+	return HasString(labels, DependencyLabel) || dependencyBumpTitle.MatchString(strings.TrimSpace(title))
+}
+
+// labelNames returns the names of all labels of the PR
+func labelNames(pr *github.PullRequest) []string {
+	names := []string{}
+	for _, label := range pr.Labels {
+		names = append(names, label.GetName())
+	}
+	return names
+}
+
+// WithCollapseDependencies allows the caller to list the notes of dependency
+// updates in a single "Dependency Updates" section at the end of the document
+// instead of their regular sections. In markdown, the section is collapsed
+// behind a summary like "3 dependencies updated". Security and action required
+// updates keep their sections.
+func WithCollapseDependencies() DocumentOption {
+	return func(c *documentConfig) {
+		c.collapseDeps = true
+	}
+}
+
+// collapsedDependency returns whether the note is listed in the dependency
+// updates section
+func collapsedDependency(note *ReleaseNote, c *documentConfig) bool {
+	return c.collapseDeps && note.Dependency && !note.IsSecurity && !note.ActionRequired
+}
+
+// dependenciesSummary returns the summary of the collapsed dependency updates
+func dependenciesSummary(count int) string {
+	if count == 1 {
+		return "1 dependency updated"
+	}
+	return fmt.Sprintf("%d dependencies updated", count)
+}
+
+// dependenciesOpening returns the heading of the dependency updates section
+// and the opening of the collapsed list of its notes
+func dependenciesOpening(count int, c *documentConfig) string {
+	heading := headingMarkdown("##", sectionTitle(sectionDependencies, c), sectionKeys[sectionDependencies], c)
+	return fmt.Sprintf("%s<details>\n<summary>%s</summary>\n\n", heading, dependenciesSummary(count))
+}
+
+// dependenciesClosing closes the collapsed list of the dependency updates
+const dependenciesClosing = "\n</details>\n\n\n"
@@ -0,0 +1,64 @@
+package notes
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsDependencyBump(t *testing.T) {
+	for _, tc := range []struct {
+		labels   []string
+		title    string
+		expected bool
+	}{
+		{nil, "Bump golang.org/x/net from 0.1.0 to 0.2.0", true},
+		{nil, "build(deps): bump github.com/pkg/errors from 0.8.1 to 0.9.1", true},
+		{nil, "chore!: Bump foo from v1 to v2", true},
+		{[]string{"dependencies"}, "Update the vendored modules", true},
+		{[]string{"kind/bug"}, "Fix the bump of the version", false},
+		{nil, "Bump the version to 1.2.0", false},
+	} {
+		require.Equal(t, tc.expected, IsDependencyBump(tc.labels, tc.title), tc.title)
+	}
+}
+
+func TestRenderCollapsedDependencies(t *testing.T) {
+	notes := ReleaseNoteList{
+		1: {PrNumber: 1, Markdown: "- Fix the thing ([#1](url))"},
+		2: {PrNumber: 2, Markdown: "- Bump foo from 1.0 to 1.1 ([#2](url))", Dependency: true},
+		3: {PrNumber: 3, Markdown: "- Bump bar from 2.0 to 3.0 ([#3](url))", Dependency: true, ActionRequired: true},
+		4: {PrNumber: 4, Markdown: "- Bump baz from 0.1 to 0.2 ([#4](url))", Dependency: true},
+	}
+	opts := []DocumentOption{WithCollapseDependencies()}
+
+	doc, err := CreateDocument(notes, opts...)
+	require.Nil(t, err)
+	require.Len(t, doc.Dependencies, 2)
+
+	expected := "## Action Required\n\n" +
+		"- Bump bar from 2.0 to 3.0 ([#3](url))\n\n\n" +
+		"## Other Notable Changes\n\n" +
+		"- Fix the thing ([#1](url))\n\n\n" +
+		"## Dependency Updates\n\n" +
+		"<details>\n<summary>2 dependencies updated</summary>\n\n" +
+		"- Bump foo from 1.0 to 1.1 ([#2](url))\n" +
+		"- Bump baz from 0.1 to 0.2 ([#4](url))\n" +
+		"\n</details>\n\n\n"
+
+	out := &bytes.Buffer{}
+	require.Nil(t, RenderMarkdown(doc, out, opts...))
+	require.Equal(t, expected, out.String())
+
+	// streaming renders the same markdown
+	out.Reset()
+	require.Nil(t, RenderMarkdownStream(notes, out, opts...))
+	require.Equal(t, expected, out.String())
+
+	// the notes keep their sections unless collapsed
+	doc, err = CreateDocument(notes)
+	require.Nil(t, err)
+	require.Len(t, doc.Dependencies, 0)
+	require.Len(t, doc.Uncategorized, 3)
+}
@@ -0,0 +1,164 @@
+package notes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v27/github"
+	"github.com/pkg/errors"
+)
+
+// DefaultGraphQLURL is the GraphQL API endpoint of github.com
+const DefaultGraphQLURL = "https://api.github.com/graphql"
+
+// discussionsQuery looks up the repository, its discussion categories and its
+// most recent discussions
+const discussionsQuery = `query($owner: String!, $name: String!) {
+  repository(owner: $owner, name: $name) {
+    id
+    discussionCategories(first: 100) {
+      nodes { id name }
+    }
+    discussions(first: 100, orderBy: {field: CREATED_AT, direction: DESC}) {
+      nodes { id title url }
+    }
+  }
+}`
+
+// createDiscussionMutation creates a discussion in a category
+const createDiscussionMutation = `mutation($repositoryId: ID!, $categoryId: ID!, $title: String!, $body: String!) {
+  createDiscussion(input: {repositoryId: $repositoryId, categoryId: $categoryId, title: $title, body: $body}) {
+    discussion { url }
+  }
+}`
+
+// updateDiscussionMutation replaces the body of a discussion
+const updateDiscussionMutation = `mutation($discussionId: ID!, $body: String!) {
+  updateDiscussion(input: {discussionId: $discussionId, body: $body}) {
+    discussion { url }
+  }
+}`
+
+// graphQLRequest is the payload of a GraphQL request
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// graphQLResponse is the payload of a GraphQL response
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// discussionPayload is the payload of the discussion mutations
+type discussionPayload struct {
+	Discussion struct {
+		URL string `json:"url"`
+	} `json:"discussion"`
+}
+
+// graphQL sends the query with its variables to the GraphQL endpoint and
+// decodes the data of the response into data. The request is sent with the
+// authentication of the client.
+func graphQL(ctx context.Context, client *github.Client, endpoint, query string, variables map[string]interface{}, data interface{}) error {
+	req, err := client.NewRequest(http.MethodPost, endpoint, &graphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		return err
+	}
+
+	resp := &graphQLResponse{}
+	if _, err := client.Do(ctx, req, resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		messages := []string{}
+		for _, e := range resp.Errors {
+			messages = append(messages, e.Message)
+		}
+		return errors.Errorf("GraphQL request failed: %s", strings.Join(messages, "; "))
+	}
+	return json.Unmarshal(resp.Data, data)
+}
+
+// PublishDiscussion creates a GitHub Discussion with the title and body in the
+// category of the repository, or updates the body of the discussion with the
+// same title if one exists among the 100 most recent ones. It returns the URL
+// of the discussion. The GraphQL API is requested at endpoint, which is
+// DefaultGraphQLURL unless GitHub Enterprise is used.
+func PublishDiscussion(client *github.Client, endpoint, category, title, body string, opts ...GithubApiOption) (string, error) {
+	c := configFromOpts(opts...)
+
+	repository := struct {
+		Repository *struct {
+			ID                   string `json:"id"`
+			DiscussionCategories struct {
+				Nodes []struct {
+					ID   string `json:"id"`
+					Name string `json:"name"`
+				} `json:"nodes"`
+			} `json:"discussionCategories"`
+			Discussions struct {
+				Nodes []struct {
+					ID    string `json:"id"`
+					Title string `json:"title"`
+					URL   string `json:"url"`
+				} `json:"nodes"`
+			} `json:"discussions"`
+		} `json:"repository"`
+	}{}
+	variables := map[string]interface{}{"owner": c.org, "name": c.repo}
+	if err := graphQL(c.ctx, client, endpoint, discussionsQuery, variables, &repository); err != nil {
+		return "", errors.Wrapf(err, "error getting the discussions of %s/%s", c.org, c.repo)
+	}
+	if repository.Repository == nil {
+		return "", errors.Errorf("repository %s/%s not found", c.org, c.repo)
+	}
+
+	for _, existing := range repository.Repository.Discussions.Nodes {
+		if existing.Title != title {
+			continue
+		}
+		result := struct {
+			UpdateDiscussion discussionPayload `json:"updateDiscussion"`
+		}{}
+		variables := map[string]interface{}{"discussionId": existing.ID, "body": body}
+		if err := graphQL(c.ctx, client, endpoint, updateDiscussionMutation, variables, &result); err != nil {
+			return "", errors.Wrapf(err, "error updating the discussion %q", title)
+		}
+		return result.UpdateDiscussion.Discussion.URL, nil
+	}
+
+	categoryID := ""
+	names := []string{}
+	for _, node := range repository.Repository.DiscussionCategories.Nodes {
+		if node.Name == category {
+			categoryID = node.ID
+		}
+		names = append(names, node.Name)
+	}
+	if categoryID == "" {
+		return "", errors.Errorf(
+			"discussion category %q not found in %s/%s (categories: %s)",
+			category, c.org, c.repo, strings.Join(names, ", "),
+		)
+	}
+
+	result := struct {
+		CreateDiscussion discussionPayload `json:"createDiscussion"`
+	}{}
+	variables = map[string]interface{}{
+		"repositoryId": repository.Repository.ID,
+		"categoryId":   categoryID,
+		"title":        title,
+		"body":         body,
+	}
+	if err := graphQL(c.ctx, client, endpoint, createDiscussionMutation, variables, &result); err != nil {
+		return "", errors.Wrapf(err, "error creating the discussion %q", title)
+	}
+	return result.CreateDiscussion.Discussion.URL, nil
+}
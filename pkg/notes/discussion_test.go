@@ -0,0 +1,63 @@
+package notes
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishDiscussion(t *testing.T) {
+	mutations := []map[string]interface{}{}
+	client, server := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/graphql", r.URL.Path)
+		req := graphQLRequest{}
+		require.Nil(t, json.NewDecoder(r.Body).Decode(&req))
+
+		switch {
+		case strings.HasPrefix(req.Query, "query"):
+			require.Equal(t, "kubernetes", req.Variables["owner"])
+			fmt.Fprint(w, `{"data": {"repository": {
+				"id": "R_1",
+				"discussionCategories": {"nodes": [{"id": "C_1", "name": "General"}, {"id": "C_2", "name": "Announcements"}]},
+				"discussions": {"nodes": [{"id": "D_1", "title": "v1.16.0", "url": "https://github.com/kubernetes/community/discussions/1"}]}
+			}}}`)
+		case strings.Contains(req.Query, "createDiscussion"):
+			mutations = append(mutations, req.Variables)
+			fmt.Fprint(w, `{"data": {"createDiscussion": {"discussion": {"url": "https://github.com/kubernetes/community/discussions/2"}}}}`)
+		case strings.Contains(req.Query, "updateDiscussion"):
+			mutations = append(mutations, req.Variables)
+			fmt.Fprint(w, `{"data": {"updateDiscussion": {"discussion": {"url": "https://github.com/kubernetes/community/discussions/1"}}}}`)
+		default:
+			fmt.Fprint(w, `{"errors": [{"message": "unexpected query"}]}`)
+		}
+	}))
+	defer server.Close()
+	endpoint := server.URL + "/graphql"
+	opts := []GithubApiOption{WithOrg("kubernetes"), WithRepo("community")}
+
+	// a new release creates a discussion in the category
+	discussion, err := PublishDiscussion(client, endpoint, "Announcements", "v1.17.0", "the notes", opts...)
+	require.Nil(t, err)
+	require.Equal(t, "https://github.com/kubernetes/community/discussions/2", discussion)
+	require.Equal(t, map[string]interface{}{
+		"repositoryId": "R_1",
+		"categoryId":   "C_2",
+		"title":        "v1.17.0",
+		"body":         "the notes",
+	}, mutations[0])
+
+	// the discussion of an existing title is updated
+	discussion, err = PublishDiscussion(client, endpoint, "Announcements", "v1.16.0", "new notes", opts...)
+	require.Nil(t, err)
+	require.Equal(t, "https://github.com/kubernetes/community/discussions/1", discussion)
+	require.Equal(t, map[string]interface{}{"discussionId": "D_1", "body": "new notes"}, mutations[1])
+
+	_, err = PublishDiscussion(client, endpoint, "Releases", "v1.17.0", "the notes", opts...)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "General, Announcements")
+	require.Len(t, mutations, 2)
+}
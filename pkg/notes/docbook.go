@@ -0,0 +1,187 @@
+package notes
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// docbookHeader is the XML declaration and document type of the DocBook
+// output
+const docbookHeader = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE article PUBLIC "-//OASIS//DTD DocBook XML V4.5//EN" "http://www.oasis-open.org/docbook/xml/4.5/docbookx.dtd">
+`
+
+// docbookLink matches a markdown link, like [#123](https://...)
+var docbookLink = regexp.MustCompile(`\[([^\]]*)\]\(([^)\s]*)\)`)
+
+// docbookSection is a section of the DocBook output with its notes, followed
+// by its subsections
+type docbookSection struct {
+	title    string
+	key      string
+	notes    []string
+	sections []docbookSection
+}
+
+// RenderDocBook writes the document to the supplied io.Writer as a DocBook
+// article, with a section per group of the markdown format and an itemized
+// list of its notes. The links of the notes, like the ones to the PR and its
+// author, are rendered as ulinks. The output is checked to be well-formed XML
+// before it is written. The options must match the ones the document was
+// created with.
+func RenderDocBook(doc *Document, w io.Writer, opts ...DocumentOption) error {
+	c := documentConfigFromOpts(opts...)
+	buf := &bytes.Buffer{}
+	buf.WriteString(docbookHeader)
+	buf.WriteString("<article>\n  <title>Release Notes</title>\n")
+	for _, s := range docbookSections(doc, c) {
+		writeDocBookSection(buf, s, 1)
+	}
+	buf.WriteString("</article>\n")
+
+	if err := checkWellFormed(buf.Bytes()); err != nil {
+		return errors.Wrap(err, "rendered DocBook is not well-formed")
+	}
+
+	_, err := io.Copy(w, buf)
+	return err
+}
+
+// docbookSections returns the non-empty sections of the document in the same
+// order as the markdown format of its layout
+func docbookSections(doc *Document, c *documentConfig) []docbookSection {
+	sections := []docbookSection{}
+	add := func(s docbookSection) {
+		if len(s.notes) > 0 || len(s.sections) > 0 {
+			sections = append(sections, s)
+		}
+	}
+	grouped := func(kind sectionKind, groups map[string][]string) docbookSection {
+		names := []string{}
+		for name := range groups {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		s := docbookSection{title: sectionTitle(kind, c), key: sectionKeys[kind]}
+		for _, name := range names {
+			group := section{kind: kind, group: name}
+			s.sections = append(s.sections, docbookSection{
+				title: sectionGroupTitle(group),
+				key:   sectionGroupKey(group),
+				notes: groups[name],
+			})
+		}
+		return s
+	}
+
+	add(docbookSection{title: sectionTitle(sectionSecurity, c), key: sectionKeys[sectionSecurity], notes: doc.Security})
+
+	if doc.Layout == LayoutKubernetes {
+		add(docbookSection{title: "Urgent Upgrade Notes", key: "urgent-upgrade-notes", notes: doc.ActionRequired})
+		kinds := docbookSection{title: "Changes by Kind", key: "changes-by-kind"}
+		for _, section := range kubernetesKindSections {
+			title := kubernetesKindTitle(section.title, c)
+			if len(doc.Kinds[title]) > 0 {
+				kinds.sections = append(kinds.sections, docbookSection{title: title, key: section.key, notes: doc.Kinds[title]})
+			}
+		}
+		add(kinds)
+		add(docbookSection{title: sectionTitle(sectionDependencies, c), key: sectionKeys[sectionDependencies], notes: doc.Dependencies})
+		return sections
+	}
+
+	add(docbookSection{title: sectionTitle(sectionActionRequired, c), key: sectionKeys[sectionActionRequired], notes: doc.ActionRequired})
+	add(docbookSection{title: sectionTitle(sectionNewFeatures, c), key: sectionKeys[sectionNewFeatures], notes: doc.NewFeatures})
+	add(docbookSection{title: sectionTitle(sectionAPIChanges, c), key: sectionKeys[sectionAPIChanges], notes: doc.APIChanges})
+	add(grouped(sectionDuplicates, doc.Duplicates))
+	add(grouped(sectionSIGs, doc.SIGs))
+	add(grouped(sectionAreas, doc.Areas))
+	add(docbookSection{title: sectionTitle(sectionBugFixes, c), key: sectionKeys[sectionBugFixes], notes: doc.BugFixes})
+	add(docbookSection{title: sectionTitle(sectionUncategorized, c), key: sectionKeys[sectionUncategorized], notes: doc.Uncategorized})
+	add(docbookSection{title: sectionTitle(sectionDependencies, c), key: sectionKeys[sectionDependencies], notes: doc.Dependencies})
+	return sections
+}
+
+// writeDocBookSection writes the section and its subsections, indented by
+// depth levels
+func writeDocBookSection(b *bytes.Buffer, s docbookSection, depth int) {
+	indent := strings.Repeat("  ", depth)
+	b.WriteString(indent + `<section id="` + docbookEscape(AnchorFor(s.key)) + `">` + "\n")
+	b.WriteString(indent + "  <title>" + docbookEscape(s.title) + "</title>\n")
+	if len(s.notes) > 0 {
+		b.WriteString(indent + "  <itemizedlist>\n")
+		for _, note := range s.notes {
+			b.WriteString(indent + "    <listitem>\n")
+			for _, para := range docbookParas(note) {
+				b.WriteString(indent + "      <para>" + para + "</para>\n")
+			}
+			b.WriteString(indent + "    </listitem>\n")
+		}
+		b.WriteString(indent + "  </itemizedlist>\n")
+	}
+	for _, sub := range s.sections {
+		writeDocBookSection(b, sub, depth+1)
+	}
+	b.WriteString(indent + "</section>\n")
+}
+
+// docbookParas converts the markdown of a note to the escaped content of its
+// paragraphs. Paragraphs are separated by blank lines, and the markdown links
+// are converted to ulinks.
+func docbookParas(markdown string) []string {
+	markdown = strings.TrimPrefix(strings.TrimSpace(markdown), "- ")
+
+	paras := []string{}
+	for _, para := range regexp.MustCompile(`\n\s*\n`).Split(markdown, -1) {
+		lines := strings.Split(para, "\n")
+		for i := range lines {
+			lines[i] = strings.TrimSpace(lines[i])
+		}
+		text := strings.Join(lines, " ")
+		if text == "" {
+			continue
+		}
+
+		var b strings.Builder
+		last := 0
+		for _, match := range docbookLink.FindAllStringSubmatchIndex(text, -1) {
+			b.WriteString(docbookEscape(text[last:match[0]]))
+			b.WriteString(`<ulink url="` + docbookEscape(text[match[4]:match[5]]) + `">`)
+			b.WriteString(docbookEscape(text[match[2]:match[3]]) + "</ulink>")
+			last = match[1]
+		}
+		b.WriteString(docbookEscape(text[last:]))
+		paras = append(paras, b.String())
+	}
+	return paras
+}
+
+// docbookEscape escapes the text for XML character data and attribute values
+func docbookEscape(s string) string {
+	var b strings.Builder
+	// writing to a strings.Builder never fails
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// checkWellFormed decodes every token of the XML document, which fails if it
+// is not well-formed
+func checkWellFormed(content []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
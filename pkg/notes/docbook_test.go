@@ -0,0 +1,91 @@
+package notes
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderDocBook(t *testing.T) {
+	doc, err := CreateDocument(ReleaseNoteList{
+		1: &ReleaseNote{
+			PrNumber:  1,
+			PrUrl:     "https://github.com/kubernetes/kubernetes/pull/1",
+			Author:    "jdoe",
+			AuthorUrl: "https://github.com/jdoe",
+			Text:      "Fixed <script> & friends",
+			Markdown:  "Fixed <script> & friends ([#1](https://github.com/kubernetes/kubernetes/pull/1), [@jdoe](https://github.com/jdoe))",
+			Kinds:     []string{"bug"},
+		},
+		2: &ReleaseNote{
+			PrNumber: 2,
+			Text:     "Improved the scheduler",
+			Markdown: "Improved the scheduler ([#2](https://github.com/kubernetes/kubernetes/pull/2))\n\n  Courtesy of SIG Scheduling",
+			SIGs:     []string{"scheduling"},
+		},
+	})
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, RenderDocBook(doc, buf))
+
+	type para struct {
+		Content string `xml:",innerxml"`
+	}
+	type section struct {
+		ID       string    `xml:"id,attr"`
+		Title    string    `xml:"title"`
+		Items    []para    `xml:"itemizedlist>listitem>para"`
+		Sections []section `xml:"section"`
+	}
+	article := struct {
+		XMLName  xml.Name  `xml:"article"`
+		Title    string    `xml:"title"`
+		Sections []section `xml:"section"`
+	}{}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &article))
+
+	require.Equal(t, "Release Notes", article.Title)
+	require.Len(t, article.Sections, 2)
+
+	sigs := article.Sections[0]
+	require.Equal(t, "sigs", sigs.ID)
+	require.Equal(t, "Notes from Individual SIGs", sigs.Title)
+	require.Empty(t, sigs.Items)
+	require.Len(t, sigs.Sections, 1)
+	require.Equal(t, "sig-scheduling", sigs.Sections[0].ID)
+	require.Equal(t, "SIG Scheduling", sigs.Sections[0].Title)
+	require.Equal(t, []para{
+		{Content: `Improved the scheduler (<ulink url="https://github.com/kubernetes/kubernetes/pull/2">#2</ulink>)`},
+		{Content: "Courtesy of SIG Scheduling"},
+	}, sigs.Sections[0].Items)
+
+	bugs := article.Sections[1]
+	require.Equal(t, "bug-fixes", bugs.ID)
+	require.Equal(t, []para{
+		{Content: `Fixed &lt;script&gt; &amp; friends (<ulink url="https://github.com/kubernetes/kubernetes/pull/1">#1</ulink>, <ulink url="https://github.com/jdoe">@jdoe</ulink>)`},
+	}, bugs.Items)
+}
+
+func TestRenderDocBookKubernetesLayout(t *testing.T) {
+	doc, err := CreateDocument(ReleaseNoteList{
+		1: &ReleaseNote{PrNumber: 1, Markdown: "Removed a flag", ActionRequired: true},
+		2: &ReleaseNote{PrNumber: 2, Markdown: "Added a flag", Kinds: []string{"feature"}},
+	}, WithLayout(LayoutKubernetes))
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, RenderDocBook(doc, buf))
+	require.Contains(t, buf.String(), `<section id="urgent-upgrade-notes">`)
+	require.Contains(t, buf.String(), `<section id="changes-by-kind">`)
+	require.Contains(t, buf.String(), `<section id="kind-feature">`)
+	require.NoError(t, checkWellFormed(buf.Bytes()))
+}
+
+func TestCheckWellFormed(t *testing.T) {
+	require.NoError(t, checkWellFormed([]byte(`<article><para>a &amp; b</para></article>`)))
+	require.Error(t, checkWellFormed([]byte(`<article><para>a & b</para></article>`)))
+	require.Error(t, checkWellFormed([]byte(`<article><para>a</article>`)))
+}
@@ -0,0 +1,93 @@
+package notes
+
+import (
+	"strings"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-github/v27/github"
+)
+
+// DefaultDocsLabels are the labels which mark documentation only PRs
+var DefaultDocsLabels = []string{"area/docs"}
+
+// WithExcludeDocs allows the caller to drop the notes of documentation only
+// PRs, which are labeled with one of the kind, area or sig labels, like
+// "area/docs". The labels are known anyway, so this costs no API requests.
+func WithExcludeDocs(labels []string) GithubApiOption {
+	return func(c *githubApiConfig) {
+		c.excludeDocs = true
+		c.docsLabels = labels
+	}
+}
+
+// WithDocsPaths allows the caller to additionally drop the notes of PRs which
+// only change files below the provided directory prefixes, like "docs/". This
+// costs additional API requests to list the files of every PR, so the check is
+// limited to the first limit PRs unless limit is zero. The budget is shared by
+// all calls which are passed the same option. Only effective together with
+// WithExcludeDocs.
+func WithDocsPaths(paths []string, limit int) GithubApiOption {
+	var budget *inferenceBudget
+	if limit > 0 {
+		budget = &inferenceBudget{remaining: limit}
+	}
+	return func(c *githubApiConfig) {
+		c.docsPaths = paths
+		c.docsBudget = budget
+	}
+}
+
+// IsDocsOnly returns true if all of the files are below one of the directory
+// prefixes. A PR without files is not considered documentation only.
+func IsDocsOnly(files, paths []string) bool {
+	if len(files) == 0 {
+		return false
+	}
+	for _, file := range files {
+		docs := false
+		for _, path := range paths {
+			if strings.HasPrefix(file, path) {
+				docs = true
+				break
+			}
+		}
+		if !docs {
+			return false
+		}
+	}
+	return true
+}
+
+// isDocsNote returns true if the note belongs to a documentation only PR, as
+// determined by its labels and, if requested, its changed files. Failures to
+// list the files are logged and keep the note.
+func isDocsNote(client *github.Client, logger log.Logger, note *ReleaseNote, opts ...GithubApiOption) bool {
+	c := configFromOpts(opts...)
+
+	labels := NoteLabels(ReleaseNoteList{note.PrNumber: note})
+	for _, label := range c.docsLabels {
+		if HasString(labels, label) {
+			return true
+		}
+	}
+
+	if len(c.docsPaths) == 0 {
+		return false
+	}
+	if c.docsBudget != nil {
+		ok, exhausted := c.docsBudget.take()
+		if exhausted {
+			warnPR(logger, c, WarningBudgetExhausted, note.PrNumber, "docs path budget exhausted, the changed files of the remaining PRs are not checked", nil)
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	files, err := ListPRFiles(client, note.PrNumber, opts...)
+	if err != nil {
+		warnPR(logger, c, WarningFetchFailed, note.PrNumber, "error listing the files of the PR to check for documentation only changes", err)
+		return false
+	}
+	return IsDocsOnly(files, c.docsPaths)
+}
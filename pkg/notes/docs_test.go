@@ -0,0 +1,53 @@
+package notes
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsDocsOnly(t *testing.T) {
+	paths := []string{"docs/", "site/content/"}
+	require.True(t, IsDocsOnly([]string{"docs/README.md", "site/content/index.md"}, paths))
+	require.False(t, IsDocsOnly([]string{"docs/README.md", "pkg/kubelet/kubelet.go"}, paths))
+	require.False(t, IsDocsOnly([]string{"docsite/index.md"}, paths))
+	require.False(t, IsDocsOnly([]string{}, paths))
+}
+
+func TestIsDocsNote(t *testing.T) {
+	requests := 0
+	client, server := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		files := map[string]string{
+			"/repos/kubernetes/kubernetes/pulls/1/files": `[{"filename": "docs/README.md"}]`,
+			"/repos/kubernetes/kubernetes/pulls/2/files": `[{"filename": "docs/README.md"}, {"filename": "cmd/main.go"}]`,
+		}
+		fmt.Fprint(w, files[r.URL.Path])
+	}))
+	defer server.Close()
+
+	labeled := &ReleaseNote{PrNumber: 3, Areas: []string{"docs"}}
+	docsOnly := &ReleaseNote{PrNumber: 1}
+	mixed := &ReleaseNote{PrNumber: 2}
+
+	// the label check is free of API requests
+	opts := []GithubApiOption{WithExcludeDocs(DefaultDocsLabels)}
+	require.True(t, isDocsNote(client, log.NewNopLogger(), labeled, opts...))
+	require.False(t, isDocsNote(client, log.NewNopLogger(), docsOnly, opts...))
+	require.Equal(t, 0, requests)
+
+	opts = append(opts, WithDocsPaths([]string{"docs/"}, 0))
+	require.True(t, isDocsNote(client, log.NewNopLogger(), docsOnly, opts...))
+	require.False(t, isDocsNote(client, log.NewNopLogger(), mixed, opts...))
+	require.Equal(t, 2, requests)
+
+	// files are no longer checked once the budget is used up
+	requests = 0
+	opts = []GithubApiOption{WithExcludeDocs(DefaultDocsLabels), WithDocsPaths([]string{"docs/"}, 1)}
+	require.False(t, isDocsNote(client, log.NewNopLogger(), mixed, opts...))
+	require.False(t, isDocsNote(client, log.NewNopLogger(), docsOnly, opts...))
+	require.Equal(t, 1, requests)
+}
@@ -1,14 +1,20 @@
 package notes
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"regexp"
 	"sort"
 	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
 )
 
 // Document represents the underlying structure of a release notes document.
 type Document struct {
+	Security       []string            `json:"security"`
 	NewFeatures    []string            `json:"new_features"`
 	ActionRequired []string            `json:"action_required"`
 	APIChanges     []string            `json:"api_changes"`
@@ -16,12 +22,399 @@ type Document struct {
 	SIGs           map[string][]string `json:"sigs"`
 	BugFixes       []string            `json:"bug_fixes"`
 	Uncategorized  []string            `json:"uncategorized"`
+
+	// Layout is the arrangement of the sections, empty for LayoutFlat
+	Layout DocumentLayout `json:"layout,omitempty"`
+
+	// Kinds are the notes per kind section of the LayoutKubernetes
+	Kinds map[string][]string `json:"kinds,omitempty"`
+
+	// Dependencies are the notes of dependency updates, if collapsed
+	Dependencies []string `json:"dependencies,omitempty"`
+
+	// Areas are the notes per area, if grouped by area instead of SIG
+	Areas map[string][]string `json:"areas,omitempty"`
+}
+
+// sectionKind identifies a top level section of a release notes document. The
+// constants are declared in the order in which the sections are rendered.
+type sectionKind int
+
+const (
+	sectionSecurity sectionKind = iota
+	sectionActionRequired
+	sectionNewFeatures
+	sectionAPIChanges
+	sectionDuplicates
+	sectionSIGs
+	sectionAreas
+	sectionBugFixes
+	sectionUncategorized
+	sectionDependencies
+)
+
+// section is a place within a document where a note can be listed. The group
+// is only set for sections which are further divided, like the SIG sections.
+type section struct {
+	kind  sectionKind
+	group string
+}
+
+// sectionsForNote returns all sections a note belongs to
+func sectionsForNote(note *ReleaseNote, c *documentConfig) []section {
+	if collapsedDependency(note, c) {
+		return []section{{kind: sectionDependencies}}
+	}
+	if note.IsSecurity {
+		return []section{{kind: sectionSecurity}}
+	}
+	if note.ActionRequired {
+		return []section{{kind: sectionActionRequired}}
+	}
+	if note.Feature {
+		return []section{{kind: sectionNewFeatures}}
+	}
+	if note.Duplicate && c.groupBy != GroupByArea {
+		return []section{{kind: sectionDuplicates, group: prettifySigList(note.SIGs)}}
+	}
+
+	sections := []section{}
+	if c.groupBy == GroupByArea {
+		if area := primaryArea(note, c.areaPriority); area != "" {
+			sections = append(sections, section{kind: sectionAreas, group: area})
+		}
+	} else {
+		for _, sig := range note.SIGs {
+			sections = append(sections, section{kind: sectionSIGs, group: sig})
+		}
+	}
+
+	isBug := false
+	for _, kind := range note.Kinds {
+		switch kind {
+		case "bug":
+			// if the PR has kind/bug, we want to make a note of it, but we don't
+			// include it in the Bug Fixes section until we haven't processed all
+			// kinds and determined that it has no other categorization label.
+			isBug = true
+		case "feature":
+			continue
+		case "api-change", "new-api":
+			sections = append(sections, section{kind: sectionAPIChanges})
+		}
+	}
+
+	// if the note has not been categorized so far, we can toss in one of two
+	// buckets
+	if len(sections) == 0 {
+		if isBug {
+			sections = append(sections, section{kind: sectionBugFixes})
+		} else {
+			sections = append(sections, section{kind: sectionUncategorized})
+		}
+	}
+	return sections
+}
+
+// sectionTitles are the headings of the top level sections of a document
+var sectionTitles = map[sectionKind]string{
+	sectionSecurity:       "Security",
+	sectionActionRequired: "Action Required",
+	sectionNewFeatures:    "New Features",
+	sectionAPIChanges:     "API Changes",
+	sectionDuplicates:     "Notes From Multiple SIGs",
+	sectionSIGs:           "Notes from Individual SIGs",
+	sectionAreas:          "Notes by Area",
+	sectionBugFixes:       "Bug Fixes",
+	sectionUncategorized:  "Other Notable Changes",
+	sectionDependencies:   "Dependency Updates",
+}
+
+// sectionTitle returns the heading of a top level section, which is the title
+// set with WithOtherSectionTitle for the section of uncategorized notes
+func sectionTitle(kind sectionKind, c *documentConfig) string {
+	if kind == sectionUncategorized && c.otherTitle != "" {
+		return c.otherTitle
+	}
+	return sectionTitles[kind]
+}
+
+// WithOtherSectionTitle allows the caller to rename the section of the notes
+// which belong to no SIG and have no kind with a section of their own. It is
+// the last section of every layout and titled "Other Notable Changes" by
+// default.
+func WithOtherSectionTitle(title string) DocumentOption {
+	return func(c *documentConfig) {
+		c.otherTitle = title
+	}
+}
+
+// newNoteMarker highlights notes which were added by the current run
+const newNoteMarker = "🆕 "
+
+// noteListItem returns the markdown of a note, rendered with the note template
+// if any, prefixed with a marker if the note is new
+func noteListItem(note *ReleaseNote, c *documentConfig) (string, error) {
+	addRenderWarnings(note, c)
+	if c.enforceStyle {
+		note = styledNote(note)
+	}
+
+	markdown := note.Markdown
+	if c.noteTemplate != nil {
+		rendered, err := executeNoteTemplate(c.noteTemplate, note)
+		if err != nil {
+			return "", err
+		}
+		markdown = rendered
+	}
+	// task lists are an extension of GitHub Flavored Markdown
+	if len(note.Subtasks) > 0 && c.flavor != FlavorCommonMark {
+		markdown += subtasksMarkdown(note.Subtasks)
+	}
+	markdown = formatPRLinks(markdown, c.prLinkFormat)
+	if c.flavor == FlavorCommonMark {
+		markdown = commonMarkAutolinks(markdown)
+	}
+	if c.showStats && note.Stats != nil {
+		markdown = statsSuffix(markdown, note.Stats)
+	}
+
+	if !note.New {
+		return markdown, nil
+	}
+	if strings.HasPrefix(markdown, "- ") {
+		return "- " + newNoteMarker + strings.TrimPrefix(markdown, "- "), nil
+	}
+	return newNoteMarker + markdown, nil
+}
+
+// MergeReleaseNotes adds the notes of a previous run to the list, unless the
+// list already contains a note for the same PR. If annotateNew is set, the
+// notes which were not part of the previous run are marked as new, while the
+// carried over notes are not.
+func MergeReleaseNotes(notes, previous ReleaseNoteList, annotateNew bool) {
+	for number, note := range notes {
+		_, carried := previous[number]
+		note.New = annotateNew && !carried
+	}
+	for number, note := range previous {
+		if _, ok := notes[number]; !ok {
+			note.New = false
+			notes[number] = note
+		}
+	}
+}
+
+// DocumentOption is a type which allows for the expression of options to
+// customize how release notes are organized within a document
+type DocumentOption func(*documentConfig)
+
+type documentConfig struct {
+	kindPriority  []string
+	layout        DocumentLayout
+	stableAnchors bool
+	noteTemplate  *template.Template
+	labelLegend   bool
+	labelColors   map[string]string
+	flavor        MarkdownFlavor
+	enforceStyle  bool
+	showStats     bool
+	sortBy        NoteOrder
+	collapseDeps  bool
+	emptySections bool
+	sortKeys      []SortKey
+	otherTitle    string
+	patchBuckets  bool
+	prLinkFormat  string
+	warnings      *Warnings
+	groupBy       NoteGrouping
+	areaPriority  []string
+	compact       bool
+	formats       formatsConfig
+}
+
+func documentConfigFromOpts(opts ...DocumentOption) *documentConfig {
+	c := &documentConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithKindPriority allows the caller to order the notes within every section
+// by their kind, like "feature" before "bug". Notes whose kinds are not part
+// of the list come after the listed ones, notes without a kind come last.
+// Notes of the same priority are ordered by their PR number.
+func WithKindPriority(kinds []string) DocumentOption {
+	return func(c *documentConfig) {
+		c.kindPriority = kinds
+	}
+}
+
+// kindRank returns the position of the highest priority kind of the note
+// within the priority list
+func kindRank(note *ReleaseNote, priority []string) int {
+	if len(note.Kinds) == 0 {
+		return len(priority) + 1
+	}
+
+	rank := len(priority)
+	for i, kind := range priority {
+		if i < rank && HasString(note.Kinds, kind) {
+			rank = i
+		}
+	}
+	return rank
+}
+
+// sectionKeys are the canonical keys of the top level sections, which are
+// used for stable anchors and never change with the section titles
+var sectionKeys = map[sectionKind]string{
+	sectionSecurity:       "security",
+	sectionActionRequired: "action-required",
+	sectionNewFeatures:    "new-features",
+	sectionAPIChanges:     "api-changes",
+	sectionDuplicates:     "duplicates",
+	sectionSIGs:           "sigs",
+	sectionAreas:          "areas",
+	sectionBugFixes:       "bug-fixes",
+	sectionUncategorized:  "uncategorized",
+	sectionDependencies:   "dependency-updates",
+}
+
+// sectionGroupKey returns the canonical key of a group within a section, like
+// "sig-node" for the notes of SIG Node
+func sectionGroupKey(s section) string {
+	switch s.kind {
+	case sectionSIGs:
+		return "sig-" + s.group
+	case sectionAreas:
+		return "area-" + s.group
+	}
+	return sectionKeys[s.kind] + "-" + s.group
+}
+
+// isGroupedSection reports whether a top level section is divided into groups
+func isGroupedSection(kind sectionKind) bool {
+	return kind == sectionDuplicates || kind == sectionSIGs || kind == sectionAreas
+}
+
+// sectionGroupTitle returns the heading of a group within a section, like
+// "SIG Node" for the notes of SIG Node
+func sectionGroupTitle(s section) string {
+	switch s.kind {
+	case sectionSIGs:
+		return "SIG " + prettySIG(s.group)
+	case sectionAreas:
+		return "Area " + prettySIG(s.group)
+	}
+	return s.group
+}
+
+// AnchorFor returns the anchor of the section with the provided canonical key,
+// like "sig-api-machinery". All characters besides lowercase letters and
+// digits are replaced by dashes.
+func AnchorFor(section string) string {
+	anchor := regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(strings.ToLower(section), "-")
+	return strings.Trim(anchor, "-")
+}
+
+// WithStableAnchors allows the caller to precede every heading of the rendered
+// markdown with an HTML anchor, which is derived from the canonical key of the
+// section instead of the heading text. Inbound links using these anchors keep
+// working if the wording of the headings changes.
+func WithStableAnchors() DocumentOption {
+	return func(c *documentConfig) {
+		c.stableAnchors = true
+	}
+}
+
+// WithEmptySections allows the caller to render the headings of all top level
+// sections, even the ones without notes, so that the documents of all releases
+// have the same skeleton. By default, sections without notes are omitted.
+func WithEmptySections() DocumentOption {
+	return func(c *documentConfig) {
+		c.emptySections = true
+	}
+}
+
+// missingSections returns the top level sections which none of the notes
+// belongs to, if they are rendered with WithEmptySections
+func missingSections(present map[sectionKind]bool, c *documentConfig) []section {
+	if !c.emptySections {
+		return nil
+	}
+	last := sectionUncategorized
+	if c.collapseDeps {
+		last = sectionDependencies
+	}
+
+	// only the sections of the grouping are part of the skeleton
+	grouping := map[sectionKind]bool{sectionDuplicates: true, sectionSIGs: true}
+	if c.groupBy == GroupByArea {
+		grouping = map[sectionKind]bool{sectionAreas: true}
+	}
+
+	missing := []section{}
+	for kind := sectionSecurity; kind <= last; kind++ {
+		if isGroupedSection(kind) && !grouping[kind] {
+			continue
+		}
+		if !present[kind] {
+			missing = append(missing, section{kind: kind})
+		}
+	}
+	return missing
+}
+
+// headingMarkdown renders a heading with the given markdown prefix, like "##",
+// preceded by the stable anchor of the key if requested
+func headingMarkdown(prefix, title, key string, c *documentConfig) string {
+	heading := prefix + " " + title + "\n\n"
+	if c.stableAnchors {
+		heading = fmt.Sprintf("<a id=\"%s\"></a>\n", anchorForFlavor(key, c.flavor)) + heading
+	}
+	return heading
+}
+
+// sortedNotes returns the notes of the list ordered by their kind priority, if
+// any, their sort keys, their size with SortBySize and their PR number
+func sortedNotes(notes ReleaseNoteList, opts ...DocumentOption) []*ReleaseNote {
+	c := documentConfigFromOpts(opts...)
+
+	sorted := make([]*ReleaseNote, 0, len(notes))
+	for _, note := range notes {
+		sorted = append(sorted, note)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if len(c.kindPriority) > 0 {
+			ri, rj := kindRank(sorted[i], c.kindPriority), kindRank(sorted[j], c.kindPriority)
+			if ri != rj {
+				return ri < rj
+			}
+		}
+		for _, key := range c.sortKeys {
+			if cmp := compareSortKey(sorted[i], sorted[j], key, c); cmp != 0 {
+				return cmp < 0
+			}
+		}
+		if c.sortBy == SortBySize {
+			si, sj := sorted[i].Stats.Size(), sorted[j].Stats.Size()
+			if si != sj {
+				return si > sj
+			}
+		}
+		return sorted[i].PrNumber < sorted[j].PrNumber
+	})
+	return sorted
 }
 
 // CreateDocument assembles an organized document from an unorganized set of
 // release notes
-func CreateDocument(notes ReleaseNoteList) (*Document, error) {
+func CreateDocument(notes ReleaseNoteList, opts ...DocumentOption) (*Document, error) {
 	doc := &Document{
+		Security:       []string{},
 		NewFeatures:    []string{},
 		ActionRequired: []string{},
 		APIChanges:     []string{},
@@ -31,64 +424,80 @@ func CreateDocument(notes ReleaseNoteList) (*Document, error) {
 		Uncategorized:  []string{},
 	}
 
-	for _, note := range notes {
-		if note.ActionRequired {
-			doc.ActionRequired = append(doc.ActionRequired, note.Markdown)
-		} else if note.Feature {
-			doc.NewFeatures = append(doc.NewFeatures, note.Markdown)
-		} else if note.Duplicate {
-			header := prettifySigList(note.SIGs)
-			existingNotes, ok := doc.Duplicates[header]
-			if ok {
-				doc.Duplicates[header] = append(existingNotes, note.Markdown)
-			} else {
-				doc.Duplicates[header] = []string{note.Markdown}
-			}
-		} else {
-			categorized := false
-
-			for _, sig := range note.SIGs {
-				categorized = true
-				notesForSIG, ok := doc.SIGs[sig]
-				if ok {
-					doc.SIGs[sig] = append(notesForSIG, note.Markdown)
-				} else {
-					doc.SIGs[sig] = []string{note.Markdown}
-				}
-			}
-			isBug := false
-			for _, kind := range note.Kinds {
-				switch kind {
-				case "bug":
-					// if the PR has kind/bug, we want to make a note of it, but we don't
-					// include it in the Bug Fixes section until we haven't processed all
-					// kinds and determined that it has no other categorization label.
-					isBug = true
-				case "feature":
-					continue
-				case "api-change", "new-api":
-					categorized = true
-					doc.APIChanges = append(doc.APIChanges, note.Markdown)
-				}
-			}
+	c := documentConfigFromOpts(opts...)
+	if c.layout == LayoutKubernetes {
+		return createKubernetesDocument(doc, sortedNotes(notes, opts...), c)
+	}
 
-			// if the note has not been categorized so far, we can toss in one of two
-			// buckets
-			if !categorized {
-				if isBug {
-					doc.BugFixes = append(doc.BugFixes, note.Markdown)
-				} else {
-					doc.Uncategorized = append(doc.Uncategorized, note.Markdown)
+	for _, note := range sortedNotes(notes, opts...) {
+		item, err := noteListItem(note, c)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range sectionsForNote(note, c) {
+			switch s.kind {
+			case sectionSecurity:
+				doc.Security = append(doc.Security, item)
+			case sectionActionRequired:
+				doc.ActionRequired = append(doc.ActionRequired, item)
+			case sectionNewFeatures:
+				doc.NewFeatures = append(doc.NewFeatures, item)
+			case sectionAPIChanges:
+				doc.APIChanges = append(doc.APIChanges, item)
+			case sectionDuplicates:
+				doc.Duplicates[s.group] = append(doc.Duplicates[s.group], item)
+			case sectionSIGs:
+				doc.SIGs[s.group] = append(doc.SIGs[s.group], item)
+			case sectionAreas:
+				if doc.Areas == nil {
+					doc.Areas = map[string][]string{}
 				}
+				doc.Areas[s.group] = append(doc.Areas[s.group], item)
+			case sectionBugFixes:
+				doc.BugFixes = append(doc.BugFixes, item)
+			case sectionUncategorized:
+				doc.Uncategorized = append(doc.Uncategorized, item)
+			case sectionDependencies:
+				doc.Dependencies = append(doc.Dependencies, item)
 			}
 		}
 	}
 	return doc, nil
 }
 
+// RenderToBytes renders the list of release notes in the provided format,
+// like Render, and returns the result.
+func RenderToBytes(notes ReleaseNoteList, format string, opts ...DocumentOption) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := Render(notes, format, buf, opts...); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 // RenderMarkdown accepts a Document and writes a version of that document to
 // supplied io.Writer in markdown format.
-func RenderMarkdown(doc *Document, w io.Writer) error {
+func RenderMarkdown(doc *Document, w io.Writer, opts ...DocumentOption) error {
+	c := documentConfigFromOpts(opts...)
+	if !c.compact {
+		return renderMarkdown(doc, w, c)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := renderMarkdown(doc, buf, c); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, compactMarkdown(buf.String()))
+	return err
+}
+
+// renderMarkdown writes the document in markdown format as RenderMarkdown
+// does, before it is compacted
+func renderMarkdown(doc *Document, w io.Writer, c *documentConfig) error {
+	if doc.Layout == LayoutKubernetes {
+		return renderKubernetesMarkdown(doc, w, c)
+	}
+
 	// we always want to render the document with SIGs in alphabetical order
 	sortedSIGs := []string{}
 	for sig := range doc.SIGs {
@@ -96,6 +505,20 @@ func RenderMarkdown(doc *Document, w io.Writer) error {
 	}
 	sort.Strings(sortedSIGs)
 
+	// and the areas
+	sortedAreas := []string{}
+	for area := range doc.Areas {
+		sortedAreas = append(sortedAreas, area)
+	}
+	sort.Strings(sortedAreas)
+
+	// the same applies to the headers of notes from multiple SIGs
+	sortedDuplicates := []string{}
+	for header := range doc.Duplicates {
+		sortedDuplicates = append(sortedDuplicates, header)
+	}
+	sort.Strings(sortedDuplicates)
+
 	// this is a helper so that we don't have to check err != nil on every write
 
 	// first, we create a long-lived err that we can re-use
@@ -120,9 +543,18 @@ func RenderMarkdown(doc *Document, w io.Writer) error {
 		write(s + "\n")
 	}
 
+	// the "Security" section comes before everything else
+	if len(doc.Security) > 0 || c.emptySections {
+		write(headingMarkdown("##", sectionTitle(sectionSecurity, c), sectionKeys[sectionSecurity], c))
+		for _, note := range doc.Security {
+			writeNote(note)
+		}
+		write("\n\n")
+	}
+
 	// the "Action Required" section
-	if len(doc.ActionRequired) > 0 {
-		write("## Action Required\n\n")
+	if len(doc.ActionRequired) > 0 || c.emptySections {
+		write(headingMarkdown("##", sectionTitle(sectionActionRequired, c), sectionKeys[sectionActionRequired], c))
 		for _, note := range doc.ActionRequired {
 			writeNote(note)
 		}
@@ -130,8 +562,8 @@ func RenderMarkdown(doc *Document, w io.Writer) error {
 	}
 
 	// the "New Feautres" section
-	if len(doc.NewFeatures) > 0 {
-		write("## New Features\n\n")
+	if len(doc.NewFeatures) > 0 || c.emptySections {
+		write(headingMarkdown("##", sectionTitle(sectionNewFeatures, c), sectionKeys[sectionNewFeatures], c))
 		for _, note := range doc.NewFeatures {
 			writeNote(note)
 		}
@@ -139,8 +571,8 @@ func RenderMarkdown(doc *Document, w io.Writer) error {
 	}
 
 	// the "API Changes" section
-	if len(doc.APIChanges) > 0 {
-		write("## API Changes\n\n")
+	if len(doc.APIChanges) > 0 || c.emptySections {
+		write(headingMarkdown("##", sectionTitle(sectionAPIChanges, c), sectionKeys[sectionAPIChanges], c))
 		for _, note := range doc.APIChanges {
 			writeNote(note)
 		}
@@ -148,11 +580,11 @@ func RenderMarkdown(doc *Document, w io.Writer) error {
 	}
 
 	// the "Duplicate Notes" section
-	if len(doc.Duplicates) > 0 {
-		write("## Notes From Multiple SIGs\n\n")
-		for header, notes := range doc.Duplicates {
-			write(fmt.Sprintf("### %s\n\n", header))
-			for _, note := range notes {
+	if len(doc.Duplicates) > 0 || (c.emptySections && c.groupBy != GroupByArea) {
+		write(headingMarkdown("##", sectionTitle(sectionDuplicates, c), sectionKeys[sectionDuplicates], c))
+		for _, header := range sortedDuplicates {
+			write(headingMarkdown("###", header, sectionGroupKey(section{kind: sectionDuplicates, group: header}), c))
+			for _, note := range doc.Duplicates[header] {
 				writeNote(note)
 			}
 			write("\n")
@@ -161,10 +593,11 @@ func RenderMarkdown(doc *Document, w io.Writer) error {
 	}
 
 	// each SIG gets a section (in alphabetical order)
-	if len(sortedSIGs) > 0 {
-		write("## Notes from Individual SIGs\n\n")
+	if len(sortedSIGs) > 0 || (c.emptySections && c.groupBy != GroupByArea) {
+		write(headingMarkdown("##", sectionTitle(sectionSIGs, c), sectionKeys[sectionSIGs], c))
 		for _, sig := range sortedSIGs {
-			write("### SIG " + prettySIG(sig) + "\n\n")
+			s := section{kind: sectionSIGs, group: sig}
+			write(headingMarkdown("###", sectionGroupTitle(s), sectionGroupKey(s), c))
 			for _, note := range doc.SIGs[sig] {
 				writeNote(note)
 			}
@@ -173,9 +606,23 @@ func RenderMarkdown(doc *Document, w io.Writer) error {
 		write("\n\n")
 	}
 
+	// as does each area, if grouped by area
+	if len(sortedAreas) > 0 || (c.emptySections && c.groupBy == GroupByArea) {
+		write(headingMarkdown("##", sectionTitle(sectionAreas, c), sectionKeys[sectionAreas], c))
+		for _, area := range sortedAreas {
+			s := section{kind: sectionAreas, group: area}
+			write(headingMarkdown("###", sectionGroupTitle(s), sectionGroupKey(s), c))
+			for _, note := range doc.Areas[area] {
+				writeNote(note)
+			}
+			write("\n")
+		}
+		write("\n\n")
+	}
+
 	// the "Bug Fixes" section
-	if len(doc.BugFixes) > 0 {
-		write("## Bug Fixes\n\n")
+	if len(doc.BugFixes) > 0 || c.emptySections {
+		write(headingMarkdown("##", sectionTitle(sectionBugFixes, c), sectionKeys[sectionBugFixes], c))
 		for _, note := range doc.BugFixes {
 			writeNote(note)
 		}
@@ -184,14 +631,112 @@ func RenderMarkdown(doc *Document, w io.Writer) error {
 
 	// we call the uncategorized notes "Other Notable Changes". ideally these
 	// notes would at least have a SIG label.
-	if len(doc.Uncategorized) > 0 {
-		write("## Other Notable Changes\n\n")
+	if len(doc.Uncategorized) > 0 || c.emptySections {
+		write(headingMarkdown("##", sectionTitle(sectionUncategorized, c), sectionKeys[sectionUncategorized], c))
 		for _, note := range doc.Uncategorized {
 			writeNote(note)
 		}
 		write("\n\n")
 	}
 
+	// the collapsed dependency updates come last
+	if len(doc.Dependencies) > 0 || (c.emptySections && c.collapseDeps) {
+		write(dependenciesOpening(len(doc.Dependencies), c))
+		for _, note := range doc.Dependencies {
+			writeNote(note)
+		}
+		write(dependenciesClosing)
+	}
+
+	return err
+}
+
+// RenderMarkdownStream writes the release notes to the supplied io.Writer in
+// the same markdown format as RenderMarkdown, but without assembling a Document
+// first. The notes are sorted into section order up front and every note is
+// written as soon as it is reached, which keeps the memory usage low for huge
+// lists of notes.
+func RenderMarkdownStream(notes ReleaseNoteList, w io.Writer, opts ...DocumentOption) error {
+	c := documentConfigFromOpts(opts...)
+	if c.layout != "" && c.layout != LayoutFlat {
+		return errors.Errorf("streaming does not support the %q layout", c.layout)
+	}
+
+	type entry struct {
+		section
+		note *ReleaseNote
+	}
+
+	entries := []entry{}
+	present := map[sectionKind]bool{}
+	dependencies := 0
+	for _, note := range sortedNotes(notes, opts...) {
+		for _, s := range sectionsForNote(note, c) {
+			entries = append(entries, entry{section: s, note: note})
+			present[s.kind] = true
+			if s.kind == sectionDependencies {
+				dependencies++
+			}
+		}
+	}
+	// the empty sections are entries without a note
+	for _, s := range missingSections(present, c) {
+		entries = append(entries, entry{section: s})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].kind != entries[j].kind {
+			return entries[i].kind < entries[j].kind
+		}
+		return entries[i].group < entries[j].group
+	})
+
+	var err error
+	write := func(s string) {
+		if err != nil {
+			return
+		}
+		_, err = io.WriteString(w, s)
+	}
+
+	for i, e := range entries {
+		grouped := isGroupedSection(e.kind) && e.note != nil
+		first := i == 0 || entries[i-1].kind != e.kind
+		last := i == len(entries)-1 || entries[i+1].kind != e.kind
+
+		if first && e.kind == sectionDependencies {
+			write(dependenciesOpening(dependencies, c))
+		} else if first {
+			write(headingMarkdown("##", sectionTitle(e.kind, c), sectionKeys[e.kind], c))
+		}
+		if grouped && (first || entries[i-1].group != e.group) {
+			write(headingMarkdown("###", sectionGroupTitle(e.section), sectionGroupKey(e.section), c))
+		}
+
+		if e.note != nil {
+			note, noteErr := noteListItem(e.note, c)
+			if noteErr != nil {
+				return noteErr
+			}
+			if !strings.HasPrefix(note, "- ") {
+				note = "- " + note
+			}
+			write(note + "\n")
+		}
+
+		if grouped && (last || entries[i+1].group != e.group) {
+			write("\n")
+		}
+		if last {
+			if e.kind == sectionDependencies {
+				write(dependenciesClosing)
+			} else if e.kind == sectionDuplicates {
+				write("\n")
+			} else {
+				write("\n\n")
+			}
+		}
+	}
+
 	return err
 }
 
@@ -235,3 +780,44 @@ func prettifySigList(sigs []string) string {
 
 	return sigList
 }
+
+// KindFiles are the names of the files of the recognized kinds when splitting
+// notes by kind. Notes without a recognized kind go into OtherKindFile.
+var KindFiles = map[string]string{
+	"feature":       "features.md",
+	"bug":           "bug-fixes.md",
+	"deprecation":   "deprecations.md",
+	"api-change":    "api-changes.md",
+	"cleanup":       "cleanups.md",
+	"documentation": "documentation.md",
+	"failing-test":  "failing-tests.md",
+	"flake":         "flakes.md",
+	"regression":    "regressions.md",
+}
+
+// OtherKindFile is the name of the file of notes without a recognized kind
+const OtherKindFile = "other.md"
+
+// SplitByKind splits the notes into one list per file of KindFiles as well as
+// OtherKindFile. Notes with multiple recognized kinds are part of multiple
+// lists. Every file is part of the result, even if it has no notes.
+func SplitByKind(notes ReleaseNoteList) map[string]ReleaseNoteList {
+	result := map[string]ReleaseNoteList{OtherKindFile: {}}
+	for _, file := range KindFiles {
+		result[file] = ReleaseNoteList{}
+	}
+
+	for number, note := range notes {
+		recognized := false
+		for _, kind := range note.Kinds {
+			if file, ok := KindFiles[kind]; ok {
+				result[file][number] = note
+				recognized = true
+			}
+		}
+		if !recognized {
+			result[OtherKindFile][number] = note
+		}
+	}
+	return result
+}
@@ -1,6 +1,11 @@
 package notes
 
 import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -21,3 +26,297 @@ func TestPrettySIG(t *testing.T) {
 		require.Equal(t, expected, (prettySIG(input)))
 	}
 }
+
+func TestRenderToBytes(t *testing.T) {
+	notes := syntheticNotes(20)
+
+	doc, err := CreateDocument(notes)
+	require.Nil(t, err)
+	expected := &bytes.Buffer{}
+	require.Nil(t, RenderMarkdown(doc, expected))
+
+	markdown, err := RenderToBytes(notes, "markdown")
+	require.Nil(t, err)
+	require.Equal(t, expected.String(), string(markdown))
+
+	jsonOutput, err := RenderToBytes(notes, "json")
+	require.Nil(t, err)
+	require.Nil(t, ValidateJSON(jsonOutput))
+
+	_, err = RenderToBytes(notes, "yaml")
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), `"yaml" is an unsupported format`)
+}
+
+func TestMergeReleaseNotes(t *testing.T) {
+	previous := ReleaseNoteList{
+		1: &ReleaseNote{PrNumber: 1, Markdown: "old note", New: true},
+		2: &ReleaseNote{PrNumber: 2, Markdown: "outdated note"},
+	}
+	notes := ReleaseNoteList{
+		2: &ReleaseNote{PrNumber: 2, Markdown: "updated note"},
+		3: &ReleaseNote{PrNumber: 3, Markdown: "new note"},
+	}
+
+	MergeReleaseNotes(notes, previous, true)
+	require.Len(t, notes, 3)
+	require.False(t, notes[1].New)
+	require.False(t, notes[2].New)
+	require.Equal(t, "updated note", notes[2].Markdown)
+	require.True(t, notes[3].New)
+
+	doc, err := CreateDocument(notes)
+	require.Nil(t, err)
+	require.Equal(t, []string{"old note", "updated note", "🆕 new note"}, doc.Uncategorized)
+}
+
+func TestSplitByKind(t *testing.T) {
+	notes := ReleaseNoteList{
+		1: &ReleaseNote{PrNumber: 1, Kinds: []string{"feature"}},
+		2: &ReleaseNote{PrNumber: 2, Kinds: []string{"bug", "api-change"}},
+		3: &ReleaseNote{PrNumber: 3, Kinds: []string{"design"}},
+		4: &ReleaseNote{PrNumber: 4},
+	}
+
+	split := SplitByKind(notes)
+	require.Len(t, split, len(KindFiles)+1)
+	require.Equal(t, ReleaseNoteList{1: notes[1]}, split["features.md"])
+	require.Equal(t, ReleaseNoteList{2: notes[2]}, split["bug-fixes.md"])
+	require.Equal(t, ReleaseNoteList{2: notes[2]}, split["api-changes.md"])
+	require.Equal(t, ReleaseNoteList{3: notes[3], 4: notes[4]}, split[OtherKindFile])
+	require.Empty(t, split["deprecations.md"])
+}
+
+func TestCreateDocumentWithKindPriority(t *testing.T) {
+	notes := ReleaseNoteList{
+		1: &ReleaseNote{PrNumber: 1, Markdown: "no kind", SIGs: []string{"node"}},
+		2: &ReleaseNote{PrNumber: 2, Markdown: "bug", SIGs: []string{"node"}, Kinds: []string{"bug"}},
+		3: &ReleaseNote{PrNumber: 3, Markdown: "cleanup", SIGs: []string{"node"}, Kinds: []string{"cleanup"}},
+		4: &ReleaseNote{PrNumber: 4, Markdown: "feature", SIGs: []string{"node"}, Kinds: []string{"feature"}},
+		5: &ReleaseNote{PrNumber: 5, Markdown: "another bug", SIGs: []string{"node"}, Kinds: []string{"bug"}},
+	}
+
+	doc, err := CreateDocument(notes)
+	require.Nil(t, err)
+	require.Equal(t, []string{"no kind", "bug", "cleanup", "feature", "another bug"}, doc.SIGs["node"])
+
+	doc, err = CreateDocument(notes, WithKindPriority([]string{"feature", "bug"}))
+	require.Nil(t, err)
+	require.Equal(t, []string{"feature", "bug", "another bug", "cleanup", "no kind"}, doc.SIGs["node"])
+}
+
+func TestSecurityNotes(t *testing.T) {
+	notes := ReleaseNoteList{
+		1: &ReleaseNote{PrNumber: 1, Markdown: "a fix of a CVE", SIGs: []string{"auth", "node"}, IsSecurity: true},
+		2: &ReleaseNote{PrNumber: 2, Markdown: "an action", SIGs: []string{"node"}, ActionRequired: true},
+		3: &ReleaseNote{PrNumber: 3, Markdown: "a secure action", SIGs: []string{"auth"}, ActionRequired: true, IsSecurity: true},
+		4: &ReleaseNote{PrNumber: 4, Markdown: "a note", SIGs: []string{"auth"}},
+	}
+
+	doc, err := CreateDocument(notes)
+	require.Nil(t, err)
+	require.Equal(t, []string{"a fix of a CVE", "a secure action"}, doc.Security)
+	require.Equal(t, []string{"an action"}, doc.ActionRequired)
+	require.Empty(t, doc.Duplicates)
+	require.Equal(t, map[string][]string{"auth": {"a note"}}, doc.SIGs)
+
+	rendered := &bytes.Buffer{}
+	require.Nil(t, RenderMarkdown(doc, rendered))
+	require.True(t, strings.HasPrefix(rendered.String(), "## Security\n\n- a fix of a CVE\n- a secure action\n\n\n## Action Required\n\n"))
+
+	streamed := &bytes.Buffer{}
+	require.Nil(t, RenderMarkdownStream(notes, streamed))
+	require.Equal(t, rendered.String(), streamed.String())
+
+	doc, err = CreateDocument(notes, WithLayout(LayoutKubernetes))
+	require.Nil(t, err)
+	require.Equal(t, []string{"a fix of a CVE", "a secure action"}, doc.Security)
+	require.Equal(t, []string{"an action"}, doc.ActionRequired)
+}
+
+func TestAnchorFor(t *testing.T) {
+	cases := map[string]string{
+		"sig-node":                "sig-node",
+		"API Changes":             "api-changes",
+		"sig-api-machinery":       "sig-api-machinery",
+		"duplicates-SIG A, SIG B": "duplicates-sig-a-sig-b",
+		"--kind/bug--":            "kind-bug",
+	}
+
+	for input, expected := range cases {
+		require.Equal(t, expected, AnchorFor(input))
+	}
+}
+
+func TestStableAnchors(t *testing.T) {
+	notes := ReleaseNoteList{
+		1: &ReleaseNote{PrNumber: 1, Markdown: "a note", SIGs: []string{"api-machinery"}},
+		2: &ReleaseNote{PrNumber: 2, Markdown: "a bug fix", Kinds: []string{"bug"}},
+	}
+
+	render := func() string {
+		buf := &bytes.Buffer{}
+		doc, err := CreateDocument(notes)
+		require.Nil(t, err)
+		require.Nil(t, RenderMarkdown(doc, buf, WithStableAnchors()))
+		return buf.String()
+	}
+
+	rendered := render()
+	require.Contains(t, rendered, "<a id=\"sigs\"></a>\n## Notes from Individual SIGs\n\n")
+	require.Contains(t, rendered, "<a id=\"sig-api-machinery\"></a>\n### SIG API Machinery\n\n")
+	require.Contains(t, rendered, "<a id=\"bug-fixes\"></a>\n## Bug Fixes\n\n")
+
+	streamed := &bytes.Buffer{}
+	require.Nil(t, RenderMarkdownStream(notes, streamed, WithStableAnchors()))
+	require.Equal(t, rendered, streamed.String())
+
+	// the anchors must not change with the wording of the headings
+	anchors := regexp.MustCompile(`<a id="[^"]*"></a>`)
+	original := sectionTitles[sectionSIGs]
+	sectionTitles[sectionSIGs] = "Changes by SIG"
+	defer func() { sectionTitles[sectionSIGs] = original }()
+	require.Equal(t, anchors.FindAllString(rendered, -1), anchors.FindAllString(render(), -1))
+}
+
+func TestOtherSectionTitle(t *testing.T) {
+	notes := ReleaseNoteList{
+		1: &ReleaseNote{PrNumber: 1, Text: "a bug fix", Markdown: "a bug fix", Kinds: []string{"bug"}},
+		2: &ReleaseNote{PrNumber: 2, Text: "a change", Markdown: "a change", Kinds: []string{"design"}},
+		3: &ReleaseNote{PrNumber: 3, Text: "a node change", Markdown: "a node change", SIGs: []string{"node"}},
+	}
+	opts := []DocumentOption{WithOtherSectionTitle("Misc")}
+
+	// only the note without a SIG or kind section is listed last
+	rendered := &bytes.Buffer{}
+	doc, err := CreateDocument(notes, opts...)
+	require.Nil(t, err)
+	require.Equal(t, []string{"a change"}, doc.Uncategorized)
+	require.Nil(t, RenderMarkdown(doc, rendered, opts...))
+	require.Contains(t, rendered.String(), "## Misc\n\n- a change\n")
+	require.Greater(t, strings.Index(rendered.String(), "## Misc"), strings.Index(rendered.String(), "## Bug Fixes"))
+	require.Greater(t, strings.Index(rendered.String(), "## Misc"), strings.Index(rendered.String(), "### SIG Node"))
+	require.NotContains(t, rendered.String(), "Other Notable Changes")
+
+	streamed := &bytes.Buffer{}
+	require.Nil(t, RenderMarkdownStream(notes, streamed, opts...))
+	require.Equal(t, rendered.String(), streamed.String())
+
+	html := &bytes.Buffer{}
+	require.Nil(t, RenderHTML(notes, html, opts...))
+	require.Contains(t, html.String(), ">Misc</h2>")
+
+	// the kind sections only leave notes without a known kind
+	opts = append(opts, WithLayout(LayoutKubernetes))
+	doc, err = CreateDocument(notes, opts...)
+	require.Nil(t, err)
+	require.Equal(t, map[string][]string{
+		"Bug or Regression": {"a bug fix"},
+		"Design":            {"a change"},
+		"Misc":              {"a node change"},
+	}, doc.Kinds)
+	rendered.Reset()
+	require.Nil(t, RenderMarkdown(doc, rendered, opts...))
+	require.Contains(t, rendered.String(), "### Misc\n\n- a node change\n")
+	require.Greater(t, strings.Index(rendered.String(), "### Misc"), strings.Index(rendered.String(), "### Design"))
+}
+
+func TestNoteTemplate(t *testing.T) {
+	notes := ReleaseNoteList{
+		1: &ReleaseNote{PrNumber: 1, Text: "a note", Author: "alice", Markdown: "a note", SIGs: []string{"node"}, Kinds: []string{"feature"}},
+		2: &ReleaseNote{PrNumber: 2, Text: "a bug fix", Author: "bob", Markdown: "a bug fix", Kinds: []string{"bug"}, New: true},
+	}
+
+	tmpl, err := ParseNoteTemplate("{{.Text}} (#{{.Number}}, @{{.Author}}, {{.Kind}})")
+	require.Nil(t, err)
+
+	doc, err := CreateDocument(notes, WithNoteTemplate(tmpl))
+	require.Nil(t, err)
+	require.Equal(t, []string{"a note (#1, @alice, feature)"}, doc.SIGs["node"])
+	require.Equal(t, []string{newNoteMarker + "a bug fix (#2, @bob, bug)"}, doc.BugFixes)
+
+	rendered := &bytes.Buffer{}
+	require.Nil(t, RenderMarkdown(doc, rendered))
+	streamed := &bytes.Buffer{}
+	require.Nil(t, RenderMarkdownStream(notes, streamed, WithNoteTemplate(tmpl)))
+	require.Equal(t, rendered.String(), streamed.String())
+
+	_, err = ParseNoteTemplate("{{.Text")
+	require.NotNil(t, err)
+	_, err = ParseNoteTemplate("{{.Title}}")
+	require.NotNil(t, err)
+}
+
+// syntheticNotes generates a list of notes which spreads across all sections
+// of a document
+func syntheticNotes(count int) ReleaseNoteList {
+	sigs := []string{"api-machinery", "cli", "node", "scheduling", "storage"}
+	notes := ReleaseNoteList{}
+	for i := 1; i <= count; i++ {
+		note := &ReleaseNote{
+			PrNumber: i,
+			Markdown: fmt.Sprintf("Note number %d ([#%d](https://github.com/kubernetes/kubernetes/pull/%d), [@author](https://github.com/author))", i, i, i),
+			SIGs:     []string{sigs[i%len(sigs)]},
+		}
+		switch i % 7 {
+		case 0:
+			note.ActionRequired = true
+		case 1:
+			note.Feature = true
+		case 2:
+			note.Duplicate = true
+			note.SIGs = append(note.SIGs, sigs[(i+1)%len(sigs)])
+		case 3:
+			note.Kinds = []string{"api-change"}
+		case 4:
+			note.Kinds = []string{"bug"}
+			note.SIGs = nil
+		case 5:
+			note.SIGs = nil
+		}
+		notes[i] = note
+	}
+	return notes
+}
+
+func TestRenderMarkdownStream(t *testing.T) {
+	notes := syntheticNotes(50)
+
+	doc, err := CreateDocument(notes)
+	require.NoError(t, err)
+	expected := &bytes.Buffer{}
+	require.NoError(t, RenderMarkdown(doc, expected))
+
+	actual := &bytes.Buffer{}
+	require.NoError(t, RenderMarkdownStream(notes, actual))
+
+	require.Equal(t, expected.String(), actual.String())
+}
+
+func BenchmarkRenderMarkdown(b *testing.B) {
+	notes := syntheticNotes(5000)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		doc, err := CreateDocument(notes)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := RenderMarkdown(doc, ioutil.Discard); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRenderMarkdownStream(b *testing.B) {
+	notes := syntheticNotes(5000)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := RenderMarkdownStream(notes, ioutil.Discard); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package notes
+
+import (
+	"net/http"
+
+	"github.com/google/go-github/v27/github"
+	"github.com/pkg/errors"
+)
+
+// The causes of the errors returned by ListReleaseNotes and the other
+// functions which call the GitHub API. They can be checked with ErrorCause,
+// or with errors.Is from Go 1.13 on.
+var (
+	// ErrRateLimited is the cause if the primary or secondary rate limit of
+	// the GitHub API was exceeded
+	ErrRateLimited = errors.New("GitHub API rate limit exceeded")
+
+	// ErrAuth is the cause if the GitHub API rejected the token
+	ErrAuth = errors.New("GitHub API authentication failed")
+
+	// ErrBranchNotFound is the cause if the branch does not exist
+	ErrBranchNotFound = errors.New("branch not found")
+
+	// ErrInvalidRange is the cause if a commit of the range does not exist
+	// or the range is not part of the branch
+	ErrInvalidRange = errors.New("invalid commit range")
+
+	// ErrRangeTooLarge is the cause if the range has more commits than the
+	// maximum set with WithMaxRangeCommits
+	ErrRangeTooLarge = errors.New("commit range too large")
+)
+
+// Error is an error whose cause is one of the sentinel errors of this
+// package. The message is the one of the underlying error, while errors.Is of
+// Go 1.13 matches the cause and errors.As the underlying error, like a
+// *github.RateLimitError.
+type Error struct {
+	// Cause is one of the sentinel errors, like ErrRateLimited
+	Cause error
+
+	// Err is the underlying error
+	Err error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is the cause of the error
+func (e *Error) Is(target error) bool {
+	return target == e.Cause
+}
+
+// ErrorCause returns the sentinel error which caused err, like ErrRateLimited,
+// or nil if the cause is unknown. The *Error is found below the errors wrapped
+// with github.com/pkg/errors.
+func ErrorCause(err error) error {
+	if e, ok := errors.Cause(err).(*Error); ok {
+		return e.Cause
+	}
+	return nil
+}
+
+// classifyError returns err as an *Error if its cause is known. Errors of the
+// GitHub API with the status code 404 are caused by notFound, if set. The
+// underlying error is found with errors.Cause, as github.com/pkg/errors does
+// not support errors.Unwrap.
+func classifyError(err error, notFound error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*Error); ok {
+		return err
+	}
+
+	switch cause := errors.Cause(err).(type) {
+	case *Error:
+		return &Error{Cause: cause.Cause, Err: err}
+	case *github.RateLimitError, *github.AbuseRateLimitError:
+		return &Error{Cause: ErrRateLimited, Err: err}
+	case *github.ErrorResponse:
+		if cause.Response == nil {
+			break
+		}
+		switch cause.Response.StatusCode {
+		case http.StatusUnauthorized:
+			return &Error{Cause: ErrAuth, Err: err}
+		case http.StatusNotFound:
+			if notFound != nil {
+				return &Error{Cause: notFound, Err: err}
+			}
+		}
+	}
+	return err
+}
@@ -0,0 +1,88 @@
+package notes
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorCauses(t *testing.T) {
+	testCases := []struct {
+		name    string
+		handler http.HandlerFunc
+		cause   error
+		message string
+	}{
+		{
+			name: "rate limited",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-RateLimit-Remaining", "0")
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `{"message": "API rate limit exceeded for 127.0.0.1."}`)
+			},
+			cause: ErrRateLimited,
+		},
+		{
+			name: "bad credentials",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"message": "Bad credentials"}`)
+			},
+			cause: ErrAuth,
+		},
+		{
+			name: "unknown start commit",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"message": "Not Found"}`)
+			},
+			cause:   ErrInvalidRange,
+			message: "error getting the start commit start",
+		},
+		{
+			name: "unknown branch",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/repos/kubernetes/kubernetes/commits" {
+					w.WriteHeader(http.StatusNotFound)
+					fmt.Fprint(w, `{"message": "Not Found"}`)
+					return
+				}
+				fmt.Fprint(w, `{"committer": {"date": "2019-01-01T00:00:00Z"}}`)
+			},
+			cause:   ErrBranchNotFound,
+			message: "error listing the commits of branch missing",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client, server := newTestClient(t, tc.handler)
+			defer server.Close()
+
+			_, err := ListReleaseNotes(client, log.NewNopLogger(), "missing", "start", "end", "", "")
+			require.NotNil(t, err)
+			require.Equal(t, tc.cause, ErrorCause(err), "unexpected cause of %v", err)
+			require.Contains(t, err.Error(), tc.message)
+
+			apiErr, ok := errors.Cause(err).(*Error)
+			require.True(t, ok)
+			require.Equal(t, tc.cause, apiErr.Cause)
+		})
+	}
+}
+
+func TestVerifyRangeOnBranchCause(t *testing.T) {
+	client, server := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status": "diverged"}`)
+	}))
+	defer server.Close()
+
+	err := VerifyRangeOnBranch(client, "master", "start", "end")
+	require.Equal(t, ErrInvalidRange, ErrorCause(err))
+	require.NotEqual(t, ErrBranchNotFound, ErrorCause(err))
+	require.EqualError(t, err, "the start commit start is not reachable from the tip of branch master")
+}
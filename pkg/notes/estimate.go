@@ -0,0 +1,126 @@
+package notes
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/google/go-github/v27/github"
+	"github.com/pkg/errors"
+)
+
+// Estimate is the estimated API cost of listing the release notes of a range
+type Estimate struct {
+	// Commits is the number of commits in the range
+	Commits int
+
+	// Requests is the estimated number of API requests. The requests made per
+	// note are counted for every commit, so it is an upper bound of them.
+	Requests int
+
+	// Remaining is the number of requests left in the current rate limit
+	// window, and Reset is the time the window resets
+	Remaining int
+	Reset     time.Time
+}
+
+// Sufficient returns true if the remaining rate limit covers the estimated
+// requests
+func (e *Estimate) Sufficient() bool {
+	return e.Requests <= e.Remaining
+}
+
+// EstimateRequests estimates the API requests ListReleaseNotes makes for the
+// range from start to end with the same options, without fetching any PR. The
+// commits are counted with a single comparison, and the share of commits whose
+// PR number is not in their message, which costs an additional lookup, is
+// taken from the commits returned by it.
+func EstimateRequests(client *github.Client, start, end string, opts ...GithubApiOption) (*Estimate, error) {
+	c := configFromOpts(opts...)
+
+	comparison, _, err := client.Repositories.CompareCommits(c.ctx, c.org, c.repo, start, end)
+	if err != nil {
+		return nil, classifyError(errors.Wrapf(err, "error comparing %s...%s", start, end), ErrInvalidRange)
+	}
+	commits := comparison.GetTotalCommits()
+
+	lookups := 0
+	if sampled := len(comparison.Commits); sampled > 0 {
+		unmatched := 0
+		for _, commit := range comparison.Commits {
+			if !hasPRNumber(commit.GetCommit().GetMessage(), c.prNumberRegex) {
+				unmatched++
+			}
+		}
+		// rounded up, a single unmatched commit costs a request
+		lookups = (commits*unmatched + sampled - 1) / sampled
+	}
+
+	// the start and end commits are fetched before the commits are listed
+	requests := 2 + (commits+99)/100
+	switch c.noteSource {
+	case NoteSourceConventional, NoteSourceCommitBody:
+		requests += lookups
+		if c.fetchStats {
+			requests += budgeted(commits, c.statsBudget)
+		}
+	default:
+		// the PR is fetched once to filter the commits and once for the note
+		requests += 2 * (commits + lookups)
+	}
+	requests += commits * len(c.trackBranches)
+	if len(c.sigOwners) > 0 {
+		requests += budgeted(commits, c.sigBudget)
+	}
+	if c.fetchReactions {
+		requests += budgeted(commits, c.reactionsBudget)
+	}
+	if c.excludeDocs && len(c.docsPaths) > 0 {
+		requests += budgeted(commits, c.docsBudget)
+	}
+	if len(c.pathFilter) > 0 {
+		requests += budgeted(commits, c.pathBudget)
+	}
+
+	// requesting the rate limit does not count against it
+	limits, _, err := client.RateLimits(c.ctx)
+	if err != nil {
+		return nil, classifyError(errors.Wrap(err, "error getting the rate limit"), nil)
+	}
+	core := limits.GetCore()
+	if core == nil {
+		return nil, errors.New("the rate limit of the core API is unknown")
+	}
+
+	return &Estimate{
+		Commits:   commits,
+		Requests:  requests,
+		Remaining: core.Remaining,
+		Reset:     core.Reset.Time,
+	}, nil
+}
+
+// hasPRNumber returns true if the PR number can be parsed from the commit
+// message, without looking up the PRs of the commit
+func hasPRNumber(message string, exp *regexp.Regexp) bool {
+	var err error
+	if exp != nil {
+		_, err = getPRNumberFromCommitMessageWithRegex(message, exp)
+	} else {
+		_, err = getPRNumberFromCommitMessage(message)
+	}
+	return err == nil
+}
+
+// budgeted returns the number of requests of n PRs which are limited by the
+// budget, if set
+func budgeted(n int, budget *inferenceBudget) int {
+	if budget == nil {
+		return n
+	}
+	budget.mu.Lock()
+	defer budget.mu.Unlock()
+	if budget.remaining < n {
+		return budget.remaining
+	}
+	return n
+}
@@ -0,0 +1,51 @@
+package notes
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateRequests(t *testing.T) {
+	client, server := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/kubernetes/kubernetes/compare/v1.16.0...v1.17.0":
+			// one of the three returned commits has no PR number in its message
+			fmt.Fprint(w, `{"total_commits": 300, "commits": [
+				{"sha": "a", "commit": {"message": "Merge pull request #1 from a/b"}},
+				{"sha": "b", "commit": {"message": "Fix the tests (#2)"}},
+				{"sha": "c", "commit": {"message": "Update the changelog"}}
+			]}`)
+		case "/rate_limit":
+			fmt.Fprint(w, `{"resources": {"core": {"limit": 5000, "remaining": 1000, "reset": 1571184000}}}`)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	estimate := func(opts ...GithubApiOption) *Estimate {
+		opts = append(opts, WithOrg("kubernetes"), WithRepo("kubernetes"))
+		result, err := EstimateRequests(client, "v1.16.0", "v1.17.0", opts...)
+		require.Nil(t, err)
+		return result
+	}
+
+	// 2 commits, 3 pages of commits and every PR fetched twice, a third of
+	// them after looking up the PRs of the commit
+	result := estimate()
+	require.Equal(t, 300, result.Commits)
+	require.Equal(t, 805, result.Requests)
+	require.Equal(t, 1000, result.Remaining)
+	require.Equal(t, int64(1571184000), result.Reset.Unix())
+	require.True(t, result.Sufficient())
+
+	result = estimate(WithTrackBranches([]string{"release-1.17"}), WithReactions(50))
+	require.Equal(t, 1155, result.Requests)
+	require.False(t, result.Sufficient())
+
+	// the commit message sources only look up the PR numbers
+	result = estimate(WithNoteSource(NoteSourceConventional))
+	require.Equal(t, 105, result.Requests)
+}
@@ -0,0 +1,67 @@
+package notes
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v27/github"
+	"github.com/stretchr/testify/require"
+)
+
+// requireMergedFixtures are the responses of testdata/require-merged, whose
+// commits belong to merged and to unmerged PRs
+var requireMergedFixtures = map[string]string{
+	"/repos/kubernetes/kubernetes/commits":           "commits.json",
+	"/repos/kubernetes/kubernetes/commits/aaa/pulls": "commit-aaa-pulls.json",
+	"/repos/kubernetes/kubernetes/commits/bbb/pulls": "commit-bbb-pulls.json",
+	"/repos/kubernetes/kubernetes/pulls/1":           "pull-1.json",
+	"/repos/kubernetes/kubernetes/pulls/2":           "pull-2.json",
+	"/repos/kubernetes/kubernetes/pulls/3":           "pull-3.json",
+}
+
+// newTestClient returns a client of the GitHub API of a test server with the
+// handler. The server must be closed by the caller.
+func newTestClient(t *testing.T, handler http.Handler) (*github.Client, *httptest.Server) {
+	server := httptest.NewServer(handler)
+
+	client := github.NewClient(nil)
+	baseURL, err := url.Parse(server.URL + "/")
+	require.Nil(t, err)
+	client.BaseURL = baseURL
+	return client, server
+}
+
+// fixtureServer is the test server of newFixtureClient, which counts the
+// requests it received
+type fixtureServer struct {
+	*httptest.Server
+	requests int
+}
+
+// newFixtureClient returns a client of a test server which serves the files of
+// testdata/<dir> at the URL paths of fixtures. The commits of the git data API
+// are served with a fixed committer date, all other paths are not found. The
+// server must be closed by the caller.
+func newFixtureClient(t *testing.T, dir string, fixtures map[string]string) (*github.Client, *fixtureServer) {
+	fixture := &fixtureServer{}
+	client, server := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fixture.requests++
+		if strings.Contains(r.URL.Path, "/git/commits/") {
+			fmt.Fprint(w, `{"committer": {"date": "2019-01-01T00:00:00Z"}}`)
+			return
+		}
+		file, ok := fixtures[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeFile(w, r, filepath.Join("testdata", dir, file))
+	}))
+	fixture.Server = server
+	return client, fixture
+}
@@ -0,0 +1,73 @@
+package notes
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// MarkdownFlavor is the markdown dialect the documents are rendered in
+type MarkdownFlavor string
+
+const (
+	// FlavorGFM is GitHub Flavored Markdown, the default
+	FlavorGFM MarkdownFlavor = "gfm"
+
+	// FlavorCommonMark is strict CommonMark, which has no extended
+	// autolinks and no heading ids of its own
+	FlavorCommonMark MarkdownFlavor = "commonmark"
+)
+
+// WithMarkdownFlavor allows the caller to render the markdown for a specific
+// dialect. For FlavorCommonMark, bare URLs within the notes are rendered as
+// autolinks, and the stable anchors only consist of lowercase ASCII letters,
+// digits and dashes. For FlavorGFM, the stable anchors follow the heading id
+// rules of GitHub.
+func WithMarkdownFlavor(flavor MarkdownFlavor) DocumentOption {
+	return func(c *documentConfig) {
+		c.flavor = flavor
+	}
+}
+
+// anchorForFlavor returns the anchor of the section with the provided
+// canonical key according to the rules of the flavor
+func anchorForFlavor(section string, flavor MarkdownFlavor) string {
+	if flavor == FlavorCommonMark {
+		return AnchorFor(section)
+	}
+	return GitHubAnchor(section)
+}
+
+// GitHubAnchor returns the id GitHub generates for a heading with the
+// provided text, like "sig-api-machinery" for "SIG API Machinery". The text
+// is lowercased, punctuation besides dashes and underscores is dropped and
+// every space becomes a dash.
+func GitHubAnchor(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), r == '-', r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
+
+// markdownLinkOrURL matches inline links, autolinks and bare URLs, so that
+// only the latter are converted by commonMarkAutolinks
+var markdownLinkOrURL = regexp.MustCompile(`\]\([^)]*\)|<[^>\s]*>|https?://[^\s<>]+`)
+
+// commonMarkAutolinks wraps the bare URLs of the markdown into angle
+// brackets, because CommonMark only links URLs in autolink syntax. Trailing
+// punctuation is not considered part of the URL, like on GitHub.
+func commonMarkAutolinks(markdown string) string {
+	return markdownLinkOrURL.ReplaceAllStringFunc(markdown, func(match string) string {
+		if !strings.HasPrefix(match, "http") {
+			return match
+		}
+		url := strings.TrimRight(match, ".,:;!?*_~'\")")
+		return "<" + url + ">" + strings.TrimPrefix(match, url)
+	})
+}
@@ -0,0 +1,58 @@
+package notes
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubAnchor(t *testing.T) {
+	for text, anchor := range map[string]string{
+		"SIG API Machinery":     "sig-api-machinery",
+		"sig-cluster_lifecycle": "sig-cluster_lifecycle",
+		"Bug Fixes (1.16)":      "bug-fixes-116",
+		"Neue Funktionen – Ü":   "neue-funktionen--ü",
+	} {
+		require.Equal(t, anchor, GitHubAnchor(text), text)
+	}
+}
+
+func TestCommonMarkAutolinks(t *testing.T) {
+	for markdown, expected := range map[string]string{
+		"See https://k8s.io/docs.": "See <https://k8s.io/docs>.",
+		"([#1](https://github.com/k/k/pull/1), [@a](https://github.com/a))": "([#1](https://github.com/k/k/pull/1), [@a](https://github.com/a))",
+		"Already <https://k8s.io> linked":                                   "Already <https://k8s.io> linked",
+		"No links":                                                          "No links",
+	} {
+		require.Equal(t, expected, commonMarkAutolinks(markdown), markdown)
+	}
+}
+
+func TestMarkdownFlavorFixtures(t *testing.T) {
+	notes := ReleaseNoteList{
+		1: {
+			Text:     "Add a flag, see https://k8s.io/docs/flag for details.",
+			Markdown: "Add a flag, see https://k8s.io/docs/flag for details. ([#1](https://github.com/kubernetes/kubernetes/pull/1), [@alice](https://github.com/alice))",
+			PrNumber: 1,
+			Kinds:    []string{"feature"},
+			Feature:  true,
+		},
+		2: {
+			Text:     "Fix the cluster lifecycle controller",
+			Markdown: "Fix the cluster lifecycle controller ([#2](https://github.com/kubernetes/kubernetes/pull/2), [@bob](https://github.com/bob))",
+			PrNumber: 2,
+			SIGs:     []string{"cluster_lifecycle"},
+		},
+	}
+
+	for _, flavor := range []MarkdownFlavor{FlavorGFM, FlavorCommonMark} {
+		expected, err := ioutil.ReadFile(filepath.Join("testdata", "flavor-"+string(flavor)+".md"))
+		require.Nil(t, err)
+
+		content, err := RenderToBytes(notes, "markdown", WithStableAnchors(), WithMarkdownFlavor(flavor))
+		require.Nil(t, err)
+		require.Equal(t, string(expected), string(content), flavor)
+	}
+}
@@ -0,0 +1,186 @@
+package notes
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// Formats are the output formats of the release notes. All of them but hugo,
+// which renders a file per note, are rendered by Render.
+var Formats = []string{"markdown", "json", "html", "docbook", "hugo", "contributors", "index-json"}
+
+// formatsConfig holds the options of the formats which are rendered by Render,
+// in addition to the document
+type formatsConfig struct {
+	stream             bool
+	badgeStyle         BadgeStyle
+	badgeColors        map[string]string
+	topReacted         int
+	contributors       bool
+	contributorsFormat string
+	firstTimers        []string
+	indexMarkdown      string
+}
+
+// WithStream allows the caller to render the markdown format with
+// RenderMarkdownStream, which writes the sections while it iterates the notes
+// instead of building the whole document first
+func WithStream() DocumentOption {
+	return func(c *documentConfig) {
+		c.formats.stream = true
+	}
+}
+
+// WithBadges allows the caller to prepend badges with the note counts of every
+// kind to the markdown format, like RenderBadgesMarkdown
+func WithBadges(style BadgeStyle, colors map[string]string) DocumentOption {
+	return func(c *documentConfig) {
+		c.formats.badgeStyle = style
+		c.formats.badgeColors = colors
+	}
+}
+
+// WithTopReacted allows the caller to prepend a section with the n most
+// reacted notes to the markdown format, like RenderTopReactedMarkdown
+func WithTopReacted(n int) DocumentOption {
+	return func(c *documentConfig) {
+		c.formats.topReacted = n
+	}
+}
+
+// WithContributorsSection allows the caller to append a section listing the
+// contributors of the notes to the markdown format, like
+// RenderContributorsMarkdown
+func WithContributorsSection() DocumentOption {
+	return func(c *documentConfig) {
+		c.formats.contributors = true
+	}
+}
+
+// WithContributorsFormat allows the caller to render the contributors format
+// as "json" instead of "markdown"
+func WithContributorsFormat(format string) DocumentOption {
+	return func(c *documentConfig) {
+		c.formats.contributorsFormat = format
+	}
+}
+
+// WithFirstTimeContributors allows the caller to flag the contributors with the
+// given logins as first-time contributors in the contributors format. The
+// logins can be found with FlagFirstTimeContributors.
+func WithFirstTimeContributors(logins []string) DocumentOption {
+	return func(c *documentConfig) {
+		c.formats.firstTimers = logins
+	}
+}
+
+// WithIndexMarkdown allows the caller to record the path of the markdown file
+// which is indexed by the index-json format
+func WithIndexMarkdown(path string) DocumentOption {
+	return func(c *documentConfig) {
+		c.formats.indexMarkdown = path
+	}
+}
+
+// Render writes the list of release notes to the supplied io.Writer in the
+// provided format, which is one of "json", "markdown", "html", "docbook",
+// "contributors" or "index-json". The hugo format renders a file per note,
+// which is done with RenderHugoNote and RenderHugoIndex instead.
+func Render(notes ReleaseNoteList, format string, w io.Writer, opts ...DocumentOption) error {
+	c := documentConfigFromOpts(opts...)
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(notes); err != nil {
+			return errors.Wrap(err, "error encoding JSON output")
+		}
+	case "markdown":
+		return renderMarkdownFormat(notes, w, c, opts...)
+	case "html":
+		if err := RenderHTML(notes, w, opts...); err != nil {
+			return errors.Wrap(err, "error rendering release notes to HTML")
+		}
+	case "docbook":
+		doc, err := CreateDocument(notes, opts...)
+		if err != nil {
+			return errors.Wrap(err, "error creating release note document")
+		}
+		if err := RenderDocBook(doc, w, opts...); err != nil {
+			return errors.Wrap(err, "error rendering release note document to DocBook")
+		}
+	case "contributors":
+		contributors := Contributors(notes)
+		for _, contributor := range contributors {
+			contributor.FirstTime = contributor.Login != "" && HasString(c.formats.firstTimers, contributor.Login)
+		}
+		format := c.formats.contributorsFormat
+		if format == "" {
+			format = "markdown"
+		}
+		if err := RenderContributorsReport(contributors, format, w); err != nil {
+			return errors.Wrap(err, "error rendering the contributors report")
+		}
+	case "index-json":
+		content, err := RenderToBytes(notes, "markdown", opts...)
+		if err != nil {
+			return err
+		}
+		index := IndexMarkdown(content, c.flavor)
+		index.Markdown = c.formats.indexMarkdown
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(index); err != nil {
+			return errors.Wrap(err, "error encoding the section index")
+		}
+	case "hugo":
+		return errors.New("the hugo format renders a file per note, use RenderHugoNote and RenderHugoIndex")
+	default:
+		return errors.Errorf("%q is an unsupported format", format)
+	}
+	return nil
+}
+
+// renderMarkdownFormat writes the markdown format with the badges, the most
+// reacted notes and the contributors, if requested
+func renderMarkdownFormat(notes ReleaseNoteList, w io.Writer, c *documentConfig, opts ...DocumentOption) error {
+	if c.formats.badgeStyle != "" {
+		if err := RenderBadgesMarkdown(Summarize(notes), c.formats.badgeStyle, c.formats.badgeColors, w); err != nil {
+			return errors.Wrap(err, "error rendering badges to markdown")
+		}
+	}
+	if c.formats.topReacted > 0 {
+		if err := RenderTopReactedMarkdown(notes, c.formats.topReacted, w, opts...); err != nil {
+			return errors.Wrap(err, "error rendering the most reacted notes to markdown")
+		}
+	}
+
+	switch {
+	case c.formats.stream:
+		if err := RenderMarkdownStream(notes, w, opts...); err != nil {
+			return errors.Wrap(err, "error streaming release notes to markdown")
+		}
+	case c.patchBuckets:
+		if err := renderPatchBuckets(notes, w, opts...); err != nil {
+			return errors.Wrap(err, "error rendering release notes to markdown by release")
+		}
+	default:
+		doc, err := CreateDocument(notes, opts...)
+		if err != nil {
+			return errors.Wrap(err, "error creating release note document")
+		}
+		if err := RenderMarkdown(doc, w, opts...); err != nil {
+			return errors.Wrap(err, "error rendering release note document to markdown")
+		}
+	}
+
+	if c.formats.contributors {
+		if err := RenderContributorsMarkdown(Contributors(notes), w); err != nil {
+			return errors.Wrap(err, "error rendering contributors to markdown")
+		}
+	}
+	return nil
+}
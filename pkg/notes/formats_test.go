@@ -0,0 +1,54 @@
+package notes
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderFormats(t *testing.T) {
+	notes := syntheticNotes(20)
+	notes[1].Author = "jdoe"
+	notes[2].Author = "alice"
+
+	for _, format := range Formats {
+		content, err := RenderToBytes(notes, format)
+		if format == "hugo" {
+			require.NotNil(t, err)
+			require.Contains(t, err.Error(), "RenderHugoNote")
+			continue
+		}
+		require.Nil(t, err, format)
+		require.NotEmpty(t, content, format)
+	}
+
+	// the options of the formats
+	content, err := RenderToBytes(notes, "contributors", WithContributorsFormat("json"), WithFirstTimeContributors([]string{"alice"}))
+	require.Nil(t, err)
+	contributors := []*Contributor{}
+	require.Nil(t, json.Unmarshal(content, &contributors))
+	require.Len(t, contributors, 2)
+	require.Equal(t, "alice", contributors[0].Login)
+	require.True(t, contributors[0].FirstTime)
+	require.False(t, contributors[1].FirstTime)
+
+	content, err = RenderToBytes(notes, "index-json", WithIndexMarkdown("notes.md"))
+	require.Nil(t, err)
+	index := &DocumentIndex{}
+	require.Nil(t, json.Unmarshal(content, index))
+	require.Equal(t, "notes.md", index.Markdown)
+	require.NotEmpty(t, index.Sections)
+
+	markdown, err := RenderToBytes(notes, "markdown")
+	require.Nil(t, err)
+	streamed, err := RenderToBytes(notes, "markdown", WithStream())
+	require.Nil(t, err)
+	require.Equal(t, string(markdown), string(streamed))
+
+	decorated, err := RenderToBytes(notes, "markdown", WithBadges(BadgeStyleStatic, nil), WithContributorsSection())
+	require.Nil(t, err)
+	require.True(t, strings.HasPrefix(string(decorated), "Total: 20"), string(decorated))
+	require.Contains(t, string(decorated), string(markdown)+"## Contributors\n\n- [@alice]")
+}
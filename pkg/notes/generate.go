@@ -0,0 +1,86 @@
+package notes
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-github/v27/github"
+	"github.com/pkg/errors"
+)
+
+// GenerateOptions are the inputs of Generate and GenerateReleaseNotes
+type GenerateOptions struct {
+	// Logger logs the progress, nothing is logged if it is nil
+	Logger log.Logger
+
+	// Branch is the branch whose commits from StartSHA to EndSHA are listed
+	Branch   string
+	StartSHA string
+	EndSHA   string
+
+	// RequiredAuthor and ReleaseVersion are passed to ListReleaseNotes
+	RequiredAuthor string
+	ReleaseVersion string
+
+	// VerifyRange checks that the range is part of the branch before the
+	// notes are listed, like VerifyRangeOnBranch
+	VerifyRange bool
+
+	// Filter is called with the listed notes, if set, and may drop or change
+	// them before the document is created
+	Filter func(ReleaseNoteList) error
+
+	// GithubApiOptions are passed to ListReleaseNotes, the context of
+	// Generate takes precedence over the one of WithContext
+	GithubApiOptions []GithubApiOption
+
+	// DocumentOptions are passed to CreateDocument
+	DocumentOptions []DocumentOption
+}
+
+// Generate lists the release notes of the range, filters them and assembles
+// them into a document, which the caller may render in any format. It is
+// GenerateReleaseNotes followed by CreateDocument.
+func Generate(ctx context.Context, client *github.Client, opts GenerateOptions) (*Document, error) {
+	releaseNotes, err := GenerateReleaseNotes(ctx, client, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := CreateDocument(releaseNotes, opts.DocumentOptions...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating release note document")
+	}
+	return doc, nil
+}
+
+// GenerateReleaseNotes lists the release notes of the range and filters them,
+// like Generate, but returns the notes instead of a document, for callers
+// which render them in several formats. Errors with a known cause, like
+// ErrInvalidRange, are returned as an *Error.
+func GenerateReleaseNotes(ctx context.Context, client *github.Client, opts GenerateOptions) (ReleaseNoteList, error) {
+	logger := opts.Logger
+	if logger == nil {
+		logger = log.NewNopLogger()
+	}
+	apiOpts := append(append([]GithubApiOption{}, opts.GithubApiOptions...), WithContext(ctx))
+
+	// ranges taken from another branch silently yield the wrong notes
+	if opts.VerifyRange {
+		if err := VerifyRangeOnBranch(client, opts.Branch, opts.StartSHA, opts.EndSHA, apiOpts...); err != nil {
+			return nil, err
+		}
+	}
+
+	releaseNotes, err := ListReleaseNotes(client, logger, opts.Branch, opts.StartSHA, opts.EndSHA, opts.RequiredAuthor, opts.ReleaseVersion, apiOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Filter != nil {
+		if err := opts.Filter(releaseNotes); err != nil {
+			return nil, err
+		}
+	}
+	return releaseNotes, nil
+}
@@ -0,0 +1,47 @@
+package notes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	client, server := newFixtureClient(t, "require-merged", requireMergedFixtures)
+	defer server.Close()
+
+	opts := GenerateOptions{
+		Branch:   "master",
+		StartSHA: "start",
+		EndSHA:   "end",
+		Filter: func(releaseNotes ReleaseNoteList) error {
+			delete(releaseNotes, 3)
+			return nil
+		},
+		GithubApiOptions: []GithubApiOption{WithRequireMerged()},
+	}
+	doc, err := Generate(context.Background(), client, opts)
+	require.Nil(t, err)
+	require.Len(t, doc.Uncategorized, 1)
+	require.Contains(t, doc.Uncategorized[0], "Fixed the kubelet restart loop")
+
+	// the filter drops the note of the draft, but not the one of the
+	// unmerged PR
+	opts.GithubApiOptions = nil
+	releaseNotes, err := GenerateReleaseNotes(context.Background(), client, opts)
+	require.Nil(t, err)
+	require.Len(t, releaseNotes, 1)
+	require.Equal(t, "Note of the unmerged PR", releaseNotes[1].Text)
+
+	filterErr := errors.New("filter failed")
+	opts.Filter = func(ReleaseNoteList) error { return filterErr }
+	_, err = Generate(context.Background(), client, opts)
+	require.Equal(t, filterErr, err)
+
+	// the range is not part of the branch, as the compare API is not served
+	opts.VerifyRange = true
+	_, err = Generate(context.Background(), client, opts)
+	require.Equal(t, ErrInvalidRange, ErrorCause(err))
+}
@@ -0,0 +1,43 @@
+//go:build ignore
+// +build ignore
+
+// genschema writes schema_generated.go, which holds the content of
+// schema.json for the builds without go:embed. Run it with go generate.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+const header = `// Code generated by genschema.go from schema.json. DO NOT EDIT.
+
+package notes
+
+// jsonSchema is the JSON Schema of the JSON encoded ReleaseNoteList, which is
+// maintained in schema.json
+var jsonSchema = []byte(`
+
+func main() {
+	schema, err := ioutil.ReadFile("schema.json")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if bytes.ContainsRune(schema, '`') {
+		fmt.Fprintln(os.Stderr, "schema.json must not contain backquotes")
+		os.Exit(1)
+	}
+
+	source := &bytes.Buffer{}
+	source.WriteString(header)
+	source.WriteString("`")
+	source.Write(schema)
+	source.WriteString("`)\n")
+	if err := ioutil.WriteFile("schema_generated.go", source.Bytes(), 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
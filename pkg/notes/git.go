@@ -4,31 +4,96 @@ import (
 	"fmt"
 	"io/ioutil"
 
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
 	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/config"
 	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
 )
 
 // RevParse parses a git revision and returns a SHA1 on success, otherwise an
-// error.
-func RevParse(rev, workDir string) (string, error) {
+// error. A revision which cannot be resolved, like a tag which is missing in
+// a shallow clone, is resolved once more after the tags are fetched from the
+// origin remote.
+func RevParse(rev, workDir string, logger log.Logger) (string, error) {
 	repo, err := git.PlainOpen(workDir)
 	if err != nil {
 		return "", err
 	}
 
 	ref, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err == nil {
+		return ref.String(), nil
+	}
+
+	level.Debug(logger).Log("msg", "fetching the tags to resolve the revision", "rev", rev, "err", err)
+	if fetchErr := fetchTags(repo); fetchErr != nil {
+		return "", fmt.Errorf("revision %s cannot be resolved and fetching the tags failed: %v", rev, fetchErr)
+	}
+	ref, err = repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return "", fmt.Errorf("revision %s cannot be resolved, even after fetching the tags: %v", rev, err)
+	}
+	return ref.String(), nil
+}
+
+// fetchTags fetches all tags of the origin remote, which a shallow clone may
+// lack, like "git fetch --tags"
+func fetchTags(repo *git.Repository) error {
+	err := repo.Fetch(&git.FetchOptions{
+		RemoteName: "origin",
+		RefSpecs:   []config.RefSpec{"+refs/tags/*:refs/tags/*"},
+		Tags:       git.AllTags,
+	})
+	if err == git.NoErrAlreadyUpToDate {
+		return nil
+	}
+	return err
+}
+
+// MergeBaseLocal returns the SHA1 of the best common ancestor of two git
+// revisions of the repository in workDir, otherwise an error. Revisions which
+// cannot be resolved are looked up as branches of the origin remote, as a
+// fresh clone only has a local branch for its HEAD.
+func MergeBaseLocal(base, head, workDir string) (string, error) {
+	repo, err := git.PlainOpen(workDir)
 	if err != nil {
 		return "", err
 	}
 
-	return ref.String(), nil
+	commits := []*object.Commit{}
+	for _, rev := range []string{base, head} {
+		hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+		if err != nil {
+			hash, err = repo.ResolveRevision(plumbing.Revision("origin/" + rev))
+		}
+		if err != nil {
+			return "", err
+		}
+		commit, err := repo.CommitObject(*hash)
+		if err != nil {
+			return "", err
+		}
+		commits = append(commits, commit)
+	}
+
+	bases, err := commits[0].MergeBase(commits[1])
+	if err != nil {
+		return "", err
+	}
+	if len(bases) == 0 {
+		return "", fmt.Errorf("%s and %s have no common ancestor", base, head)
+	}
+	return bases[0].Hash.String(), nil
 }
 
-// CloneTempRepository creates a temp directory containing the provided
-// GitHub repository via owner and name. It returns that directory if cloning
+// CloneTempRepository creates a temp directory within tempDir containing the
+// provided GitHub repository via owner and name. An empty tempDir is the
+// default directory for temporary files. It returns that directory if cloning
 // of the repository was successful, otherwise an error.
-func CloneTempRepository(owner, name string) (string, error) {
-	dir, err := ioutil.TempDir("", "release-notes")
+func CloneTempRepository(owner, name, tempDir string) (string, error) {
+	dir, err := ioutil.TempDir(tempDir, "release-notes")
 	if err != nil {
 		return "", err
 	}
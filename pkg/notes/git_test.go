@@ -0,0 +1,58 @@
+package notes
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func TestRevParseFetchesTags(t *testing.T) {
+	dir, err := ioutil.TempDir("", "release-notes-rev-parse")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	origin, err := git.PlainInit(filepath.Join(dir, "origin"), false)
+	require.Nil(t, err)
+	worktree, err := origin.Worktree()
+	require.Nil(t, err)
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "origin", "file"), []byte("first"), 0644))
+	_, err = worktree.Add("file")
+	require.Nil(t, err)
+	hash, err := worktree.Commit("first", &git.CommitOptions{
+		Author: &object.Signature{Name: "a", Email: "a@example.com", When: time.Now()},
+	})
+	require.Nil(t, err)
+
+	clone := filepath.Join(dir, "clone")
+	_, err = git.PlainClone(clone, false, &git.CloneOptions{URL: filepath.Join(dir, "origin")})
+	require.Nil(t, err)
+
+	// the tag is created after the clone, like a tag the clone did not fetch
+	_, err = origin.CreateTag("v1.0.0", hash, nil)
+	require.Nil(t, err)
+
+	logs := &bytes.Buffer{}
+	sha, err := RevParse("v1.0.0", clone, log.NewLogfmtLogger(logs))
+	require.Nil(t, err)
+	require.Equal(t, hash.String(), sha)
+	require.Contains(t, logs.String(), `msg="fetching the tags to resolve the revision" rev=v1.0.0`)
+
+	// resolvable revisions are not fetched
+	logs.Reset()
+	sha, err = RevParse("HEAD", clone, log.NewLogfmtLogger(logs))
+	require.Nil(t, err)
+	require.Equal(t, hash.String(), sha)
+	require.Empty(t, logs.String())
+
+	_, err = RevParse("v9.9.9", clone, log.NewNopLogger())
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "revision v9.9.9 cannot be resolved, even after fetching the tags")
+}
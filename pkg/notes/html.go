@@ -0,0 +1,126 @@
+package notes
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// WithLabelLegend allows the caller to append a legend of the labels of the
+// notes to the HTML output. The colors, as returned by LabelColorsOrNone, are
+// also used for the label chips of the notes.
+func WithLabelLegend(colors map[string]string) DocumentOption {
+	return func(c *documentConfig) {
+		c.labelLegend = true
+		c.labelColors = colors
+	}
+}
+
+// RenderHTML writes the release notes to the supplied io.Writer as an HTML
+// fragment with the same sections as the markdown format. Every note is
+// followed by its labels, rendered as chips.
+func RenderHTML(notes ReleaseNoteList, w io.Writer, opts ...DocumentOption) error {
+	c := documentConfigFromOpts(opts...)
+	if c.layout != "" && c.layout != LayoutFlat {
+		return errors.Errorf("HTML does not support the %q layout", c.layout)
+	}
+
+	type entry struct {
+		section
+		note *ReleaseNote
+	}
+
+	entries := []entry{}
+	present := map[sectionKind]bool{}
+	for _, note := range sortedNotes(notes, opts...) {
+		for _, s := range sectionsForNote(note, c) {
+			entries = append(entries, entry{section: s, note: note})
+			present[s.kind] = true
+		}
+	}
+	// the empty sections are entries without a note
+	for _, s := range missingSections(present, c) {
+		entries = append(entries, entry{section: s})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].kind != entries[j].kind {
+			return entries[i].kind < entries[j].kind
+		}
+		return entries[i].group < entries[j].group
+	})
+
+	var b strings.Builder
+	for i, e := range entries {
+		grouped := isGroupedSection(e.kind)
+		first := i == 0 || entries[i-1].kind != e.kind
+		if e.note == nil {
+			fmt.Fprintf(&b, "<h2 id=\"%s\">%s</h2>\n", AnchorFor(sectionKeys[e.kind]), html.EscapeString(sectionTitle(e.kind, c)))
+			continue
+		}
+		firstOfGroup := first || entries[i-1].group != e.group
+		last := i == len(entries)-1 || entries[i+1].kind != e.kind
+		lastOfGroup := last || entries[i+1].group != e.group
+
+		if first {
+			fmt.Fprintf(&b, "<h2 id=\"%s\">%s</h2>\n", AnchorFor(sectionKeys[e.kind]), html.EscapeString(sectionTitle(e.kind, c)))
+		}
+		if grouped && firstOfGroup {
+			fmt.Fprintf(&b, "<h3 id=\"%s\">%s</h3>\n", AnchorFor(sectionGroupKey(e.section)), html.EscapeString(sectionGroupTitle(e.section)))
+		}
+		if firstOfGroup {
+			b.WriteString("<ul>\n")
+		}
+		note := e.note
+		addRenderWarnings(note, c)
+		if c.enforceStyle {
+			note = styledNote(note)
+		}
+		fmt.Fprintf(&b, "  <li>%s</li>\n", noteHTML(note, c))
+		if lastOfGroup {
+			b.WriteString("</ul>\n")
+		}
+	}
+
+	if c.labelLegend {
+		if labels := NoteLabels(notes); len(labels) > 0 {
+			b.WriteString("<h2 id=\"labels\">Labels</h2>\n")
+			if err := RenderLabelLegendHTML(labels, c.labelColors, &b); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// noteHTML renders a note as the escaped text followed by links to the PR and
+// its author as well as the label chips
+func noteHTML(note *ReleaseNote, c *documentConfig) string {
+	var b strings.Builder
+	b.WriteString(html.EscapeString(note.Text))
+	fmt.Fprintf(&b, " (<a href=\"%s\">%s</a>", html.EscapeString(note.PrUrl), html.EscapeString(prLinkText(note, c)))
+	if note.Author != "" {
+		fmt.Fprintf(&b, ", <a href=\"%s\">@%s</a>", html.EscapeString(note.AuthorUrl), html.EscapeString(note.Author))
+	}
+	b.WriteString(")")
+
+	labels := NoteLabels(ReleaseNoteList{note.PrNumber: note})
+	for _, label := range labels {
+		fmt.Fprintf(&b, " %s", labelChipHTML(label, c.labelColors))
+	}
+	return b.String()
+}
+
+// labelChipHTML renders a label as a chip, colored if the color is known
+func labelChipHTML(label string, colors map[string]string) string {
+	style := ""
+	if color, ok := colors[label]; ok && color != "" {
+		style = fmt.Sprintf(" style=\"background-color: #%s\"", html.EscapeString(color))
+	}
+	return fmt.Sprintf("<span class=\"label\"%s>%s</span>", style, html.EscapeString(label))
+}
@@ -0,0 +1,52 @@
+package notes
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderHTML(t *testing.T) {
+	notes := ReleaseNoteList{
+		1: &ReleaseNote{
+			PrNumber:  1,
+			PrUrl:     "https://github.com/kubernetes/kubernetes/pull/1",
+			Author:    "jdoe",
+			AuthorUrl: "https://github.com/jdoe",
+			Text:      "Fixed <script> injection",
+			Kinds:     []string{"bug"},
+		},
+		2: &ReleaseNote{
+			PrNumber: 2,
+			PrUrl:    "https://github.com/kubernetes/kubernetes/pull/2",
+			Text:     "Improved the scheduler",
+			SIGs:     []string{"scheduling"},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	require.Nil(t, RenderHTML(notes, buf))
+	require.Equal(t, `<h2 id="sigs">Notes from Individual SIGs</h2>
+<h3 id="sig-scheduling">SIG Scheduling</h3>
+<ul>
+  <li>Improved the scheduler (<a href="https://github.com/kubernetes/kubernetes/pull/2">#2</a>) <span class="label">sig/scheduling</span></li>
+</ul>
+<h2 id="bug-fixes">Bug Fixes</h2>
+<ul>
+  <li>Fixed &lt;script&gt; injection (<a href="https://github.com/kubernetes/kubernetes/pull/1">#1</a>, <a href="https://github.com/jdoe">@jdoe</a>) <span class="label">kind/bug</span></li>
+</ul>
+`, buf.String())
+
+	buf.Reset()
+	require.Nil(t, RenderHTML(notes, buf, WithLabelLegend(map[string]string{"kind/bug": "d73a4a"})))
+	require.Contains(t, buf.String(), `<span class="label" style="background-color: #d73a4a">kind/bug</span></li>`)
+	require.Contains(t, buf.String(), `<h2 id="labels">Labels</h2>
+<div class="label-legend">
+  <span class="label" style="background-color: #d73a4a">kind/bug</span>
+  <span class="label">sig/scheduling</span>
+</div>
+`)
+
+	require.NotNil(t, RenderHTML(notes, buf, WithLayout(LayoutKubernetes)))
+}
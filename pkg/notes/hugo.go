@@ -0,0 +1,98 @@
+package notes
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// HugoIndexFile is the name of the index page of the content files of the
+// notes, which is the list page of their Hugo section
+const HugoIndexFile = "_index.md"
+
+// hugoFrontMatter is the front matter of the content file of a note. The
+// fields are marshalled in their declaration order.
+type hugoFrontMatter struct {
+	Title string   `yaml:"title"`
+	Date  string   `yaml:"date,omitempty"`
+	PR    int      `yaml:"pr,omitempty"`
+	SIGs  []string `yaml:"sigs,omitempty"`
+	Kind  []string `yaml:"kind,omitempty"`
+}
+
+// HugoFileName returns the name of the content file of the note of the PR with
+// the provided number. The names are unique within a list of notes, because
+// the notes are keyed by their PR number, and never collide with the
+// HugoIndexFile.
+func HugoFileName(number int) string {
+	return fmt.Sprintf("%d.md", number)
+}
+
+// hugoTitle returns the first line of the note text without markdown, which
+// is the title of its content file
+func hugoTitle(note *ReleaseNote) string {
+	title := StripMarkdown(note.Text)
+	if i := strings.Index(title, "\n"); i >= 0 {
+		title = title[:i]
+	}
+	return strings.TrimSpace(title)
+}
+
+// writeFrontMatter writes the front matter, delimited by "---" lines, to w.
+// Marshalling the values as YAML takes care of their quoting and escaping.
+func writeFrontMatter(frontMatter interface{}, w io.Writer) error {
+	content, err := yaml.Marshal(frontMatter)
+	if err != nil {
+		return errors.Wrap(err, "error marshalling the front matter")
+	}
+	_, err = fmt.Fprintf(w, "---\n%s---\n\n", content)
+	return err
+}
+
+// RenderHugoNote writes the content file of the note to w, which is the front
+// matter with its title, merge date, PR number, SIGs and kinds, followed by
+// the markdown of the note
+func RenderHugoNote(note *ReleaseNote, w io.Writer, opts ...DocumentOption) error {
+	c := documentConfigFromOpts(opts...)
+
+	frontMatter := hugoFrontMatter{
+		Title: hugoTitle(note),
+		PR:    note.PrNumber,
+		SIGs:  note.SIGs,
+		Kind:  note.Kinds,
+	}
+	if !note.MergedAt.IsZero() {
+		frontMatter.Date = note.MergedAt.UTC().Format(time.RFC3339)
+	}
+	if err := writeFrontMatter(frontMatter, w); err != nil {
+		return err
+	}
+
+	body, err := noteListItem(note, c)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, strings.TrimPrefix(body, "- ")+"\n")
+	return err
+}
+
+// RenderHugoIndex writes the index page of the content files of the notes to
+// w, which links the content file of every note in document order
+func RenderHugoIndex(notes ReleaseNoteList, title string, w io.Writer, opts ...DocumentOption) error {
+	if err := writeFrontMatter(hugoFrontMatter{Title: title}, w); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	for _, note := range sortedNotes(notes, opts...) {
+		// the brackets of the title must not end the link text
+		text := strings.NewReplacer("[", `\[`, "]", `\]`).Replace(hugoTitle(note))
+		fmt.Fprintf(&b, "- [%s]({{< relref %q >}})\n", text, HugoFileName(note.PrNumber))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
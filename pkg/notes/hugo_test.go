@@ -0,0 +1,67 @@
+package notes
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestRenderHugoNote(t *testing.T) {
+	note := &ReleaseNote{
+		PrNumber: 1,
+		Text:     "Fixed `kubectl: apply` with \"quotes\" & [brackets]\nSecond line",
+		Markdown: "- Fixed `kubectl: apply` ([#1](https://github.com/kubernetes/kubernetes/pull/1))",
+		SIGs:     []string{"cli"},
+		Kinds:    []string{"bug"},
+		MergedAt: time.Date(2019, 1, 7, 12, 30, 0, 0, time.UTC),
+	}
+
+	out := &bytes.Buffer{}
+	require.Nil(t, RenderHugoNote(note, out))
+	require.Equal(t, `---
+title: 'Fixed kubectl: apply with "quotes" & [brackets]'
+date: "2019-01-07T12:30:00Z"
+pr: 1
+sigs:
+- cli
+kind:
+- bug
+---
+
+Fixed `+"`kubectl: apply`"+` ([#1](https://github.com/kubernetes/kubernetes/pull/1))
+`, out.String())
+
+	// the front matter round-trips
+	parts := bytes.SplitN(out.Bytes(), []byte("---\n"), 3)
+	require.Len(t, parts, 3)
+	frontMatter := hugoFrontMatter{}
+	require.Nil(t, yaml.Unmarshal(parts[1], &frontMatter))
+	require.Equal(t, `Fixed kubectl: apply with "quotes" & [brackets]`, frontMatter.Title)
+	require.Equal(t, 1, frontMatter.PR)
+
+	// notes without a merge date have no date
+	note.MergedAt = time.Time{}
+	out.Reset()
+	require.Nil(t, RenderHugoNote(note, out))
+	require.NotContains(t, out.String(), "date:")
+}
+
+func TestRenderHugoIndex(t *testing.T) {
+	notes := ReleaseNoteList{
+		2: {PrNumber: 2, Text: "Second [note]"},
+		1: {PrNumber: 1, Text: "First note"},
+	}
+
+	out := &bytes.Buffer{}
+	require.Nil(t, RenderHugoIndex(notes, "Release Notes v1.16.0", out))
+	require.Equal(t, `---
+title: Release Notes v1.16.0
+---
+
+- [First note]({{< relref "1.md" >}})
+- [Second \[note\]]({{< relref "2.md" >}})
+`, out.String())
+}
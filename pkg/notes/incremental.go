@@ -0,0 +1,218 @@
+package notes
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// sectionAnchor matches the stable anchor and heading which start a top level
+// section of the markdown, capturing the anchor
+var sectionAnchor = regexp.MustCompile(`(?m)^<a id="([^"]*)"></a>\n## `)
+
+// List returns the notes keyed by their PR number, as needed to render them.
+// Notes of different repositories which share a PR number are an error.
+func (n KeyedReleaseNotes) List() (ReleaseNoteList, error) {
+	list := make(ReleaseNoteList, len(n))
+	for key, note := range n {
+		if existing, ok := list[note.PrNumber]; ok {
+			return nil, errors.Errorf(
+				"the notes %s and %s share a PR number, which a list of a single repository cannot hold",
+				NoteKey(existing), key,
+			)
+		}
+		list[note.PrNumber] = note
+	}
+	return list, nil
+}
+
+// topLevelSections returns the canonical keys of the top level sections of the
+// markdown which list the note
+func topLevelSections(note *ReleaseNote, c *documentConfig) []string {
+	if c.layout == LayoutKubernetes {
+		switch {
+		case collapsedDependency(note, c):
+			return []string{sectionKeys[sectionDependencies]}
+		case note.IsSecurity:
+			return []string{sectionKeys[sectionSecurity]}
+		case note.ActionRequired:
+			return []string{"urgent-upgrade-notes"}
+		}
+		return []string{"changes-by-kind"}
+	}
+
+	keys := []string{}
+	for _, s := range sectionsForNote(note, c) {
+		if key := sectionKeys[s.kind]; !HasString(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// ChangedSections returns the sorted canonical keys of the top level sections
+// whose markdown differs between the old and the new notes, because a note was
+// added, removed, moved to another section or rendered differently.
+func ChangedSections(old, new ReleaseNoteList, opts ...DocumentOption) ([]string, error) {
+	c := documentConfigFromOpts(opts...)
+
+	changed := []string{}
+	add := func(note *ReleaseNote) {
+		for _, key := range topLevelSections(note, c) {
+			if !HasString(changed, key) {
+				changed = append(changed, key)
+			}
+		}
+	}
+
+	for number, note := range new {
+		previous, ok := old[number]
+		if !ok {
+			add(note)
+			continue
+		}
+		item, err := noteListItem(note, c)
+		if err != nil {
+			return nil, err
+		}
+		previousItem, err := noteListItem(previous, c)
+		if err != nil {
+			return nil, err
+		}
+		sections, previousSections := topLevelSections(note, c), topLevelSections(previous, c)
+		sort.Strings(sections)
+		sort.Strings(previousSections)
+		if item != previousItem || !equalStrings(sections, previousSections) {
+			add(note)
+			add(previous)
+		}
+	}
+	for number, note := range old {
+		if _, ok := new[number]; !ok {
+			add(note)
+		}
+	}
+
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// equalStrings returns true if both slices hold the same strings in the same
+// order
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// markdownBlock is a top level section of the markdown, from its anchor up to
+// the anchor of the next section or the end of the document
+type markdownBlock struct {
+	anchor  string
+	content []byte
+}
+
+// splitMarkdownBlocks returns the content before the first top level section
+// and the top level sections of markdown rendered with WithStableAnchors
+func splitMarkdownBlocks(markdown []byte) ([]byte, []markdownBlock) {
+	matches := sectionAnchor.FindAllSubmatchIndex(markdown, -1)
+	if len(matches) == 0 {
+		return markdown, nil
+	}
+
+	blocks := []markdownBlock{}
+	for i, match := range matches {
+		end := len(markdown)
+		if i < len(matches)-1 {
+			end = matches[i+1][0]
+		}
+		blocks = append(blocks, markdownBlock{
+			anchor:  string(markdown[match[2]:match[3]]),
+			content: markdown[match[0]:end],
+		})
+	}
+	return markdown[:matches[0][0]], blocks
+}
+
+// UpdateMarkdown rewrites the top level sections of markdown, which was
+// rendered from the old notes with the same options, whose notes changed in
+// the new notes. Only the notes of the changed sections are rendered again, all
+// other sections, as well as the content before the first section, are kept as
+// they are. The sections are found by their stable anchors, so the markdown
+// must be rendered with WithStableAnchors, which is implied for the rewritten
+// sections. The second value is false if the markdown cannot be updated,
+// because it has no anchors or a changed section is missing from it, in which
+// case the caller renders the whole document instead.
+func UpdateMarkdown(markdown []byte, old, new ReleaseNoteList, opts ...DocumentOption) ([]byte, bool, error) {
+	opts = append(opts, WithStableAnchors())
+	c := documentConfigFromOpts(opts...)
+
+	preamble, blocks := splitMarkdownBlocks(markdown)
+	if len(blocks) == 0 {
+		return nil, false, nil
+	}
+
+	changed, err := ChangedSections(old, new, opts...)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(changed) == 0 {
+		return markdown, true, nil
+	}
+
+	// the notes of the changed sections render exactly these sections
+	affected := ReleaseNoteList{}
+	for number, note := range new {
+		for _, key := range topLevelSections(note, c) {
+			if HasString(changed, key) {
+				affected[number] = note
+			}
+		}
+	}
+	doc, err := CreateDocument(affected, opts...)
+	if err != nil {
+		return nil, false, err
+	}
+	rendered := &bytes.Buffer{}
+	if err := RenderMarkdown(doc, rendered, opts...); err != nil {
+		return nil, false, err
+	}
+	_, renderedBlocks := splitMarkdownBlocks(rendered.Bytes())
+	replacements := map[string][]byte{}
+	for _, block := range renderedBlocks {
+		replacements[block.anchor] = block.content
+	}
+
+	existing := map[string]bool{}
+	for _, block := range blocks {
+		existing[block.anchor] = true
+	}
+	anchors := map[string]bool{}
+	for _, key := range changed {
+		anchor := anchorForFlavor(key, c.flavor)
+		anchors[anchor] = true
+		// a section which appears would have to be placed among the others
+		if _, ok := replacements[anchor]; ok && !existing[anchor] {
+			return nil, false, nil
+		}
+	}
+
+	result := bytes.NewBuffer(append([]byte{}, preamble...))
+	for _, block := range blocks {
+		if !anchors[block.anchor] {
+			result.Write(block.content)
+			continue
+		}
+		// the sections which are emptied are dropped
+		result.Write(replacements[block.anchor])
+	}
+	return result.Bytes(), true, nil
+}
@@ -0,0 +1,110 @@
+package notes
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedSections(t *testing.T) {
+	old := ReleaseNoteList{
+		1: {PrNumber: 1, Markdown: "a feature", Feature: true},
+		2: {PrNumber: 2, Markdown: "a bug fix", Kinds: []string{"bug"}},
+		3: {PrNumber: 3, Markdown: "a node change", SIGs: []string{"node"}},
+	}
+	changed, err := ChangedSections(old, old)
+	require.Nil(t, err)
+	require.Empty(t, changed)
+
+	new := ReleaseNoteList{
+		1: old[1],
+		2: {PrNumber: 2, Markdown: "a bug fix", Kinds: []string{"bug"}, SIGs: []string{"node"}},
+		4: {PrNumber: 4, Markdown: "an API change", Kinds: []string{"api-change"}},
+	}
+	changed, err = ChangedSections(old, new)
+	require.Nil(t, err)
+	require.Equal(t, []string{"api-changes", "bug-fixes", "sigs"}, changed)
+
+	changed, err = ChangedSections(old, new, WithLayout(LayoutKubernetes))
+	require.Nil(t, err)
+	require.Equal(t, []string{"changes-by-kind"}, changed)
+}
+
+func TestUpdateMarkdown(t *testing.T) {
+	render := func(notes ReleaseNoteList, opts ...DocumentOption) []byte {
+		opts = append(opts, WithStableAnchors())
+		doc, err := CreateDocument(notes, opts...)
+		require.Nil(t, err)
+		buf := &bytes.Buffer{}
+		require.Nil(t, RenderMarkdown(doc, buf, opts...))
+		return buf.Bytes()
+	}
+
+	old := ReleaseNoteList{
+		1: {PrNumber: 1, Markdown: "a feature", Feature: true},
+		2: {PrNumber: 2, Markdown: "a bug fix", Kinds: []string{"bug"}},
+		3: {PrNumber: 3, Markdown: "a node change", SIGs: []string{"node"}},
+		4: {PrNumber: 4, Markdown: "another change"},
+	}
+	new := ReleaseNoteList{
+		1: old[1],
+		2: {PrNumber: 2, Markdown: "a better bug fix", Kinds: []string{"bug"}},
+		3: {PrNumber: 3, Markdown: "a node change", SIGs: []string{"node"}},
+		5: {PrNumber: 5, Markdown: "another node change", SIGs: []string{"node"}},
+	}
+
+	// without edits, the result is the full rendering of the new notes
+	updated, ok, err := UpdateMarkdown(render(old), old, new)
+	require.Nil(t, err)
+	require.True(t, ok)
+	require.Equal(t, string(render(new)), string(updated))
+	require.NotContains(t, string(updated), "another change")
+
+	// the sections without changes are kept as they are
+	edited := strings.Replace(string(render(old)), "- a feature", "- a feature, edited", 1)
+	updated, ok, err = UpdateMarkdown([]byte("# v1.17.0\n\n"+edited), old, new)
+	require.Nil(t, err)
+	require.True(t, ok)
+	require.True(t, strings.HasPrefix(string(updated), "# v1.17.0\n\n"))
+	require.Contains(t, string(updated), "- a feature, edited")
+	require.Contains(t, string(updated), "- a better bug fix")
+
+	// a section which appears requires a full rendering
+	appeared := ReleaseNoteList{1: old[1], 2: old[2], 3: old[3], 4: old[4], 6: {PrNumber: 6, Markdown: "removed a flag", ActionRequired: true}}
+	_, ok, err = UpdateMarkdown(render(old), old, appeared)
+	require.Nil(t, err)
+	require.False(t, ok)
+
+	// as does markdown without stable anchors
+	doc, err := CreateDocument(old)
+	require.Nil(t, err)
+	plain := &bytes.Buffer{}
+	require.Nil(t, RenderMarkdown(doc, plain))
+	_, ok, err = UpdateMarkdown(plain.Bytes(), old, new)
+	require.Nil(t, err)
+	require.False(t, ok)
+
+	// the kubernetes layout is updated by its top level sections
+	opts := []DocumentOption{WithLayout(LayoutKubernetes)}
+	updated, ok, err = UpdateMarkdown(render(old, opts...), old, new, opts...)
+	require.Nil(t, err)
+	require.True(t, ok)
+	require.Equal(t, string(render(new, opts...)), string(updated))
+}
+
+func TestKeyedReleaseNotesList(t *testing.T) {
+	list, err := KeyedReleaseNotes{
+		"kubernetes/kubernetes#1": {PrNumber: 1, PrUrl: "https://github.com/kubernetes/kubernetes/pull/1"},
+		"kubernetes/kubernetes#2": {PrNumber: 2, PrUrl: "https://github.com/kubernetes/kubernetes/pull/2"},
+	}.List()
+	require.Nil(t, err)
+	require.Len(t, list, 2)
+
+	_, err = KeyedReleaseNotes{
+		"kubernetes/kubernetes#1": {PrNumber: 1, PrUrl: "https://github.com/kubernetes/kubernetes/pull/1"},
+		"kubernetes/release#1":    {PrNumber: 1, PrUrl: "https://github.com/kubernetes/release/pull/1"},
+	}.List()
+	require.NotNil(t, err)
+}
@@ -0,0 +1,109 @@
+package notes
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// SectionIndex locates a section within a rendered markdown document
+type SectionIndex struct {
+	// Title is the heading text, like "SIG Node"
+	Title string `json:"title"`
+
+	// Level is the heading level, 2 for "##"
+	Level int `json:"level"`
+
+	// Anchor is the id of the stable anchor preceding the heading, or else the
+	// id derived from the heading text
+	Anchor string `json:"anchor"`
+
+	// Notes is the number of notes within the section and its subsections
+	Notes int `json:"notes"`
+
+	// Start and End are the byte range of the section within the document,
+	// including its stable anchor and subsections
+	Start int `json:"start"`
+	End   int `json:"end"`
+
+	// Sections are the subsections, like the SIGs
+	Sections []*SectionIndex `json:"sections,omitempty"`
+}
+
+// DocumentIndex is an index of the sections of a markdown document
+type DocumentIndex struct {
+	// Markdown is the path of the indexed document
+	Markdown string `json:"markdown"`
+
+	// Notes is the number of notes within all sections
+	Notes int `json:"notes"`
+
+	Sections []*SectionIndex `json:"sections"`
+}
+
+var (
+	indexHeading = regexp.MustCompile(`^(#{2,3}) (.*)$`)
+	indexAnchor  = regexp.MustCompile(`^<a id="([^"]*)"></a>$`)
+)
+
+// IndexMarkdown returns the "##" sections of a markdown document rendered by
+// RenderMarkdown, with their "###" subsections. The index is derived from the
+// rendered document, so it matches every layout. Notes are counted as the
+// list items which are not indented.
+func IndexMarkdown(markdown []byte, flavor MarkdownFlavor) *DocumentIndex {
+	index := &DocumentIndex{Sections: []*SectionIndex{}}
+
+	var section, subsection *SectionIndex
+	anchor, anchorStart := "", -1
+	offset := 0
+	for _, line := range bytes.SplitAfter(markdown, []byte("\n")) {
+		start := offset
+		offset += len(line)
+		text := strings.TrimRight(string(line), "\n")
+
+		if match := indexAnchor.FindStringSubmatch(text); match != nil {
+			anchor, anchorStart = match[1], start
+			continue
+		}
+
+		if match := indexHeading.FindStringSubmatch(text); match != nil {
+			s := &SectionIndex{Title: match[2], Level: len(match[1]), Anchor: anchor, Start: start}
+			if anchorStart >= 0 {
+				s.Start = anchorStart
+			}
+			if s.Anchor == "" {
+				s.Anchor = anchorForFlavor(s.Title, flavor)
+			}
+
+			if subsection != nil {
+				subsection.End = s.Start
+				subsection = nil
+			}
+			if s.Level == 2 || section == nil {
+				if section != nil {
+					section.End = s.Start
+				}
+				section = s
+				index.Sections = append(index.Sections, s)
+			} else {
+				subsection = s
+				section.Sections = append(section.Sections, s)
+			}
+		} else if strings.HasPrefix(text, "- ") && section != nil {
+			section.Notes++
+			index.Notes++
+			if subsection != nil {
+				subsection.Notes++
+			}
+		}
+		anchor, anchorStart = "", -1
+	}
+
+	if subsection != nil {
+		subsection.End = offset
+	}
+	if section != nil {
+		section.End = offset
+	}
+	return index
+}
@@ -0,0 +1,58 @@
+package notes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexMarkdown(t *testing.T) {
+	notes := ReleaseNoteList{
+		1: {PrNumber: 1, Markdown: "Add a flag", Kinds: []string{"feature"}, Feature: true},
+		2: {PrNumber: 2, Markdown: "Fix the kubelet\n  on restart", SIGs: []string{"node"}},
+		3: {PrNumber: 3, Markdown: "Fix the API server", SIGs: []string{"api-machinery"}},
+		4: {PrNumber: 4, Markdown: "Fix the scheduler", SIGs: []string{"scheduling"}},
+	}
+
+	for _, stableAnchors := range []bool{false, true} {
+		opts := []DocumentOption{}
+		if stableAnchors {
+			opts = append(opts, WithStableAnchors())
+		}
+		content, err := RenderToBytes(notes, "markdown", opts...)
+		require.Nil(t, err)
+		markdown := string(content)
+
+		index := IndexMarkdown(content, FlavorGFM)
+		require.Equal(t, 4, index.Notes)
+		require.Len(t, index.Sections, 2)
+
+		features, sigs := index.Sections[0], index.Sections[1]
+		require.Equal(t, "New Features", features.Title)
+		require.Equal(t, "new-features", features.Anchor)
+		require.Equal(t, 1, features.Notes)
+		require.Equal(t, 0, features.Start)
+		require.Equal(t, sigs.Start, features.End)
+
+		require.Equal(t, "Notes from Individual SIGs", sigs.Title)
+		require.Equal(t, 3, sigs.Notes)
+		require.Equal(t, len(markdown), sigs.End)
+		require.Len(t, sigs.Sections, 3)
+		require.Equal(t, "SIG API Machinery", sigs.Sections[0].Title)
+		require.Equal(t, 3, sigs.Sections[0].Level)
+		require.Equal(t, 1, sigs.Sections[1].Notes)
+
+		if stableAnchors {
+			require.Equal(t, "sigs", sigs.Anchor)
+			require.Equal(t, "sig-api-machinery", sigs.Sections[0].Anchor)
+			require.True(t, strings.HasPrefix(markdown[sigs.Start:sigs.End], `<a id="sigs"></a>`))
+		} else {
+			require.Equal(t, "notes-from-individual-sigs", sigs.Anchor)
+			require.True(t, strings.HasPrefix(markdown[sigs.Start:sigs.End], "## Notes from Individual SIGs"))
+		}
+		node := sigs.Sections[1]
+		require.Contains(t, markdown[node.Start:node.End], "Fix the kubelet\n  on restart")
+		require.NotContains(t, markdown[node.Start:node.End], "Fix the scheduler")
+	}
+}
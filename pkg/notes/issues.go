@@ -0,0 +1,61 @@
+package notes
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// WithLinkIssues allows the caller to append the issues which are fixed by a
+// PR to the markdown of its note.
+func WithLinkIssues() GithubApiOption {
+	return func(c *githubApiConfig) {
+		c.linkIssues = true
+	}
+}
+
+// IssueReferencesFromString returns the URLs of all issues referenced with a
+// closing keyword, like "Fixes #1234" or "Closes https://github.com/org/repo/issues/1234".
+// Short references are resolved against the provided org and repo.
+func IssueReferencesFromString(s, org, repo string) []string {
+	exp := regexp.MustCompile(`(?i)\b(?:fix|fixes|fixed|close|closes|closed|resolve|resolves|resolved):?\s+` +
+		`(?:(?P<short>#\d+)|(?P<repo>[\w.-]+/[\w.-]+#\d+)|https://github\.com/(?P<url>[\w.-]+/[\w.-]+/(?:issues|pull)/\d+))`)
+
+	issues := []string{}
+	for _, match := range exp.FindAllStringSubmatch(s, -1) {
+		var issue string
+		switch {
+		case match[1] != "":
+			issue = fmt.Sprintf("https://github.com/%s/%s/issues/%s", org, repo, strings.TrimPrefix(match[1], "#"))
+		case match[2] != "":
+			parts := strings.SplitN(match[2], "#", 2)
+			issue = fmt.Sprintf("https://github.com/%s/issues/%s", parts[0], parts[1])
+		default:
+			issue = "https://github.com/" + strings.Replace(match[3], "/pull/", "/issues/", 1)
+		}
+		if !HasString(issues, issue) {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// issuesSuffix renders the related issues of a note as markdown links. Issues
+// of the provided org and repo are abbreviated, like [#1234](...).
+func issuesSuffix(issues []string, org, repo string) string {
+	exp := regexp.MustCompile(`^https://github\.com/([^/]+/[^/]+)/issues/(\d+)$`)
+
+	links := []string{}
+	for _, issue := range issues {
+		match := exp.FindStringSubmatch(issue)
+		if match == nil {
+			continue
+		}
+		name := fmt.Sprintf("%s#%s", match[1], match[2])
+		if match[1] == org+"/"+repo {
+			name = "#" + match[2]
+		}
+		links = append(links, fmt.Sprintf("[%s](%s)", name, issue))
+	}
+	return "Related issues: " + strings.Join(links, ", ")
+}
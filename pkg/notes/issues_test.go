@@ -0,0 +1,33 @@
+package notes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssueReferencesFromString(t *testing.T) {
+	body := "Fixes #1234\r\n" +
+		"closes: kubernetes/enhancements#42\n" +
+		"Resolves https://github.com/kubernetes/test-infra/issues/7, also fixes #1234\n" +
+		"Related to #999, see https://github.com/kubernetes/kubernetes/issues/5"
+
+	require.Equal(t, []string{
+		"https://github.com/kubernetes/kubernetes/issues/1234",
+		"https://github.com/kubernetes/enhancements/issues/42",
+		"https://github.com/kubernetes/test-infra/issues/7",
+	}, IssueReferencesFromString(body, "kubernetes", "kubernetes"))
+
+	require.Empty(t, IssueReferencesFromString("Prefix the fixtures with #", "kubernetes", "kubernetes"))
+}
+
+func TestIssuesSuffix(t *testing.T) {
+	require.Equal(t,
+		"Related issues: [#1234](https://github.com/kubernetes/kubernetes/issues/1234), "+
+			"[kubernetes/enhancements#42](https://github.com/kubernetes/enhancements/issues/42)",
+		issuesSuffix([]string{
+			"https://github.com/kubernetes/kubernetes/issues/1234",
+			"https://github.com/kubernetes/enhancements/issues/42",
+		}, "kubernetes", "kubernetes"),
+	)
+}
@@ -0,0 +1,151 @@
+package notes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// prURLRepository matches the organization and repository of a PR URL, like
+// https://github.com/kubernetes/kubernetes/pull/123
+var prURLRepository = regexp.MustCompile(`^https?://[^/]+/([^/]+)/([^/]+)/pull/\d+`)
+
+// NoteKey returns the key of the note in the JSON output, which is the
+// composite "org/repo#pr" of its PR, like "kubernetes/kubernetes#123". Unlike
+// the PR number, the key is unique across repositories, so that the notes of
+// several repositories can be merged into one file. Notes without a GitHub PR
+// URL are keyed by "#pr".
+func NoteKey(note *ReleaseNote) string {
+	if match := prURLRepository.FindStringSubmatch(note.PrUrl); match != nil {
+		return fmt.Sprintf("%s/%s#%d", match[1], match[2], note.PrNumber)
+	}
+	return fmt.Sprintf("#%d", note.PrNumber)
+}
+
+// keyNumber returns the PR number of a key of the JSON output, which is either
+// a NoteKey or, in the files of previous versions, the plain PR number
+func keyNumber(key string) (int, error) {
+	number, err := strconv.Atoi(key[strings.LastIndex(key, "#")+1:])
+	if err != nil {
+		return 0, errors.Errorf("%q is not a valid release note key", key)
+	}
+	return number, nil
+}
+
+// MarshalJSON encodes the notes as an object keyed by their NoteKey
+func (l ReleaseNoteList) MarshalJSON() ([]byte, error) {
+	keyed := make(map[string]*ReleaseNote, len(l))
+	for _, note := range l {
+		keyed[NoteKey(note)] = note
+	}
+	return json.Marshal(keyed)
+}
+
+// UnmarshalJSON decodes an object of notes keyed by their NoteKey or, as
+// written by previous versions, by their PR number. Since the list is keyed by
+// PR number, notes of different repositories with the same PR number are an
+// error; such files are read with ParseKeyedReleaseNotes instead.
+func (l *ReleaseNoteList) UnmarshalJSON(data []byte) error {
+	keyed := map[string]*ReleaseNote{}
+	if err := json.Unmarshal(data, &keyed); err != nil {
+		return err
+	}
+
+	list := make(ReleaseNoteList, len(keyed))
+	for key, note := range keyed {
+		if note.PrNumber == 0 {
+			number, err := keyNumber(key)
+			if err != nil {
+				return err
+			}
+			note.PrNumber = number
+		}
+		if existing, ok := list[note.PrNumber]; ok && NoteKey(existing) != NoteKey(note) {
+			return errors.Errorf(
+				"the notes %s and %s share a PR number, which a list of a single repository cannot hold",
+				NoteKey(existing), NoteKey(note),
+			)
+		}
+		list[note.PrNumber] = note
+	}
+	*l = list
+	return nil
+}
+
+// KeyedReleaseNotes are the notes of possibly several repositories, keyed by
+// their NoteKey, like the ones of a JSON output which several runs were merged
+// into
+type KeyedReleaseNotes map[string]*ReleaseNote
+
+// ParseKeyedReleaseNotes decodes the notes of a JSON output. The files of
+// previous versions, which are keyed by PR number, are upgraded to be keyed by
+// NoteKey.
+func ParseKeyedReleaseNotes(data []byte) (KeyedReleaseNotes, error) {
+	keyed := map[string]*ReleaseNote{}
+	if err := json.Unmarshal(data, &keyed); err != nil {
+		return nil, err
+	}
+
+	notes := make(KeyedReleaseNotes, len(keyed))
+	for key, note := range keyed {
+		if note.PrNumber == 0 {
+			number, err := keyNumber(key)
+			if err != nil {
+				return nil, err
+			}
+			note.PrNumber = number
+		}
+		notes[NoteKey(note)] = note
+	}
+	return notes, nil
+}
+
+// MergeKeyedReleaseNotes returns the notes of the current run merged with the
+// ones of previous runs, which may belong to other repositories. The notes of
+// the current run replace the previous ones of the same PR. If annotateNew is
+// set, the notes of PRs which none of the previous runs listed are marked as
+// New.
+func MergeKeyedReleaseNotes(notes ReleaseNoteList, previous KeyedReleaseNotes, annotateNew bool) KeyedReleaseNotes {
+	merged := make(KeyedReleaseNotes, len(notes)+len(previous))
+	for key, note := range previous {
+		note.New = false
+		merged[key] = note
+	}
+	for _, note := range notes {
+		key := NoteKey(note)
+		_, carried := previous[key]
+		note.New = annotateNew && !carried
+		merged[key] = note
+	}
+	return merged
+}
+
+// MergeNewestKeyedReleaseNotes merges the notes like MergeKeyedReleaseNotes,
+// but keeps the previous note of a PR which was updated after the note of the
+// current run, like MergeStrategyNewest. This keeps the notes of a previous
+// run which was regenerated after the current run fetched its notes.
+func MergeNewestKeyedReleaseNotes(notes ReleaseNoteList, previous KeyedReleaseNotes, annotateNew bool) KeyedReleaseNotes {
+	merged := MergeKeyedReleaseNotes(notes, previous, annotateNew)
+	for key, note := range previous {
+		if NewerReleaseNote(note, merged[key]) {
+			merged[key] = note
+		}
+	}
+	return merged
+}
+
+// RenderKeyedJSON encodes the notes like the json format of RenderToBytes
+func RenderKeyedJSON(notes KeyedReleaseNotes) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(notes); err != nil {
+		return nil, errors.Wrap(err, "error encoding JSON output")
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,102 @@
+package notes
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoteKey(t *testing.T) {
+	require.Equal(t, "kubernetes/kubernetes#123", NoteKey(&ReleaseNote{
+		PrNumber: 123,
+		PrUrl:    "https://github.com/kubernetes/kubernetes/pull/123",
+	}))
+	require.Equal(t, "#123", NoteKey(&ReleaseNote{PrNumber: 123}))
+}
+
+func TestReleaseNoteListJSON(t *testing.T) {
+	notes := ReleaseNoteList{
+		1: {PrNumber: 1, PrUrl: "https://github.com/kubernetes/kubernetes/pull/1", Text: "a note"},
+	}
+	content, err := json.Marshal(notes)
+	require.Nil(t, err)
+	require.Contains(t, string(content), `"kubernetes/kubernetes#1":`)
+
+	decoded := ReleaseNoteList{}
+	require.Nil(t, json.Unmarshal(content, &decoded))
+	require.Equal(t, notes, decoded)
+
+	// the files of previous versions are keyed by PR number
+	decoded = ReleaseNoteList{}
+	require.Nil(t, json.Unmarshal([]byte(`{"2": {"pr_number": 2, "text": "an old note"}}`), &decoded))
+	require.Equal(t, "an old note", decoded[2].Text)
+
+	// a list cannot hold the same PR number of two repositories
+	err = json.Unmarshal([]byte(`{
+		"kubernetes/kubernetes#3": {"pr_number": 3, "pr_url": "https://github.com/kubernetes/kubernetes/pull/3"},
+		"kubernetes/release#3": {"pr_number": 3, "pr_url": "https://github.com/kubernetes/release/pull/3"}
+	}`), &decoded)
+	require.NotNil(t, err)
+}
+
+func TestMergeKeyedReleaseNotes(t *testing.T) {
+	// an old file, keyed by PR number, of another repository
+	previous, err := ParseKeyedReleaseNotes([]byte(`{
+		"1": {"pr_number": 1, "pr_url": "https://github.com/kubernetes/release/pull/1", "text": "release note"},
+		"2": {"pr_number": 2, "pr_url": "https://github.com/kubernetes/release/pull/2", "text": "another release note", "new": true}
+	}`))
+	require.Nil(t, err)
+	require.Len(t, previous, 2)
+	require.Contains(t, previous, "kubernetes/release#1")
+
+	notes := ReleaseNoteList{
+		1: {PrNumber: 1, PrUrl: "https://github.com/kubernetes/kubernetes/pull/1", Text: "kubernetes note"},
+	}
+	merged := MergeKeyedReleaseNotes(notes, previous, true)
+	require.Len(t, merged, 3)
+	require.Equal(t, "release note", merged["kubernetes/release#1"].Text)
+	require.Equal(t, "kubernetes note", merged["kubernetes/kubernetes#1"].Text)
+	require.True(t, merged["kubernetes/kubernetes#1"].New)
+	require.False(t, merged["kubernetes/release#2"].New)
+
+	// merging the same run again replaces its notes
+	merged = MergeKeyedReleaseNotes(notes, merged, true)
+	require.Len(t, merged, 3)
+	require.False(t, merged["kubernetes/kubernetes#1"].New)
+
+	content, err := RenderKeyedJSON(merged)
+	require.Nil(t, err)
+	require.Nil(t, ValidateJSON(content))
+	reparsed, err := ParseKeyedReleaseNotes(content)
+	require.Nil(t, err)
+	require.Equal(t, merged, reparsed)
+}
+
+func TestMergeNewestKeyedReleaseNotes(t *testing.T) {
+	// the previous run was regenerated after the current run fetched its notes
+	previous, err := ParseKeyedReleaseNotes([]byte(`{
+		"kubernetes/kubernetes#1": {"pr_number": 1, "pr_url": "https://github.com/kubernetes/kubernetes/pull/1", "text": "edited", "updated_at": "2019-01-02T00:00:00Z"},
+		"kubernetes/kubernetes#2": {"pr_number": 2, "pr_url": "https://github.com/kubernetes/kubernetes/pull/2", "text": "outdated", "updated_at": "2019-01-01T00:00:00Z"},
+		"kubernetes/kubernetes#3": {"pr_number": 3, "pr_url": "https://github.com/kubernetes/kubernetes/pull/3", "text": "without update time"}
+	}`))
+	require.Nil(t, err)
+
+	updatedAt := time.Date(2019, 1, 1, 12, 0, 0, 0, time.UTC)
+	notes := ReleaseNoteList{
+		1: {PrNumber: 1, PrUrl: "https://github.com/kubernetes/kubernetes/pull/1", Text: "original", UpdatedAt: &updatedAt},
+		2: {PrNumber: 2, PrUrl: "https://github.com/kubernetes/kubernetes/pull/2", Text: "current", UpdatedAt: &updatedAt},
+		3: {PrNumber: 3, PrUrl: "https://github.com/kubernetes/kubernetes/pull/3", Text: "with update time", UpdatedAt: &updatedAt},
+	}
+	merged := MergeNewestKeyedReleaseNotes(notes, previous, false)
+	require.Len(t, merged, 3)
+	require.Equal(t, "edited", merged["kubernetes/kubernetes#1"].Text)
+	require.Equal(t, "current", merged["kubernetes/kubernetes#2"].Text)
+	require.Equal(t, "with update time", merged["kubernetes/kubernetes#3"].Text)
+
+	content, err := RenderKeyedJSON(merged)
+	require.Nil(t, err)
+	require.Nil(t, ValidateJSON(content))
+	require.Contains(t, string(content), `"updated_at": "2019-01-02T00:00:00Z"`)
+}
@@ -0,0 +1,66 @@
+package notes
+
+import (
+	"regexp"
+	"strings"
+)
+
+// KindRule classifies the notes of PRs whose title matches the pattern into
+// the kind, like "bug"
+type KindRule struct {
+	Pattern *regexp.Regexp
+	Kind    string
+}
+
+// DefaultKindRules are the title heuristics used by WithInferKind if no
+// rules are provided
+var DefaultKindRules = []KindRule{
+	{Pattern: regexp.MustCompile(`(?i)^(fix|fixes|fixed|bugfix|hotfix)\b`), Kind: "bug"},
+	{Pattern: regexp.MustCompile(`(?i)^deprecat(e|es|ed|ing|ion)\b`), Kind: "deprecation"},
+	{Pattern: regexp.MustCompile(`(?i)^(feat|add|adds|added|introduce|introduces|implement|implements|support)\b`), Kind: "feature"},
+}
+
+// WithInferKind allows the caller to classify the notes of PRs without any
+// kind label by their title. The first matching rule wins, and the inferred
+// kind is marked with KindInferred. DefaultKindRules are used if no rules are
+// provided.
+func WithInferKind(rules []KindRule) GithubApiOption {
+	if len(rules) == 0 {
+		rules = DefaultKindRules
+	}
+	return func(c *githubApiConfig) {
+		c.kindRules = rules
+	}
+}
+
+// KindFromTitle returns the kind of the first rule matching the title, or an
+// empty string if none matches. Leading whitespace and a "[branch]" prefix,
+// like in cherry pick titles, are ignored.
+func KindFromTitle(title string, rules []KindRule) string {
+	title = strings.TrimSpace(title)
+	if strings.HasPrefix(title, "[") {
+		if end := strings.Index(title, "]"); end > 0 {
+			title = strings.TrimSpace(title[end+1:])
+		}
+	}
+
+	for _, rule := range rules {
+		if rule.Pattern.MatchString(title) {
+			return rule.Kind
+		}
+	}
+	return ""
+}
+
+// inferredKinds returns the kind inferred from the title if the kind
+// inference is enabled and there are no labeled kinds
+func inferredKinds(kinds []string, title string, opts ...GithubApiOption) ([]string, bool) {
+	c := configFromOpts(opts...)
+	if len(kinds) > 0 || len(c.kindRules) == 0 {
+		return kinds, false
+	}
+	if kind := KindFromTitle(title, c.kindRules); kind != "" {
+		return []string{kind}, true
+	}
+	return kinds, false
+}
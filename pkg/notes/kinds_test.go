@@ -0,0 +1,56 @@
+package notes
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKindFromTitle(t *testing.T) {
+	for _, tc := range []struct {
+		title string
+		kind  string
+	}{
+		{"Fix the kubelet crash on startup", "bug"},
+		{"fix: nil pointer in the scheduler", "bug"},
+		{"Bugfix for the volume manager", "bug"},
+		{"Deprecate the --foo flag", "deprecation"},
+		{"Deprecation of the v1beta1 API", "deprecation"},
+		{"Add support for IPv6", "feature"},
+		{"feat(kubectl): add a --bar flag", "feature"},
+		{"[release-1.15] Fix the kubelet crash", "bug"},
+		{"  Implements the new API", "feature"},
+		{"Fixture cleanup", ""},
+		{"Update the dependencies", ""},
+		{"Adding tests", ""},
+	} {
+		require.Equal(t, tc.kind, KindFromTitle(tc.title, DefaultKindRules), tc.title)
+	}
+}
+
+func TestInferredKinds(t *testing.T) {
+	// the inference is disabled by default
+	kinds, inferred := inferredKinds(nil, "Fix the kubelet")
+	require.Nil(t, kinds)
+	require.False(t, inferred)
+
+	// labeled kinds take precedence
+	kinds, inferred = inferredKinds([]string{"cleanup"}, "Fix the kubelet", WithInferKind(nil))
+	require.Equal(t, []string{"cleanup"}, kinds)
+	require.False(t, inferred)
+
+	kinds, inferred = inferredKinds(nil, "Fix the kubelet", WithInferKind(nil))
+	require.Equal(t, []string{"bug"}, kinds)
+	require.True(t, inferred)
+
+	// custom rules replace the default ones
+	rules := []KindRule{{Pattern: regexp.MustCompile(`^Cleanup`), Kind: "cleanup"}}
+	kinds, inferred = inferredKinds(nil, "Cleanup of the kubelet", WithInferKind(rules))
+	require.Equal(t, []string{"cleanup"}, kinds)
+	require.True(t, inferred)
+
+	kinds, inferred = inferredKinds(nil, "Fix the kubelet", WithInferKind(rules))
+	require.Nil(t, kinds)
+	require.False(t, inferred)
+}
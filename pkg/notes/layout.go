@@ -0,0 +1,149 @@
+package notes
+
+import (
+	"io"
+	"strings"
+)
+
+// DocumentLayout describes how the sections of a document are arranged
+type DocumentLayout string
+
+const (
+	// LayoutFlat arranges the notes in top level sections for action required
+	// notes, features, API changes, SIGs, bug fixes and other changes. This is
+	// the default.
+	LayoutFlat DocumentLayout = "flat"
+
+	// LayoutKubernetes mirrors the Kubernetes CHANGELOG with the "Urgent
+	// Upgrade Notes" followed by the "Changes by Kind" with one subsection per
+	// kind.
+	LayoutKubernetes DocumentLayout = "kubernetes"
+)
+
+// WithLayout allows the caller to select the arrangement of the sections of a
+// document. By default, it is LayoutFlat.
+func WithLayout(layout DocumentLayout) DocumentOption {
+	return func(c *documentConfig) {
+		c.layout = layout
+	}
+}
+
+// kubernetesKindSections are the subsections of the "Changes by Kind" in the
+// order of the Kubernetes CHANGELOG, with the kinds listed in each of them
+var kubernetesKindSections = []struct {
+	title string
+	key   string
+	kinds []string
+}{
+	{title: "Deprecation", key: "kind-deprecation", kinds: []string{"deprecation"}},
+	{title: "API Change", key: "kind-api-change", kinds: []string{"api-change"}},
+	{title: "Feature", key: "kind-feature", kinds: []string{"feature"}},
+	{title: "Design", key: "kind-design", kinds: []string{"design"}},
+	{title: "Documentation", key: "kind-documentation", kinds: []string{"documentation"}},
+	{title: "Failing Test", key: "kind-failing-test", kinds: []string{"failing-test"}},
+	{title: "Bug or Regression", key: "kind-bug", kinds: []string{"bug", "regression"}},
+	{title: "Other (Cleanup or Flake)", key: "kind-other", kinds: []string{"cleanup", "flake"}},
+	// the notes without any of the kinds above, titled like the
+	// uncategorized section of the other layouts
+	{key: "kind-uncategorized"},
+}
+
+// kubernetesKindTitle returns the title of a kind section
+func kubernetesKindTitle(title string, c *documentConfig) string {
+	if title == "" {
+		return sectionTitle(sectionUncategorized, c)
+	}
+	return title
+}
+
+// kubernetesKindSection returns the title of the first kind section which
+// lists one of the kinds of the note, or the title of the uncategorized notes
+func kubernetesKindSection(note *ReleaseNote, c *documentConfig) string {
+	for _, section := range kubernetesKindSections {
+		for _, kind := range section.kinds {
+			if HasString(note.Kinds, kind) {
+				return section.title
+			}
+		}
+	}
+	return sectionTitle(sectionUncategorized, c)
+}
+
+// createKubernetesDocument arranges the sorted notes in the LayoutKubernetes.
+// Security notes are only listed in the security section and notes which
+// require an action only in the urgent upgrade notes.
+func createKubernetesDocument(doc *Document, notes []*ReleaseNote, c *documentConfig) (*Document, error) {
+	doc.Layout = LayoutKubernetes
+	doc.Kinds = map[string][]string{}
+
+	for _, note := range notes {
+		item, err := noteListItem(note, c)
+		if err != nil {
+			return nil, err
+		}
+		if collapsedDependency(note, c) {
+			doc.Dependencies = append(doc.Dependencies, item)
+			continue
+		}
+		if note.IsSecurity {
+			doc.Security = append(doc.Security, item)
+			continue
+		}
+		if note.ActionRequired {
+			doc.ActionRequired = append(doc.ActionRequired, item)
+			continue
+		}
+		title := kubernetesKindSection(note, c)
+		doc.Kinds[title] = append(doc.Kinds[title], item)
+	}
+	return doc, nil
+}
+
+// renderKubernetesMarkdown writes a document arranged in the LayoutKubernetes
+// to the supplied io.Writer in markdown format
+func renderKubernetesMarkdown(doc *Document, w io.Writer, c *documentConfig) error {
+	var b strings.Builder
+	writeNotes := func(notes []string) {
+		for _, note := range notes {
+			if !strings.HasPrefix(note, "- ") {
+				note = "- " + note
+			}
+			b.WriteString(note + "\n")
+		}
+	}
+
+	if len(doc.Security) > 0 || c.emptySections {
+		b.WriteString(headingMarkdown("##", sectionTitle(sectionSecurity, c), sectionKeys[sectionSecurity], c))
+		writeNotes(doc.Security)
+		b.WriteString("\n")
+	}
+
+	if len(doc.ActionRequired) > 0 || c.emptySections {
+		b.WriteString(headingMarkdown("##", "Urgent Upgrade Notes", "urgent-upgrade-notes", c))
+		b.WriteString("### (No, really, you MUST read this before you upgrade)\n\n")
+		writeNotes(doc.ActionRequired)
+		b.WriteString("\n")
+	}
+
+	if len(doc.Kinds) > 0 || c.emptySections {
+		b.WriteString(headingMarkdown("##", "Changes by Kind", "changes-by-kind", c))
+		for _, section := range kubernetesKindSections {
+			title := kubernetesKindTitle(section.title, c)
+			if len(doc.Kinds[title]) == 0 && !c.emptySections {
+				continue
+			}
+			b.WriteString(headingMarkdown("###", title, section.key, c))
+			writeNotes(doc.Kinds[title])
+			b.WriteString("\n")
+		}
+	}
+
+	if len(doc.Dependencies) > 0 || (c.emptySections && c.collapseDeps) {
+		b.WriteString(dependenciesOpening(len(doc.Dependencies), c))
+		writeNotes(doc.Dependencies)
+		b.WriteString(dependenciesClosing)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
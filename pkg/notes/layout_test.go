@@ -0,0 +1,59 @@
+package notes
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubernetesLayout(t *testing.T) {
+	notes := ReleaseNoteList{
+		1: &ReleaseNote{PrNumber: 1, Markdown: "removed the foo flag", Kinds: []string{"cleanup"}, ActionRequired: true},
+		2: &ReleaseNote{PrNumber: 2, Markdown: "fixed a crash", Kinds: []string{"bug"}},
+		3: &ReleaseNote{PrNumber: 3, Markdown: "added the bar API", Kinds: []string{"feature", "api-change"}},
+		4: &ReleaseNote{PrNumber: 4, Markdown: "fixed a flake", Kinds: []string{"flake"}},
+		5: &ReleaseNote{PrNumber: 5, Markdown: "changed something"},
+	}
+
+	doc, err := CreateDocument(notes, WithLayout(LayoutKubernetes))
+	require.Nil(t, err)
+	require.Equal(t, LayoutKubernetes, doc.Layout)
+	require.Equal(t, []string{"removed the foo flag"}, doc.ActionRequired)
+	require.Equal(t, map[string][]string{
+		"API Change":               {"added the bar API"},
+		"Bug or Regression":        {"fixed a crash"},
+		"Other (Cleanup or Flake)": {"fixed a flake"},
+		"Other Notable Changes":    {"changed something"},
+	}, doc.Kinds)
+
+	buf := &bytes.Buffer{}
+	require.Nil(t, RenderMarkdown(doc, buf))
+	require.Equal(t, `## Urgent Upgrade Notes
+
+### (No, really, you MUST read this before you upgrade)
+
+- removed the foo flag
+
+## Changes by Kind
+
+### API Change
+
+- added the bar API
+
+### Bug or Regression
+
+- fixed a crash
+
+### Other (Cleanup or Flake)
+
+- fixed a flake
+
+### Other Notable Changes
+
+- changed something
+
+`, buf.String())
+
+	require.NotNil(t, RenderMarkdownStream(notes, &bytes.Buffer{}, WithLayout(LayoutKubernetes)))
+}
@@ -0,0 +1,85 @@
+package notes
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/google/go-github/v27/github"
+)
+
+// LabelColors returns the hex colors of all labels of the repository, like
+// "d73a4a" for "kind/bug". The labels are fetched once with as few requests
+// as possible, so the result is meant to be reused for all notes.
+func LabelColors(client *github.Client, opts ...GithubApiOption) (map[string]string, error) {
+	c := configFromOpts(opts...)
+
+	lo := &github.ListOptions{
+		Page:    1,
+		PerPage: 100,
+	}
+
+	colors := map[string]string{}
+	for {
+		labels, resp, err := client.Issues.ListLabels(c.ctx, c.org, c.repo, lo)
+		if err != nil {
+			return nil, err
+		}
+		for _, label := range labels {
+			colors[label.GetName()] = label.GetColor()
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		lo.Page = resp.NextPage
+	}
+	return colors, nil
+}
+
+// LabelColorsOrNone returns the label colors like LabelColors, but degrades
+// to no colors if the labels API is unavailable.
+func LabelColorsOrNone(client *github.Client, logger log.Logger, opts ...GithubApiOption) map[string]string {
+	colors, err := LabelColors(client, opts...)
+	if err != nil {
+		level.Warn(logger).Log("msg", "error fetching the label colors, the legend is rendered without colors", "err", err)
+		return map[string]string{}
+	}
+	return colors
+}
+
+// NoteLabels returns the sorted kind, area and sig labels of the notes, like
+// "kind/bug"
+func NoteLabels(notes ReleaseNoteList) []string {
+	seen := map[string]struct{}{}
+	for _, note := range notes {
+		for prefix, values := range map[string][]string{"kind": note.Kinds, "area": note.Areas, "sig": note.SIGs} {
+			for _, value := range values {
+				seen[prefix+"/"+value] = struct{}{}
+			}
+		}
+	}
+
+	labels := make([]string, 0, len(seen))
+	for label := range seen {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// RenderLabelLegendHTML writes a legend of the labels as colored chips to the
+// supplied io.Writer. Labels without a known color are rendered without one.
+func RenderLabelLegendHTML(labels []string, colors map[string]string, w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("<div class=\"label-legend\">\n")
+	for _, label := range labels {
+		fmt.Fprintf(&b, "  %s\n", labelChipHTML(label, colors))
+	}
+	b.WriteString("</div>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,63 @@
+package notes
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelColors(t *testing.T) {
+	requests := 0
+	client, server := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		require.Equal(t, "/repos/kubernetes/kubernetes/labels", r.URL.Path)
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, `[{"name": "sig/node", "color": "c5def5"}]`)
+			return
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s/repos/kubernetes/kubernetes/labels?page=2>; rel="next"`, r.Host))
+		fmt.Fprint(w, `[{"name": "kind/bug", "color": "d73a4a"}]`)
+	}))
+	defer server.Close()
+
+	colors, err := LabelColors(client)
+	require.Nil(t, err)
+	require.Equal(t, map[string]string{"kind/bug": "d73a4a", "sig/node": "c5def5"}, colors)
+	require.Equal(t, 2, requests)
+	require.Equal(t, colors, LabelColorsOrNone(client, log.NewNopLogger()))
+}
+
+func TestLabelColorsOrNone(t *testing.T) {
+	client, server := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	_, err := LabelColors(client)
+	require.NotNil(t, err)
+	require.Equal(t, map[string]string{}, LabelColorsOrNone(client, log.NewNopLogger()))
+}
+
+func TestNoteLabels(t *testing.T) {
+	notes := ReleaseNoteList{
+		1: &ReleaseNote{Kinds: []string{"bug"}, SIGs: []string{"node"}},
+		2: &ReleaseNote{Kinds: []string{"bug", "feature"}, Areas: []string{"kubelet"}},
+		3: &ReleaseNote{},
+	}
+	require.Equal(t, []string{"area/kubelet", "kind/bug", "kind/feature", "sig/node"}, NoteLabels(notes))
+}
+
+func TestRenderLabelLegendHTML(t *testing.T) {
+	buf := &bytes.Buffer{}
+	colors := map[string]string{"kind/bug": "d73a4a"}
+	require.Nil(t, RenderLabelLegendHTML([]string{"kind/bug", "sig/<node>"}, colors, buf))
+	require.Equal(t, `<div class="label-legend">
+  <span class="label" style="background-color: #d73a4a">kind/bug</span>
+  <span class="label">sig/&lt;node&gt;</span>
+</div>
+`, buf.String())
+}
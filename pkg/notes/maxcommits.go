@@ -0,0 +1,39 @@
+package notes
+
+import (
+	"github.com/google/go-github/v27/github"
+	"github.com/pkg/errors"
+)
+
+// WithMaxRangeCommits allows the caller to refuse listing the commits of a
+// range with more than max commits, which is most likely a wrong range, like
+// one starting at the root commit of the repository. The commits are counted
+// with a single comparison before any of them is listed. A max of 0 disables
+// the limit.
+func WithMaxRangeCommits(max int) GithubApiOption {
+	return func(c *githubApiConfig) {
+		c.maxRangeCommits = max
+	}
+}
+
+// checkRangeCommits returns an error caused by ErrRangeTooLarge if the range
+// from start to end has more commits than the configured maximum
+func checkRangeCommits(client *github.Client, start, end string, c *githubApiConfig) error {
+	if c.maxRangeCommits <= 0 {
+		return nil
+	}
+	comparison, _, err := client.Repositories.CompareCommits(c.ctx, c.org, c.repo, start, end)
+	if err != nil {
+		return classifyError(errors.Wrapf(err, "error comparing %s...%s", start, end), ErrInvalidRange)
+	}
+	if commits := comparison.GetTotalCommits(); commits > c.maxRangeCommits {
+		return &Error{
+			Cause: ErrRangeTooLarge,
+			Err: errors.Errorf(
+				"the range %s...%s has %d commits, more than the maximum of %d: the range is most likely wrong, check its start and end",
+				start, end, commits, c.maxRangeCommits,
+			),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package notes
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCommitsMaxRangeCommits(t *testing.T) {
+	compared := 0
+	client, server := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/kubernetes/kubernetes/compare/root...v1.17.0":
+			compared++
+			fmt.Fprint(w, `{"total_commits": 90000}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	_, err := ListCommits(client, "master", "root", "v1.17.0", WithMaxRangeCommits(50000))
+	require.Equal(t, ErrRangeTooLarge, ErrorCause(err))
+	require.Contains(t, err.Error(), "90000 commits, more than the maximum of 50000")
+	require.Equal(t, 1, compared)
+
+	// within the limit, the start commit is fetched, which is not served
+	_, err = ListCommits(client, "master", "root", "v1.17.0", WithMaxRangeCommits(100000))
+	require.Equal(t, ErrInvalidRange, ErrorCause(err))
+	require.Equal(t, 2, compared)
+
+	// without a limit, the range is not compared
+	_, err = ListCommits(client, "master", "root", "v1.17.0")
+	require.Equal(t, ErrInvalidRange, ErrorCause(err))
+	require.Equal(t, 2, compared)
+
+	_, err = ListCommits(client, "master", "missing", "v1.17.0", WithMaxRangeCommits(100000))
+	require.Equal(t, ErrInvalidRange, ErrorCause(err))
+}
@@ -0,0 +1,87 @@
+package notes
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// MergeStrategy describes how notes for the same PR from different lists are
+// combined.
+type MergeStrategy string
+
+const (
+	// MergeStrategyError fails if the lists contain different notes for the
+	// same PR. Identical notes are not a conflict. This is the default.
+	MergeStrategyError MergeStrategy = "error"
+
+	// MergeStrategyFirst keeps the note of the first list containing the PR
+	MergeStrategyFirst MergeStrategy = "first"
+
+	// MergeStrategyLast keeps the note of the last list containing the PR
+	MergeStrategyLast MergeStrategy = "last"
+
+	// MergeStrategyNewest keeps the note whose PR was updated last, see
+	// NewerReleaseNote
+	MergeStrategyNewest MergeStrategy = "prefer-newest"
+)
+
+// NewerReleaseNote reports whether the PR of the note was updated after the
+// one of the other note. Notes without an update time, like the ones of older
+// runs, are older than all notes with one.
+func NewerReleaseNote(note, other *ReleaseNote) bool {
+	if note.UpdatedAt == nil {
+		return false
+	}
+	return other.UpdatedAt == nil || note.UpdatedAt.After(*other.UpdatedAt)
+}
+
+// ReadReleaseNotes reads a list of release notes from a JSON file, like the
+// ones written with the json format.
+func ReadReleaseNotes(path string) (ReleaseNoteList, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading release notes %s", path)
+	}
+
+	notes := ReleaseNoteList{}
+	if err := json.Unmarshal(content, &notes); err != nil {
+		return nil, errors.Wrapf(err, "decoding release notes %s", path)
+	}
+	return notes, nil
+}
+
+// CombineReleaseNotes merges the lists into one list with a single note per
+// PR number. Notes for the same PR are resolved by the strategy.
+func CombineReleaseNotes(lists []ReleaseNoteList, strategy MergeStrategy) (ReleaseNoteList, error) {
+	combined := ReleaseNoteList{}
+	for _, list := range lists {
+		for number, note := range list {
+			existing, ok := combined[number]
+			if !ok {
+				combined[number] = note
+				continue
+			}
+
+			switch strategy {
+			case MergeStrategyFirst:
+			case MergeStrategyLast:
+				combined[number] = note
+			case MergeStrategyNewest:
+				// the note of the later list wins a tie
+				if !NewerReleaseNote(existing, note) {
+					combined[number] = note
+				}
+			case MergeStrategyError, "":
+				if !reflect.DeepEqual(existing, note) {
+					return nil, errors.Errorf("conflicting notes for PR #%d", number)
+				}
+			default:
+				return nil, errors.Errorf("%q is an unsupported merge strategy", strategy)
+			}
+		}
+	}
+	return combined, nil
+}
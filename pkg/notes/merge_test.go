@@ -0,0 +1,86 @@
+package notes
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadReleaseNotes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "merge")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "sig-node.json")
+	require.Nil(t, ioutil.WriteFile(path, []byte(`{"1": {"pr_number": 1, "text": "a note"}}`), 0644))
+
+	notes, err := ReadReleaseNotes(path)
+	require.Nil(t, err)
+	require.Equal(t, ReleaseNoteList{1: &ReleaseNote{PrNumber: 1, Text: "a note"}}, notes)
+
+	_, err = ReadReleaseNotes(filepath.Join(dir, "missing.json"))
+	require.NotNil(t, err)
+}
+
+func TestCombineReleaseNotes(t *testing.T) {
+	first := ReleaseNoteList{
+		1: &ReleaseNote{PrNumber: 1, Text: "first"},
+		2: &ReleaseNote{PrNumber: 2, Text: "only in first"},
+	}
+	second := ReleaseNoteList{
+		1: &ReleaseNote{PrNumber: 1, Text: "second"},
+		3: &ReleaseNote{PrNumber: 3, Text: "only in second"},
+	}
+
+	combined, err := CombineReleaseNotes([]ReleaseNoteList{first, second}, MergeStrategyFirst)
+	require.Nil(t, err)
+	require.Len(t, combined, 3)
+	require.Equal(t, "first", combined[1].Text)
+
+	combined, err = CombineReleaseNotes([]ReleaseNoteList{first, second}, MergeStrategyLast)
+	require.Nil(t, err)
+	require.Len(t, combined, 3)
+	require.Equal(t, "second", combined[1].Text)
+
+	_, err = CombineReleaseNotes([]ReleaseNoteList{first, second}, MergeStrategyError)
+	require.NotNil(t, err)
+
+	// identical notes are no conflict
+	duplicate := ReleaseNoteList{1: &ReleaseNote{PrNumber: 1, Text: "first"}}
+	combined, err = CombineReleaseNotes([]ReleaseNoteList{first, duplicate}, MergeStrategyError)
+	require.Nil(t, err)
+	require.Len(t, combined, 2)
+}
+
+func TestCombineReleaseNotesNewest(t *testing.T) {
+	earlier := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Hour)
+
+	regenerated := ReleaseNoteList{
+		1: &ReleaseNote{PrNumber: 1, Text: "edited", UpdatedAt: &later},
+		2: &ReleaseNote{PrNumber: 2, Text: "tie in regenerated", UpdatedAt: &earlier},
+		3: &ReleaseNote{PrNumber: 3, Text: "without update time"},
+	}
+	partial := ReleaseNoteList{
+		1: &ReleaseNote{PrNumber: 1, Text: "original", UpdatedAt: &earlier},
+		2: &ReleaseNote{PrNumber: 2, Text: "tie in partial", UpdatedAt: &earlier},
+		3: &ReleaseNote{PrNumber: 3, Text: "with update time", UpdatedAt: &earlier},
+	}
+
+	combined, err := CombineReleaseNotes([]ReleaseNoteList{regenerated, partial}, MergeStrategyNewest)
+	require.Nil(t, err)
+	require.Equal(t, "edited", combined[1].Text)
+	// the later list wins a tie
+	require.Equal(t, "tie in partial", combined[2].Text)
+	require.Equal(t, "with update time", combined[3].Text)
+
+	combined, err = CombineReleaseNotes([]ReleaseNoteList{partial, regenerated}, MergeStrategyNewest)
+	require.Nil(t, err)
+	require.Equal(t, "edited", combined[1].Text)
+	require.Equal(t, "tie in regenerated", combined[2].Text)
+	require.Equal(t, "with update time", combined[3].Text)
+}
@@ -17,11 +17,17 @@ package notes
 import (
 	"bufio"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"net/url"
+	"os"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/go-kit/kit/log"
 	"github.com/go-kit/kit/log/level"
@@ -32,6 +38,10 @@ import (
 // ReleaseNote is the type that represents the total sum of all the information
 // we've gathered about a single release note.
 type ReleaseNote struct {
+	// ID is a stable identifier of the note, which is derived from the org,
+	// repo and PR number and therefore survives changes of the note text
+	ID string `json:"id,omitempty"`
+
 	// Commit is the SHA of the commit which is the source of this note. This is
 	// also effectively a unique ID for release notes.
 	Commit string `json:"commit"`
@@ -57,12 +67,28 @@ type ReleaseNote struct {
 	// PrNumber is the number of the PR
 	PrNumber int `json:"pr_number"`
 
+	// PrTitle is the title of the PR, or the subject of the commit message if
+	// the note is not read from a PR
+	PrTitle string `json:"pr_title,omitempty"`
+
+	// TitleSubstituted indicates that the note only referred to the PR title,
+	// like "See PR title", and its text was replaced by the title
+	TitleSubstituted bool `json:"title_substituted,omitempty"`
+
+	// PrNumbers are the numbers of all PRs with an identical note, including
+	// PrNumber, if the notes were collapsed into this one
+	PrNumbers []int `json:"pr_numbers,omitempty"`
+
 	// Areas is a list of the labels beginning with area/
 	Areas []string `json:"areas,omitempty"`
 
 	// Kinds is a list of the labels beginning with kind/
 	Kinds []string `json:"kinds,omitempty"`
 
+	// KindInferred indicates that the kind was inferred from the PR title
+	// instead of taken from the labels
+	KindInferred bool `json:"kind_inferred,omitempty"`
+
 	// SIGs is a list of the labels beginning with sig/
 	SIGs []string `json:"sigs,omitempty"`
 
@@ -76,6 +102,48 @@ type ReleaseNote struct {
 	// label was set on the PR
 	ActionRequired bool `json:"action_required,omitempty"`
 
+	// IsSecurity indicates whether or not one of the security labels was set
+	// on the PR
+	IsSecurity bool `json:"is_security,omitempty"`
+
+	// Description is the first paragraph of the PR body, if requested
+	Description string `json:"description,omitempty"`
+
+	// CoAuthors are the contributors listed in Co-authored-by commit trailers
+	CoAuthors []CoAuthor `json:"co_authors,omitempty"`
+
+	// Branches are the tracked branches which contain the commit of the note
+	Branches []string `json:"branches,omitempty"`
+
+	// RelatedIssues are the URLs of the issues fixed by the PR
+	RelatedIssues []string `json:"related_issues,omitempty"`
+
+	// Subtasks are the task list items of the PR body, if requested
+	Subtasks []Subtask `json:"subtasks,omitempty"`
+
+	// Reactions is the total number of reactions on the PR, if requested
+	Reactions int `json:"reactions,omitempty"`
+
+	// Dependency indicates that the PR updates a dependency, as detected by
+	// IsDependencyBump
+	Dependency bool `json:"dependency,omitempty"`
+
+	// Stats is the size of the change of the PR, if requested
+	Stats *Stats `json:"stats,omitempty"`
+
+	// MergedAt is the time the PR was merged, or the commit was committed if
+	// the note is not read from a PR. It is not persisted.
+	MergedAt time.Time `json:"-"`
+
+	// UpdatedAt is the time the PR was last updated, like when its
+	// description was edited. It is not set if the note is not read from a
+	// PR.
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+
+	// New indicates that the note was added by the current run when merged
+	// with the notes of a previous run
+	New bool `json:"new,omitempty"`
+
 	// Tags each note with a release version if specified
 	// If not specified, omitted
 	ReleaseVersion string `json:"release_version,omitempty"`
@@ -119,6 +187,116 @@ type githubApiConfig struct {
 	org    string
 	repo   string
 	branch string
+
+	// includeDescription enables capturing the first paragraph of the PR body
+	includeDescription  bool
+	descriptionMaxChars int
+
+	// noteSource selects where the release note text is extracted from
+	noteSource NoteSource
+
+	// prNumberRegex overrides the expressions used to find the PR number in a
+	// commit message
+	prNumberRegex *regexp.Regexp
+
+	// requireMerged skips the PRs associated with a commit which are not
+	// merged
+	requireMerged bool
+
+	// trackBranches are checked for containing the commit of every note
+	trackBranches []string
+
+	// linkIssues appends the related issues to the markdown of the notes
+	linkIssues bool
+
+	// includeSubtasks extracts the task list items of the PR bodies
+	includeSubtasks bool
+
+	// seeTitlePhrases are the notes which are replaced by the PR title
+	seeTitlePhrases []string
+
+	// maxRangeCommits is the maximum number of commits of a range, if not 0
+	maxRangeCommits int
+
+	// suppressRevertedInRange drops the notes of commits which are reverted
+	// within the same range, as well as the notes of the reverts
+	suppressRevertedInRange bool
+
+	// sigOwners maps directory prefixes to the SIGs owning them
+	sigOwners map[string]string
+
+	// authorField selects the user of a commit which must be the required
+	// author
+	authorField AuthorField
+
+	// requiredAuthors are the logins of which one must be the author of a
+	// commit, in addition to the required author passed to ListReleaseNotes
+	requiredAuthors []string
+
+	// securityLabels mark the PRs whose notes are security related
+	securityLabels []string
+
+	// dedupeIdenticalText collapses notes with identical text instead of
+	// dropping all but the first one
+	dedupeIdenticalText bool
+
+	// progress receives progress events, if set
+	progress chan<- Progress
+
+	// sigBudget limits the number of PRs whose SIGs are inferred, if set
+	sigBudget *inferenceBudget
+
+	// kindRules classify the notes of PRs without kind labels by their
+	// title, if set
+	kindRules []KindRule
+
+	// fetchReactions fetches the reactions on every PR
+	fetchReactions bool
+
+	// reactionsBudget limits the number of PRs whose reactions are fetched,
+	// if set
+	reactionsBudget *inferenceBudget
+
+	// fetchStats fetches the stats of every PR
+	fetchStats bool
+
+	// statsBudget limits the number of PRs whose stats are fetched with an
+	// additional request, if set
+	statsBudget *inferenceBudget
+
+	// wrapColumn soft-wraps the markdown of the notes, if greater than zero
+	wrapColumn int
+
+	// normalizeWhitespace enables cleaning up the whitespace of the notes
+	normalizeWhitespace bool
+
+	// checkpointFile records the progress of ListReleaseNotes every
+	// checkpointInterval commits, if set
+	checkpointFile     string
+	checkpointInterval int
+
+	// excludeDocs drops the notes of PRs with one of the docsLabels or, if
+	// docsPaths are set, which only change files below them
+	excludeDocs bool
+	docsLabels  []string
+	docsPaths   []string
+
+	// docsBudget limits the number of PRs whose files are checked, if set
+	docsBudget *inferenceBudget
+
+	// pathFilter drops the notes of PRs which change no file matching one of
+	// the glob patterns
+	pathFilter []string
+
+	// pathBudget limits the number of PRs whose files are matched against the
+	// path filter, if set
+	pathBudget *inferenceBudget
+
+	// verbosity traces the processing of every commit, if greater than zero
+	verbosity int
+
+	// warnings collects the warnings, if set
+	warnings *Warnings
 }
 
 // WithContext allows the caller to inject a context into GitHub API requests
@@ -152,8 +330,176 @@ func WithBranch(branch string) GithubApiOption {
 	}
 }
 
+// WithDescription allows the caller to include the first paragraph of the PR
+// description with every note. The description is truncated to maxChars
+// characters, where a value of zero or less means no truncation.
+func WithDescription(maxChars int) GithubApiOption {
+	return func(c *githubApiConfig) {
+		c.includeDescription = true
+		c.descriptionMaxChars = maxChars
+	}
+}
+
+// WithPRNumberRegex allows the caller to override the regular expression used
+// to extract the PR number from a commit message. The expression must contain
+// a capture group for the number, which is either named "number" or the first
+// group of the expression.
+func WithPRNumberRegex(exp *regexp.Regexp) GithubApiOption {
+	return func(c *githubApiConfig) {
+		c.prNumberRegex = exp
+	}
+}
+
+// WithRequireMerged allows the caller to ignore the PRs associated with a
+// commit which are not merged. This avoids picking up the note of another,
+// unmerged PR containing the same commit.
+func WithRequireMerged() GithubApiOption {
+	return func(c *githubApiConfig) {
+		c.requireMerged = true
+	}
+}
+
+// WithNormalizeWhitespace allows the caller to disable the whitespace
+// normalization of the note text and markdown. By default, it is enabled.
+func WithNormalizeWhitespace(enabled bool) GithubApiOption {
+	return func(c *githubApiConfig) {
+		c.normalizeWhitespace = enabled
+	}
+}
+
+// WithSuppressRevertedInRange allows the caller to drop the notes of commits
+// which are reverted by another commit of the same range. The notes of the
+// reverting commits are dropped as well.
+func WithSuppressRevertedInRange() GithubApiOption {
+	return func(c *githubApiConfig) {
+		c.suppressRevertedInRange = true
+	}
+}
+
+// RevertedCommits returns the commits of the list which are reverted by
+// another commit of the list, as detected by the "This reverts commit <sha>"
+// line git adds to the message of a revert. The result maps the SHA of the
+// reverted commit to the SHA of the revert.
+func RevertedCommits(commits []*github.RepositoryCommit) map[string]string {
+	exp := regexp.MustCompile(`(?m)^This reverts commit ([0-9a-f]{7,40})`)
+
+	result := map[string]string{}
+	for _, revert := range commits {
+		for _, match := range exp.FindAllStringSubmatch(revert.GetCommit().GetMessage(), -1) {
+			for _, commit := range commits {
+				if strings.HasPrefix(commit.GetSHA(), match[1]) && commit.GetSHA() != revert.GetSHA() {
+					result[commit.GetSHA()] = revert.GetSHA()
+				}
+			}
+		}
+	}
+	return result
+}
+
+// NoteID returns the stable identifier of the note of a PR, which is the
+// beginning of the SHA256 hash of "org/repo#number".
+func NoteID(org, repo string, number int) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%s#%d", org, repo, number)))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// WithWrapNotes allows the caller to soft-wrap the markdown of every note at
+// the provided column.
+func WithWrapNotes(column int) GithubApiOption {
+	return func(c *githubApiConfig) {
+		c.wrapColumn = column
+	}
+}
+
+// AuthorField selects which GitHub user of a commit is compared against the
+// required author.
+type AuthorField string
+
+const (
+	// AuthorFieldAuthor compares the author of the commit
+	AuthorFieldAuthor AuthorField = "author"
+
+	// AuthorFieldCommitter compares the committer of the commit, which is the
+	// only user set by some merge flows
+	AuthorFieldCommitter AuthorField = "committer"
+
+	// AuthorFieldEither accepts commits if either the author or the committer
+	// matches. This is the default.
+	AuthorFieldEither AuthorField = "either"
+)
+
+// WithAuthorField allows the caller to select which GitHub user of a commit
+// has to match the required author. By default, it is AuthorFieldEither.
+func WithAuthorField(field AuthorField) GithubApiOption {
+	return func(c *githubApiConfig) {
+		c.authorField = field
+	}
+}
+
+// WithRequiredAuthors allows the caller to accept the commits of any of the
+// provided logins, like the members of a team. If a required author is passed
+// to ListReleaseNotes as well, its commits are accepted too.
+func WithRequiredAuthors(logins []string) GithubApiOption {
+	return func(c *githubApiConfig) {
+		c.requiredAuthors = logins
+	}
+}
+
+// TeamMembers returns the sorted logins of the members of the team with the
+// provided slug within the organization. Listing the members of a team
+// requires a token with the read:org scope.
+func TeamMembers(client *github.Client, org, slug string, opts ...GithubApiOption) ([]string, error) {
+	c := configFromOpts(opts...)
+
+	team, _, err := client.Teams.GetTeamBySlug(c.ctx, org, slug)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error getting team %s/%s", org, slug)
+	}
+
+	lo := &github.TeamListTeamMembersOptions{
+		ListOptions: github.ListOptions{
+			Page:    1,
+			PerPage: 100,
+		},
+	}
+
+	logins := []string{}
+	for {
+		members, resp, err := client.Teams.ListTeamMembers(c.ctx, team.GetID(), lo)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error listing the members of team %s/%s", org, slug)
+		}
+		for _, member := range members {
+			logins = append(logins, member.GetLogin())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		lo.Page = resp.NextPage
+	}
+	sort.Strings(logins)
+	return logins, nil
+}
+
+// MatchesAuthor returns whether the GitHub login of the author, the committer
+// or either of them is the provided login.
+func MatchesAuthor(commit *github.RepositoryCommit, login string, field AuthorField) bool {
+	author := commit.GetAuthor().GetLogin() == login
+	committer := commit.GetCommitter().GetLogin() == login
+
+	switch field {
+	case AuthorFieldAuthor:
+		return author
+	case AuthorFieldCommitter:
+		return committer
+	default:
+		return author || committer
+	}
+}
+
 // ListReleaseNotes produces a list of fully contextualized release notes
 // starting from a given commit SHA and ending at starting a given commit SHA.
+// Errors with a known cause, like ErrRateLimited, are returned as an *Error.
 func ListReleaseNotes(
 	client *github.Client,
 	logger log.Logger,
@@ -163,32 +509,167 @@ func ListReleaseNotes(
 	requiredAuthor,
 	relVer string,
 	opts ...GithubApiOption,
-) (ReleaseNoteList, error) {
-	commits, err := ListCommitsWithNotes(client, logger, branch, start, end, opts...)
+) (_ ReleaseNoteList, err error) {
+	defer func() {
+		err = classifyError(err, nil)
+	}()
+	c := configFromOpts(opts...)
+
+	commits, err := ListCommits(client, branch, start, end, opts...)
 	if err != nil {
 		return nil, err
 	}
 
+	// reverts are detected on all commits of the range, because the reverting
+	// commits usually have no release notes themselves
+	suppressed := map[string]struct{}{}
+	if c.suppressRevertedInRange {
+		for reverted, revert := range RevertedCommits(commits) {
+			level.Info(logger).Log(
+				"msg", "suppressing the notes of a commit which was reverted within the range",
+				"sha", reverted,
+				"revert", revert,
+			)
+			suppressed[reverted] = struct{}{}
+			suppressed[revert] = struct{}{}
+		}
+	}
+
 	dedupeCache := map[string]struct{}{}
 	notes := make(ReleaseNoteList)
-	for _, commit := range commits {
 
-		if requiredAuthor != "" {
-			if commit.GetAuthor().GetLogin() != requiredAuthor {
+	// the checkpoint refers to the unfiltered commits, so that the PRs of the
+	// already processed commits are not fetched again by the filter below
+	if c.checkpointFile != "" {
+		checkpoint, err := ReadCheckpoint(c.checkpointFile)
+		if err != nil {
+			return nil, err
+		}
+		if checkpoint != nil {
+			first, err := resumeIndex(commits, checkpoint, start, end)
+			if err != nil {
+				return nil, err
+			}
+			commits = commits[first:]
+			notes = checkpoint.Notes
+			for _, note := range notes {
+				dedupeCache[note.Text] = struct{}{}
+			}
+			level.Info(logger).Log(
+				"msg", "resuming from checkpoint",
+				"file", c.checkpointFile,
+				"sha", checkpoint.Commit,
+				"remaining", len(commits),
+			)
+		}
+	}
+
+	if c.noteSource != NoteSourceConventional && c.noteSource != NoteSourceCommitBody {
+		commits, err = filterCommitsWithNotes(client, logger, commits, opts...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := 0; i < len(commits); i++ {
+		commit := commits[i]
+		sendProgress(c, Progress{Stage: ProgressNotes, Commits: len(commits), Processed: i, Notes: len(notes)})
+
+		// all commits before the current one are processed at this point
+		if c.checkpointFile != "" && i > 0 && i%c.checkpointInterval == 0 {
+			if err := WriteCheckpoint(c.checkpointFile, &Checkpoint{
+				Start:  start,
+				End:    end,
+				Commit: commits[i-1].GetSHA(),
+				Notes:  notes,
+			}); err != nil {
+				return nil, err
+			}
+			level.Debug(logger).Log("msg", "flushed checkpoint", "sha", commits[i-1].GetSHA())
+		}
+
+		if requiredAuthor != "" || len(c.requiredAuthors) > 0 {
+			matches := requiredAuthor != "" && MatchesAuthor(commit, requiredAuthor, c.authorField)
+			for _, login := range c.requiredAuthors {
+				matches = matches || MatchesAuthor(commit, login, c.authorField)
+			}
+			if !matches {
+				traceCommit(logger, c, TraceDecisions, commit.GetSHA(), "skipped: not authored by a required author",
+					"author", commit.GetAuthor().GetLogin(), "committer", commit.GetCommitter().GetLogin())
 				continue
 			}
+			traceCommit(logger, c, TraceChecks, commit.GetSHA(), "authored by a required author",
+				"author", commit.GetAuthor().GetLogin(), "committer", commit.GetCommitter().GetLogin())
 		}
 
-		note, err := ReleaseNoteFromCommit(commit, client, logger, relVer, opts...)
+		if _, ok := suppressed[commit.GetSHA()]; ok {
+			traceCommit(logger, c, TraceDecisions, commit.GetSHA(), "skipped: reverted within the range")
+			continue
+		}
+
+		var note *ReleaseNote
+		switch c.noteSource {
+		case NoteSourceConventional:
+			note, err = ReleaseNoteFromConventionalCommit(commit, client, logger, relVer, opts...)
+			if err != nil {
+				level.Debug(logger).Log(
+					"err", err,
+					"msg", "skipping commit which is not a release note worthy conventional commit",
+					"sha", commit.GetSHA(),
+				)
+				traceCommit(logger, c, TraceDecisions, commit.GetSHA(), "skipped: not a release note worthy conventional commit")
+				continue
+			}
+		case NoteSourceCommitBody:
+			note, err = ReleaseNoteFromCommitBody(commit, client, logger, relVer, opts...)
+			if err == errNoCommitBodyNote {
+				level.Debug(logger).Log(
+					"msg", "skipping commit without a release note in its message",
+					"sha", commit.GetSHA(),
+				)
+				traceCommit(logger, c, TraceDecisions, commit.GetSHA(), "skipped: no release note in the commit message")
+				continue
+			}
+		default:
+			note, err = ReleaseNoteFromCommit(commit, client, logger, relVer, opts...)
+			if errors.Cause(err) == errPRNotMerged {
+				level.Info(logger).Log(
+					"msg", "skipping commit without a merged PR",
+					"sha", commit.GetSHA(),
+				)
+				c.warnings.Add(Warning{Commit: commit.GetSHA(), Category: WarningSkipped, Message: "the PR of the commit is not merged"})
+				traceCommit(logger, c, TraceDecisions, commit.GetSHA(), "skipped: the PR is not merged")
+				continue
+			}
+		}
 		if err != nil {
 			level.Error(logger).Log(
 				"err", err,
 				"msg", "error getting the release note from commit while listing release notes",
 				"sha", commit.GetSHA(),
 			)
+			c.warnings.Add(Warning{Commit: commit.GetSHA(), Category: WarningMalformedNote, Message: err.Error()})
+			traceCommit(logger, c, TraceDecisions, commit.GetSHA(), "skipped: error getting the release note")
 			continue
 		}
 
+		note.ID = NoteID(c.org, c.repo, note.PrNumber)
+		note.CoAuthors = CoAuthorsFromCommitMessage(commit.GetCommit().GetMessage())
+
+		if resolveSeeTitle(note, c.seeTitlePhrases) {
+			traceCommit(logger, c, TraceChecks, commit.GetSHA(), "the note refers to the PR title", "pr", note.PrNumber)
+			note.Markdown = NoteMarkdown(note, opts...)
+		}
+
+		if c.normalizeWhitespace {
+			note.Text = NormalizeWhitespace(note.Text)
+			note.Markdown = NormalizeWhitespace(note.Markdown)
+		}
+
+		if c.wrapColumn > 0 {
+			note.Markdown = WrapText(note.Markdown, c.wrapColumn)
+		}
+
 		// exclusionFilters is a list of regular expressions that match notes text that
 		// are deemed to have no content and should NOT be added to release notes.
 		exclusionFilters := []string{
@@ -212,12 +693,62 @@ func ListReleaseNotes(
 			}
 		}
 		if excluded {
+			traceCommit(logger, c, TraceDecisions, commit.GetSHA(), "excluded: the note has no content", "pr", note.PrNumber)
+			continue
+		}
+
+		if c.excludeDocs && isDocsNote(client, logger, note, opts...) {
+			level.Debug(logger).Log(
+				"msg", "excluding the note of a documentation only PR",
+				"sha", commit.GetSHA(),
+				"pr", note.PrNumber,
+			)
+			traceCommit(logger, c, TraceDecisions, commit.GetSHA(), "excluded: documentation only PR", "pr", note.PrNumber)
+			continue
+		}
+
+		if !matchesPathFilter(client, logger, note, opts...) {
+			level.Debug(logger).Log(
+				"msg", "excluding the note of a PR which changes no file matching the path filter",
+				"sha", commit.GetSHA(),
+				"pr", note.PrNumber,
+			)
+			traceCommit(logger, c, TraceDecisions, commit.GetSHA(), "excluded: no file matches the path filter", "pr", note.PrNumber)
+			continue
+		}
+
+		note.Reactions = noteReactions(client, logger, note.PrNumber, opts...)
+		if note.Stats == nil {
+			note.Stats = noteStats(client, logger, note.PrNumber, opts...)
+		}
+
+		// identical notes are collapsed after all notes are known
+		if c.dedupeIdenticalText {
+			traceCommit(logger, c, TraceDecisions, commit.GetSHA(), "included",
+				"pr", note.PrNumber, "kinds", strings.Join(note.Kinds, ","), "sigs", strings.Join(note.SIGs, ","))
+			notes[note.PrNumber] = note
 			continue
 		}
 
 		if _, ok := dedupeCache[note.Text]; !ok {
+			traceCommit(logger, c, TraceDecisions, commit.GetSHA(), "included",
+				"pr", note.PrNumber, "kinds", strings.Join(note.Kinds, ","), "sigs", strings.Join(note.SIGs, ","))
 			notes[note.PrNumber] = note
 			dedupeCache[note.Text] = struct{}{}
+		} else {
+			traceCommit(logger, c, TraceDecisions, commit.GetSHA(), "skipped: a note with the same text exists", "pr", note.PrNumber)
+		}
+	}
+
+	if c.dedupeIdenticalText {
+		notes = DedupeIdenticalText(notes)
+	}
+
+	sendProgress(c, Progress{Stage: ProgressNotes, Commits: len(commits), Processed: len(commits), Notes: len(notes)})
+
+	if c.checkpointFile != "" {
+		if err := os.Remove(c.checkpointFile); err != nil && !os.IsNotExist(err) {
+			return nil, errors.Wrap(err, "removing checkpoint")
 		}
 	}
 
@@ -260,6 +791,113 @@ func NoteTextFromString(s string) (string, error) {
 	return "", errors.New("no matches found when parsing note text from commit string")
 }
 
+// DescriptionFromString returns the first paragraph of a PR body, ignoring
+// HTML comments as well as the release-note and docs stanzas. The result is
+// truncated to maxChars characters if maxChars is greater than zero.
+func DescriptionFromString(s string, maxChars int) string {
+	exps := []*regexp.Regexp{
+		regexp.MustCompile("(?s)<!--.*?-->"),
+		regexp.MustCompile("(?s)```.*?```"),
+	}
+	for _, exp := range exps {
+		s = exp.ReplaceAllString(s, "")
+	}
+	s = strings.ReplaceAll(s, "\r", "")
+
+	paragraph := []string{}
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			if len(paragraph) > 0 {
+				break
+			}
+			continue
+		}
+		paragraph = append(paragraph, line)
+	}
+	description := strings.Join(paragraph, " ")
+
+	if runes := []rune(description); maxChars > 0 && len(runes) > maxChars {
+		description = strings.TrimSpace(string(runes[:maxChars])) + "..."
+	}
+	return description
+}
+
+// NormalizeWhitespace normalizes CRLF line endings to LF, trims trailing
+// whitespace from every line and collapses internal runs of spaces and tabs
+// into a single space. Leading indentation, inline code spans and fenced code
+// blocks are left untouched.
+func NormalizeWhitespace(s string) string {
+	spaces := regexp.MustCompile(`[ \t]{2,}|\t`)
+
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	fenced := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fenced = !fenced
+			lines[i] = strings.TrimRight(line, " \t")
+			continue
+		}
+		if fenced {
+			continue
+		}
+
+		content := strings.TrimLeft(line, " \t")
+		indent := line[:len(line)-len(content)]
+
+		// odd segments are within `code spans`
+		segments := strings.Split(strings.TrimRight(content, " \t"), "`")
+		for j := 0; j < len(segments); j += 2 {
+			segments[j] = spaces.ReplaceAllString(segments[j], " ")
+		}
+		lines[i] = indent + strings.Join(segments, "`")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// WrapText soft-wraps every line of the text which is longer than column
+// runes at spaces. Continuation lines are indented like the wrapped line, or
+// by two spaces if the wrapped line has no indentation, which continues a
+// markdown list item. Words longer than the column and fenced code blocks are
+// not wrapped.
+func WrapText(s string, column int) string {
+	lines := strings.Split(s, "\n")
+	result := []string{}
+	fenced := false
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fenced = !fenced
+		}
+		if fenced || utf8.RuneCountInString(line) <= column {
+			result = append(result, line)
+			continue
+		}
+
+		content := strings.TrimLeft(line, " ")
+		indent := line[:len(line)-len(content)]
+		continuation := indent
+		if continuation == "" {
+			continuation = "  "
+		}
+
+		current, prefix := "", indent
+		for _, word := range strings.Split(content, " ") {
+			if current == "" {
+				current = prefix + word
+				continue
+			}
+			if utf8.RuneCountInString(current)+1+utf8.RuneCountInString(word) > column {
+				result = append(result, current)
+				current, prefix = continuation+word, continuation
+				continue
+			}
+			current += " " + word
+		}
+		result = append(result, current)
+	}
+	return strings.Join(result, "\n")
+}
+
 func DocumentationFromString(s string) []*Documentation {
 	regex := regexp.MustCompile("(?s)```docs[\\r]?\\n(?P<text>.+)[\\r]?\\n```")
 	match := regex.FindStringSubmatch(s)
@@ -334,47 +972,127 @@ func ReleaseNoteFromCommit(commit *github.RepositoryCommit, client *github.Clien
 	}
 	documentation := DocumentationFromString(prBody)
 
+	description := ""
+	if c.includeDescription {
+		description = DescriptionFromString(prBody, c.descriptionMaxChars)
+	}
+
 	author := pr.GetUser().GetLogin()
 	authorUrl := fmt.Sprintf("https://github.com/%s", author)
 	prUrl := fmt.Sprintf("https://github.com/%s/%s/pull/%d", c.org, c.repo, pr.GetNumber())
-	IsFeature := HasString(LabelsWithPrefix(pr, "kind"), "feature")
-	IsDuplicate := false
-	sigsListPretty := prettifySigList(LabelsWithPrefix(pr, "sig"))
-	noteSuffix := ""
-
-	if IsActionRequired(pr) || IsFeature {
-		if sigsListPretty != "" {
-			noteSuffix = fmt.Sprintf("Courtesy of %s", sigsListPretty)
-		}
-	} else if len(LabelsWithPrefix(pr, "sig")) > 1 {
-		IsDuplicate = true
+	sigs := LabelsWithPrefix(pr, "sig")
+	if len(sigs) == 0 {
+		sigs = inferredSIGs(client, logger, pr.GetNumber(), opts...)
 	}
+	kinds, kindInferred := inferredKinds(LabelsWithPrefix(pr, "kind"), pr.GetTitle(), opts...)
+	IsFeature := HasString(kinds, "feature")
+	IsDuplicate := !IsActionRequired(pr) && !IsFeature && len(sigs) > 1
 
-	indented := strings.ReplaceAll(text, "\n", "\n  ")
-	markdown := fmt.Sprintf("%s ([#%d](%s), [@%s](%s))",
-		indented, pr.GetNumber(), prUrl, author, authorUrl)
+	branches := trackedBranches(client, logger, commit.GetSHA(), opts...)
 
-	if noteSuffix != "" {
-		markdown = fmt.Sprintf("%s\n\n  %s", markdown, noteSuffix)
-	}
-
-	return &ReleaseNote{
+	note := &ReleaseNote{
 		Commit:         commit.GetSHA(),
 		Text:           text,
-		Markdown:       markdown,
 		Documentation:  documentation,
 		Author:         author,
 		AuthorUrl:      authorUrl,
 		PrUrl:          prUrl,
 		PrNumber:       pr.GetNumber(),
-		SIGs:           LabelsWithPrefix(pr, "sig"),
-		Kinds:          LabelsWithPrefix(pr, "kind"),
+		PrTitle:        pr.GetTitle(),
+		SIGs:           sigs,
+		Kinds:          kinds,
+		KindInferred:   kindInferred,
 		Areas:          LabelsWithPrefix(pr, "area"),
 		Feature:        IsFeature,
 		Duplicate:      IsDuplicate,
 		ActionRequired: IsActionRequired(pr),
+		IsSecurity:     IsSecurity(pr, c.securityLabels),
+		Dependency:     IsDependencyBump(labelNames(pr), pr.GetTitle()),
+		Description:    description,
+		Branches:       branches,
+		RelatedIssues:  IssueReferencesFromString(prBody, c.org, c.repo),
+		MergedAt:       pr.GetMergedAt(),
+		UpdatedAt:      pr.UpdatedAt,
 		ReleaseVersion: relVer,
-	}, nil
+	}
+	if c.fetchStats {
+		note.Stats = statsFromPR(pr)
+	}
+	if c.includeSubtasks {
+		note.Subtasks = SubtasksFromString(prBody)
+	}
+	note.Markdown = NoteMarkdown(note, opts...)
+	return note, nil
+}
+
+// NoteMarkdown renders the markdown of a note from its text and metadata. The
+// text is followed by links to the PR and its author, the tracked branches,
+// the description and the related issues, if requested by the options, and
+// the SIGs of features and notes which require an action.
+func NoteMarkdown(note *ReleaseNote, opts ...GithubApiOption) string {
+	c := configFromOpts(opts...)
+
+	markdown := noteMarkdown(note.Text, note.PrNumber, note.PrUrl, note.Author, note.AuthorUrl, note.Branches)
+
+	if note.Description != "" {
+		markdown = fmt.Sprintf("%s\n  - %s", markdown, note.Description)
+	}
+
+	if c.linkIssues && len(note.RelatedIssues) > 0 {
+		markdown = fmt.Sprintf("%s\n\n  %s", markdown, issuesSuffix(note.RelatedIssues, c.org, c.repo))
+	}
+
+	if note.ActionRequired || note.Feature {
+		if sigsListPretty := prettifySigList(note.SIGs); sigsListPretty != "" {
+			markdown = fmt.Sprintf("%s\n\n  Courtesy of %s", markdown, sigsListPretty)
+		}
+	}
+	return markdown
+}
+
+// RegenerateMarkdown renders the markdown of a note again from its fields,
+// like after its text was edited, with the same steps as ListReleaseNotes:
+// the "See PR title" substitution, whitespace normalization, wrapping and the
+// links to the other PRs of a collapsed note.
+func RegenerateMarkdown(note *ReleaseNote, opts ...GithubApiOption) string {
+	c := configFromOpts(opts...)
+
+	resolveSeeTitle(note, c.seeTitlePhrases)
+	if c.normalizeWhitespace {
+		note.Text = NormalizeWhitespace(note.Text)
+	}
+
+	markdown := NoteMarkdown(note, opts...)
+	if c.normalizeWhitespace {
+		markdown = NormalizeWhitespace(markdown)
+	}
+	if c.wrapColumn > 0 {
+		markdown = WrapText(markdown, c.wrapColumn)
+	}
+
+	if len(note.PrNumbers) > 1 {
+		// The collapsed PRs are in the repository of the note, so their URLs
+		// only differ in the number.
+		base := strings.TrimSuffix(note.PrUrl, strconv.Itoa(note.PrNumber))
+		markdown = alsoInMarkdown(markdown, note.PrNumbers[1:], func(number int) string {
+			return base + strconv.Itoa(number)
+		})
+	}
+	return markdown
+}
+
+// noteMarkdown renders the first part of the markdown of a note, which is the
+// note text followed by links to the PR and its author as well as the tracked
+// branches containing the note, if any.
+func noteMarkdown(text string, number int, prUrl, author, authorUrl string, branches []string) string {
+	indented := strings.ReplaceAll(text, "\n", "\n  ")
+	markdown := fmt.Sprintf("%s ([#%d](%s), [@%s](%s))",
+		indented, number, prUrl, author, authorUrl)
+
+	if len(branches) > 0 {
+		markdown = fmt.Sprintf("%s %s", markdown, branchesSuffix(branches))
+	}
+	return markdown
 }
 
 // ListCommits lists all commits starting from a given commit SHA and ending at
@@ -384,14 +1102,18 @@ func ListCommits(client *github.Client, branch, start, end string, opts ...Githu
 
 	c.branch = branch
 
+	if err := checkRangeCommits(client, start, end, c); err != nil {
+		return nil, err
+	}
+
 	startCommit, _, err := client.Git.GetCommit(c.ctx, c.org, c.repo, start)
 	if err != nil {
-		return nil, err
+		return nil, classifyError(errors.Wrapf(err, "error getting the start commit %s", start), ErrInvalidRange)
 	}
 
 	endCommit, _, err := client.Git.GetCommit(c.ctx, c.org, c.repo, end)
 	if err != nil {
-		return nil, err
+		return nil, classifyError(errors.Wrapf(err, "error getting the end commit %s", end), ErrInvalidRange)
 	}
 
 	clo := &github.CommitsListOptions{
@@ -406,16 +1128,23 @@ func ListCommits(client *github.Client, branch, start, end string, opts ...Githu
 
 	commits, resp, err := client.Repositories.ListCommits(c.ctx, c.org, c.repo, clo)
 	if err != nil {
-		return nil, err
+		return nil, classifyError(errors.Wrapf(err, "error listing the commits of branch %s", c.branch), ErrBranchNotFound)
 	}
+	// the last page is not set if there is only one
+	pages := resp.LastPage
+	if pages == 0 {
+		pages = 1
+	}
+	sendProgress(c, Progress{Stage: ProgressCommits, Page: clo.ListOptions.Page, Pages: pages, Commits: len(commits)})
 	clo.ListOptions.Page++
 
 	for clo.ListOptions.Page <= resp.LastPage {
 		commitPage, _, err := client.Repositories.ListCommits(c.ctx, c.org, c.repo, clo)
 		if err != nil {
-			return nil, err
+			return nil, classifyError(err, nil)
 		}
 		commits = append(commits, commitPage...)
+		sendProgress(c, Progress{Stage: ProgressCommits, Page: clo.ListOptions.Page, Pages: pages, Commits: len(commits)})
 		clo.ListOptions.Page++
 	}
 
@@ -434,13 +1163,25 @@ func ListCommitsWithNotes(
 	end string,
 	opts ...GithubApiOption,
 ) ([]*github.RepositoryCommit, error) {
-	filteredCommits := []*github.RepositoryCommit{}
-
 	commits, err := ListCommits(client, branch, start, end, opts...)
 	if err != nil {
 		return nil, err
 	}
 
+	return filterCommitsWithNotes(client, logger, commits, opts...)
+}
+
+// filterCommitsWithNotes returns the commits whose PRs have tagged release
+// notes
+func filterCommitsWithNotes(
+	client *github.Client,
+	logger log.Logger,
+	commits []*github.RepositoryCommit,
+	opts ...GithubApiOption,
+) ([]*github.RepositoryCommit, error) {
+	c := configFromOpts(opts...)
+	filteredCommits := []*github.RepositoryCommit{}
+
 	for i, commit := range commits {
 
 		level.Debug(logger).Log("msg", "################################################")
@@ -458,9 +1199,21 @@ func ListCommitsWithNotes(
 					"msg", fmt.Sprintf("No matches found when parsing PR from commit sha '%s'.", commit.GetSHA()),
 					"func", "ListCommitsWithNotes",
 				)
+				traceCommit(logger, c, TraceDecisions, commit.GetSHA(), "skipped: no PR found for the commit")
 				continue
 			}
 		}
+		switch {
+		case errors.Cause(err) == errPRNotMerged:
+			traceCommit(logger, c, TraceDecisions, commit.GetSHA(), "skipped: the PR is not merged")
+			c.warnings.Add(Warning{Commit: commit.GetSHA(), Category: WarningSkipped, Message: "the PR of the commit is not merged"})
+		case err != nil:
+			traceCommit(logger, c, TraceDecisions, commit.GetSHA(), "skipped: error getting the PR of the commit")
+			c.warnings.Add(Warning{Commit: commit.GetSHA(), Category: WarningSkipped, Message: err.Error()})
+		default:
+			traceCommit(logger, c, TraceChecks, commit.GetSHA(), "found the PR of the commit",
+				"pr", pr.GetNumber(), "labels", strings.Join(labelNames(pr), ","))
+		}
 
 		level.Debug(logger).Log(
 			"msg", fmt.Sprintf("Obtaining PR associated with commit sha '%s'.", commit.GetSHA()),
@@ -506,6 +1259,7 @@ func ListCommitsWithNotes(
 		}
 
 		if excluded {
+			traceCommit(logger, c, TraceDecisions, commit.GetSHA(), "skipped: the PR has no release note", "pr", pr.GetNumber())
 			continue
 		}
 
@@ -516,6 +1270,7 @@ func ListCommitsWithNotes(
 			"Does this PR introduce a user-facing change?",
 		}
 
+		included := false
 		for _, filter := range inclusionFilters {
 			match, err := regexp.MatchString(filter, pr.GetBody())
 			if err != nil {
@@ -528,13 +1283,22 @@ func ListCommitsWithNotes(
 					"func", "ListCommitsWithNotes",
 					"filter", filter,
 				)
+				traceCommit(logger, c, TraceChecks, commit.GetSHA(), "the PR has a release note block", "pr", pr.GetNumber(), "filter", filter)
+				included = true
 			}
 		}
+		if !included && err == nil {
+			traceCommit(logger, c, TraceDecisions, commit.GetSHA(), "skipped: the PR has no release note block", "pr", pr.GetNumber())
+		}
 	}
 
 	return filteredCommits, nil
 }
 
+// errPRNotMerged is returned if the PR associated with a commit is not merged
+// and merged PRs are required
+var errPRNotMerged = errors.New("the PR associated with the commit is not merged")
+
 // PRFromCommit return an API Pull Request struct given a commit struct. This is
 // useful for going from a commit log to the PR (which contains useful info such
 // as labels).
@@ -548,7 +1312,13 @@ func PRFromCommit(client *github.Client, logger log.Logger, commit *github.Repos
 	// Given the PR number that we've now converted to an integer, get the PR from
 	// the API
 	pr, _, err := client.PullRequests.Get(c.ctx, c.org, c.repo, number)
-	return pr, err
+	if err != nil {
+		return nil, err
+	}
+	if c.requireMerged && !pr.GetMerged() {
+		return nil, errPRNotMerged
+	}
+	return pr, nil
 }
 
 // LabelsWithPrefix is a helper for fetching all labels on a PR that start with
@@ -576,6 +1346,28 @@ func IsActionRequired(pr *github.PullRequest) bool {
 	return false
 }
 
+// DefaultSecurityLabels are the labels of PRs with security related notes
+var DefaultSecurityLabels = []string{"kind/security", "area/security"}
+
+// WithSecurityLabels allows the caller to override the labels which mark the
+// notes of a PR as security related. By default, it is DefaultSecurityLabels.
+func WithSecurityLabels(labels []string) GithubApiOption {
+	return func(c *githubApiConfig) {
+		c.securityLabels = labels
+	}
+}
+
+// IsSecurity indicates whether or not one of the provided labels was set on
+// the PR.
+func IsSecurity(pr *github.PullRequest, labels []string) bool {
+	for _, label := range pr.Labels {
+		if HasString(labels, label.GetName()) {
+			return true
+		}
+	}
+	return false
+}
+
 // filterCommits is a helper that allows you to filter a set of commits by
 // applying a set of regular expressions over the commit messages. If include is
 // true, only commits that match at least one expression are returned. If include
@@ -633,6 +1425,15 @@ func configFromOpts(opts ...GithubApiOption) *githubApiConfig {
 		org:    "kubernetes",
 		repo:   "kubernetes",
 		branch: "master",
+
+		noteSource:  NoteSourceReleaseNote,
+		authorField: AuthorFieldEither,
+
+		securityLabels: DefaultSecurityLabels,
+
+		normalizeWhitespace: true,
+
+		checkpointInterval: DefaultCheckpointInterval,
 	}
 
 	for _, opt := range opts {
@@ -680,7 +1481,15 @@ func HasString(a []string, x string) bool {
 }
 
 func getPRNumberFromCommit(client *github.Client, logger log.Logger, commit *github.RepositoryCommit, opts ...GithubApiOption) (int, error) {
-	num, err := getPRNumberFromCommitMessage(*commit.Commit.Message)
+	c := configFromOpts(opts...)
+
+	var num int
+	var err error
+	if c.prNumberRegex != nil {
+		num, err = getPRNumberFromCommitMessageWithRegex(*commit.Commit.Message, c.prNumberRegex)
+	} else {
+		num, err = getPRNumberFromCommitMessage(*commit.Commit.Message)
+	}
 	if err != nil {
 		level.Debug(logger).Log(
 			"err", err,
@@ -696,11 +1505,16 @@ func getPRNumberFromCommit(client *github.Client, logger log.Logger, commit *git
 func getPRNumberFromCommitSHA(client *github.Client, sha string, opts ...GithubApiOption) (int, error) {
 	c := configFromOpts(opts...)
 
+	perPage := 1
+	if c.requireMerged {
+		// The first PR may be an unmerged one containing the same commit
+		perPage = 100
+	}
 	plo := &github.PullRequestListOptions{
 		State: "closed",
 		ListOptions: github.ListOptions{
 			Page:    1,
-			PerPage: 1,
+			PerPage: perPage,
 		},
 	}
 
@@ -708,8 +1522,14 @@ func getPRNumberFromCommitSHA(client *github.Client, sha string, opts ...GithubA
 	if err != nil {
 		return 0, err
 	}
-	if len(prs) > 0 {
-		return *prs[0].Number, nil
+	for _, pr := range prs {
+		if c.requireMerged && pr.MergedAt == nil {
+			continue
+		}
+		return pr.GetNumber(), nil
+	}
+	if c.requireMerged && len(prs) > 0 {
+		return 0, errPRNotMerged
 	}
 
 	return 0, errors.Errorf("no pr found for sha %s", sha)
@@ -743,3 +1563,20 @@ func getPRNumberFromCommitMessage(commitMessage string) (int, error) {
 	}
 	return number, nil
 }
+
+// getPRNumberFromCommitMessageWithRegex extracts the PR number from a commit
+// message using a custom regular expression. The number is taken from the
+// group named "number" or, if there is no such group, from the first group.
+func getPRNumberFromCommitMessageWithRegex(commitMessage string, exp *regexp.Regexp) (int, error) {
+	match := exp.FindStringSubmatch(commitMessage)
+	if len(match) < 2 {
+		return 0, errors.New("no matches found when parsing PR from commit")
+	}
+	number := match[1]
+	for i, name := range exp.SubexpNames() {
+		if name == "number" {
+			number = match[i]
+		}
+	}
+	return strconv.Atoi(number)
+}
@@ -3,10 +3,13 @@ package notes
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"net/url"
 	"os"
+	"regexp"
 	"testing"
 
+	"github.com/go-kit/kit/log"
 	"github.com/google/go-github/v27/github"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/oauth2"
@@ -50,6 +53,45 @@ func TestStripActionRequired(t *testing.T) {
 	}
 }
 
+func TestNormalizeWhitespace(t *testing.T) {
+	cases := map[string]string{
+		"trailing spaces   ":                     "trailing spaces",
+		"trailing tab\t":                         "trailing tab",
+		"internal   run of\t\tspaces":            "internal run of spaces",
+		"crlf\r\nline endings\r\n":               "crlf\nline endings\n",
+		"keep `code  span` as  is":               "keep `code  span` as is",
+		"first line\n  indented   line":          "first line\n  indented line",
+		"block:\n```\nfoo    bar  \n```\nend  x": "block:\n```\nfoo    bar  \n```\nend x",
+	}
+
+	for input, expected := range cases {
+		require.Equal(t, expected, NormalizeWhitespace(input))
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	cases := map[string]string{
+		"short note":                                  "short note",
+		"a note which is too long for the col":        "a note which is too\n  long for the col",
+		"émoji ünïcödé wörds äre counted":             "émoji ünïcödé wörds\n  äre counted",
+		"  indented continuation of the note":         "  indented\n  continuation of\n  the note",
+		"https://example.com/a-very-long-url":         "https://example.com/a-very-long-url",
+		"```\nfoo bar baz qux quux corge grault\n```": "```\nfoo bar baz qux quux corge grault\n```",
+	}
+
+	for input, expected := range cases {
+		require.Equal(t, expected, WrapText(input, 20))
+	}
+}
+
+func TestNoteID(t *testing.T) {
+	id := NoteID("kubernetes", "kubernetes", 1234)
+	require.Len(t, id, 16)
+	require.Equal(t, id, NoteID("kubernetes", "kubernetes", 1234))
+	require.NotEqual(t, id, NoteID("kubernetes", "kubernetes", 1235))
+	require.NotEqual(t, id, NoteID("kubernetes", "release", 1234))
+}
+
 func TestStripStar(t *testing.T) {
 	notes := []string{
 		"* The note text",
@@ -72,7 +114,7 @@ func TestReleaseNoteParsing(t *testing.T) {
 		fmt.Println(sha)
 		commit, _, err := client.Repositories.GetCommit(ctx, "kubernetes", "kubernetes", sha)
 		require.NoError(t, err)
-		_, err = ReleaseNoteFromCommit(commit, client, "0.1")
+		_, err = ReleaseNoteFromCommit(commit, client, log.NewNopLogger(), "0.1")
 		require.NoError(t, err)
 	}
 }
@@ -170,6 +212,42 @@ func TestDocumentationFromString(t *testing.T) {
 	require.Equal(t, url1, result[0].URL)
 }
 
+func TestDescriptionFromString(t *testing.T) {
+	body := "<!-- Thanks for sending a pull request! -->\r\n" +
+		"\r\n" +
+		"This PR adds a new flag\r\n" +
+		"to the kubelet.\r\n" +
+		"\r\n" +
+		"Second paragraph.\r\n" +
+		"```release-note\r\nAdded a new flag\r\n```"
+
+	require.Equal(t, "This PR adds a new flag to the kubelet.", DescriptionFromString(body, 0))
+	require.Equal(t, "This PR...", DescriptionFromString(body, 8))
+	require.Equal(t, "", DescriptionFromString("```release-note\nNONE\n```", 0))
+}
+
+func TestNoteMarkdown(t *testing.T) {
+	note := &ReleaseNote{
+		Text:           "Fixed a crash\nof the kubelet",
+		Author:         "jdoe",
+		AuthorUrl:      "https://github.com/jdoe",
+		PrUrl:          "https://github.com/kubernetes/kubernetes/pull/1",
+		PrNumber:       1,
+		SIGs:           []string{"node"},
+		ActionRequired: true,
+		Description:    "The kubelet crashed on startup.",
+		RelatedIssues:  []string{"https://github.com/kubernetes/kubernetes/issues/2"},
+	}
+
+	require.Equal(t,
+		"Fixed a crash\n  of the kubelet ([#1](https://github.com/kubernetes/kubernetes/pull/1), [@jdoe](https://github.com/jdoe))\n"+
+			"  - The kubelet crashed on startup.\n\n"+
+			"  Courtesy of SIG Node",
+		NoteMarkdown(note),
+	)
+	require.Contains(t, NoteMarkdown(note, WithLinkIssues()), "Related issues: [#2](https://github.com/kubernetes/kubernetes/issues/2)")
+}
+
 func TestClassifyURL(t *testing.T) {
 	// A KEP
 	url, err := url.Parse("http://github.com/kubernetes/enhancements/blob/master/keps/sig-cli/kubectl-staging.md")
@@ -223,3 +301,171 @@ func TestGetPRNumberFromCommitMessage(t *testing.T) {
 	}
 
 }
+
+func TestGetPRNumberFromCommitMessageWithRegex(t *testing.T) {
+	testCases := []struct {
+		name             string
+		commitMessage    string
+		regex            string
+		expectedPRNumber int
+	}{
+		{
+			name:             "Unnamed capture group",
+			commitMessage:    "Fix the build [PR-1234]",
+			regex:            `\[PR-(\d+)\]`,
+			expectedPRNumber: 1234,
+		},
+		{
+			name:             "Named capture group",
+			commitMessage:    "Fix the build (merge-bot: !42)",
+			regex:            `(merge-bot): !(?P<number>\d+)`,
+			expectedPRNumber: 42,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actualPRNumber, err := getPRNumberFromCommitMessageWithRegex(tc.commitMessage, regexp.MustCompile(tc.regex))
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedPRNumber, actualPRNumber)
+		})
+	}
+
+	_, err := getPRNumberFromCommitMessageWithRegex("Add swapoff to centos so kubelet starts (#504)", regexp.MustCompile(`\[PR-(\d+)\]`))
+	require.Error(t, err)
+}
+
+func TestRevertedCommits(t *testing.T) {
+	commit := func(sha, message string) *github.RepositoryCommit {
+		return &github.RepositoryCommit{
+			SHA:    github.String(sha),
+			Commit: &github.Commit{Message: github.String(message)},
+		}
+	}
+
+	original := commit("1111111111111111111111111111111111111111", "Merge pull request #1 from foo/bar")
+	revert := commit("2222222222222222222222222222222222222222",
+		"Revert \"Add the bar feature\"\n\nThis reverts commit 1111111111111111111111111111111111111111.")
+	abbreviatedRevert := commit("3333333333333333333333333333333333333333",
+		"Revert \"Add the bar feature\"\n\nThis reverts commit 1111111.")
+	unrelated := commit("4444444444444444444444444444444444444444", "Merge pull request #2 from foo/baz")
+
+	testCases := []struct {
+		name     string
+		commits  []*github.RepositoryCommit
+		expected map[string]string
+	}{
+		{
+			name:     "original and revert in range",
+			commits:  []*github.RepositoryCommit{original, unrelated, revert},
+			expected: map[string]string{original.GetSHA(): revert.GetSHA()},
+		},
+		{
+			name:     "original and abbreviated revert in range",
+			commits:  []*github.RepositoryCommit{original, abbreviatedRevert},
+			expected: map[string]string{original.GetSHA(): abbreviatedRevert.GetSHA()},
+		},
+		{
+			name:     "only the revert in range",
+			commits:  []*github.RepositoryCommit{unrelated, revert},
+			expected: map[string]string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, RevertedCommits(tc.commits))
+		})
+	}
+}
+
+func TestMatchesAuthor(t *testing.T) {
+	commit := func(author, committer string) *github.RepositoryCommit {
+		return &github.RepositoryCommit{
+			Author:    &github.User{Login: github.String(author)},
+			Committer: &github.User{Login: github.String(committer)},
+		}
+	}
+
+	byBot := commit("k8s-ci-robot", "k8s-ci-robot")
+	authoredByBot := commit("k8s-ci-robot", "web-flow")
+	committedByBot := commit("someone", "k8s-ci-robot")
+	byOthers := commit("someone", "web-flow")
+
+	testCases := []struct {
+		commit   *github.RepositoryCommit
+		field    AuthorField
+		expected bool
+	}{
+		{commit: byBot, field: AuthorFieldAuthor, expected: true},
+		{commit: byBot, field: AuthorFieldCommitter, expected: true},
+		{commit: byBot, field: AuthorFieldEither, expected: true},
+		{commit: authoredByBot, field: AuthorFieldAuthor, expected: true},
+		{commit: authoredByBot, field: AuthorFieldCommitter, expected: false},
+		{commit: authoredByBot, field: AuthorFieldEither, expected: true},
+		{commit: committedByBot, field: AuthorFieldAuthor, expected: false},
+		{commit: committedByBot, field: AuthorFieldCommitter, expected: true},
+		{commit: committedByBot, field: AuthorFieldEither, expected: true},
+		{commit: byOthers, field: AuthorFieldEither, expected: false},
+		{commit: &github.RepositoryCommit{}, field: AuthorFieldEither, expected: false},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, MatchesAuthor(tc.commit, "k8s-ci-robot", tc.field),
+			"author %s, committer %s, field %s", tc.commit.GetAuthor().GetLogin(), tc.commit.GetCommitter().GetLogin(), tc.field)
+	}
+}
+
+func TestIsSecurity(t *testing.T) {
+	pr := func(labels ...string) *github.PullRequest {
+		pr := &github.PullRequest{}
+		for _, label := range labels {
+			pr.Labels = append(pr.Labels, &github.Label{Name: github.String(label)})
+		}
+		return pr
+	}
+
+	require.True(t, IsSecurity(pr("sig/auth", "kind/security"), DefaultSecurityLabels))
+	require.True(t, IsSecurity(pr("area/security"), DefaultSecurityLabels))
+	require.False(t, IsSecurity(pr("sig/auth", "kind/bug"), DefaultSecurityLabels))
+	require.False(t, IsSecurity(pr(), DefaultSecurityLabels))
+	require.True(t, IsSecurity(pr("cve"), []string{"cve"}))
+	require.False(t, IsSecurity(pr("kind/security"), []string{"cve"}))
+}
+
+func TestTeamMembers(t *testing.T) {
+	client, server := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/orgs/kubernetes/teams/release-bots":
+			fmt.Fprint(w, `{"id": 7, "slug": "release-bots"}`)
+		case "/teams/7/members":
+			fmt.Fprint(w, `[{"login": "release-bot"}, {"login": "k8s-ci-robot"}]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	members, err := TeamMembers(client, "kubernetes", "release-bots")
+	require.Nil(t, err)
+	require.Equal(t, []string{"k8s-ci-robot", "release-bot"}, members)
+
+	_, err = TeamMembers(client, "kubernetes", "missing")
+	require.NotNil(t, err)
+}
+
+func TestListReleaseNotesRequireMerged(t *testing.T) {
+	client, server := newFixtureClient(t, "require-merged", requireMergedFixtures)
+	defer server.Close()
+
+	notes, err := ListReleaseNotes(client, log.NewNopLogger(), "master", "start", "end", "", "", WithRequireMerged())
+	require.Nil(t, err)
+	require.Len(t, notes, 1)
+	require.Equal(t, "Fixed the kubelet restart loop", notes[2].Text)
+
+	notes, err = ListReleaseNotes(client, log.NewNopLogger(), "master", "start", "end", "", "")
+	require.Nil(t, err)
+	require.Len(t, notes, 2)
+	require.Equal(t, "Note of the unmerged PR", notes[1].Text)
+	require.Equal(t, "Note of the draft", notes[3].Text)
+}
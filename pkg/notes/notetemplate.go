@@ -0,0 +1,92 @@
+package notes
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// NoteTemplateData is the data a note template is executed with
+type NoteTemplateData struct {
+	// Number and URL identify the PR of the note
+	Number int
+	URL    string
+
+	// Text is the plain note text and Markdown the default rendering of it
+	Text     string
+	Markdown string
+
+	Author    string
+	AuthorURL string
+
+	SIGs  []string
+	Kinds []string
+
+	// Kind is the first of the kinds, empty if the note has none
+	Kind string
+}
+
+// noteTemplateData returns the data a note template is executed with
+func noteTemplateData(note *ReleaseNote) *NoteTemplateData {
+	data := &NoteTemplateData{
+		Number:    note.PrNumber,
+		URL:       note.PrUrl,
+		Text:      note.Text,
+		Markdown:  note.Markdown,
+		Author:    note.Author,
+		AuthorURL: note.AuthorUrl,
+		SIGs:      note.SIGs,
+		Kinds:     note.Kinds,
+	}
+	if len(note.Kinds) > 0 {
+		data.Kind = note.Kinds[0]
+	}
+	return data
+}
+
+// ParseNoteTemplate parses a Go template which renders a single note, like
+// "{{.Text}} (#{{.Number}}, @{{.Author}})". The template is executed once
+// against an example note, so that references to unknown fields are reported
+// here instead of while rendering.
+func ParseNoteTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("note").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing note template")
+	}
+
+	example := &ReleaseNote{
+		Text:      "Example note",
+		Markdown:  "Example note",
+		Author:    "octocat",
+		AuthorUrl: "https://github.com/octocat",
+		PrUrl:     "https://github.com/kubernetes/kubernetes/pull/1",
+		PrNumber:  1,
+		SIGs:      []string{"release"},
+		Kinds:     []string{"feature"},
+	}
+	if err := tmpl.Execute(&bytes.Buffer{}, noteTemplateData(example)); err != nil {
+		return nil, errors.Wrap(err, "validating note template")
+	}
+	return tmpl, nil
+}
+
+// WithNoteTemplate allows the caller to render every note with a template
+// returned by ParseNoteTemplate instead of its default markdown. The document
+// structure, like the sections and headings, stays the same.
+func WithNoteTemplate(tmpl *template.Template) DocumentOption {
+	return func(c *documentConfig) {
+		c.noteTemplate = tmpl
+	}
+}
+
+// executeNoteTemplate renders a note with the template. Line breaks are
+// replaced by spaces, so that the note remains a single list item.
+func executeNoteTemplate(tmpl *template.Template, note *ReleaseNote) (string, error) {
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, noteTemplateData(note)); err != nil {
+		return "", errors.Wrapf(err, "rendering note of PR #%d", note.PrNumber)
+	}
+	return strings.ReplaceAll(strings.TrimSpace(buf.String()), "\n", " "), nil
+}
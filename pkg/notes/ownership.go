@@ -0,0 +1,134 @@
+package notes
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/google/go-github/v27/github"
+)
+
+// WithSIGOwnership allows the caller to provide a mapping of directory
+// prefixes to the SIGs owning them, like "pkg/kubelet/" to "node". The SIGs of
+// PRs without any sig label are inferred from the files they change, which
+// costs additional API requests.
+func WithSIGOwnership(owners map[string]string) GithubApiOption {
+	return func(c *githubApiConfig) {
+		c.sigOwners = owners
+	}
+}
+
+// WithInferSIGBudget allows the caller to limit the SIG inference of
+// WithSIGOwnership to the first limit PRs without sig labels. The notes of the
+// remaining PRs are left uncategorized, which keeps the inference from using up
+// the rate limit on large ranges. The budget is shared by all calls which are
+// passed the same option.
+func WithInferSIGBudget(limit int) GithubApiOption {
+	budget := &inferenceBudget{remaining: limit}
+	return func(c *githubApiConfig) {
+		c.sigBudget = budget
+	}
+}
+
+// inferenceBudget counts down the PRs whose SIGs may still be inferred
+type inferenceBudget struct {
+	mu        sync.Mutex
+	remaining int
+	exhausted bool
+}
+
+// take consumes one inference from the budget. The second value is true only
+// for the first refused inference, so that the exhaustion is logged once.
+func (b *inferenceBudget) take() (ok, exhausted bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.remaining > 0 {
+		b.remaining--
+		return true, false
+	}
+	if !b.exhausted {
+		b.exhausted = true
+		return false, true
+	}
+	return false, false
+}
+
+// SIGsFromFiles returns the sorted SIGs owning the provided files. Every file
+// is owned by the SIG with the longest matching directory prefix, files
+// without a match are ignored.
+func SIGsFromFiles(files []string, owners map[string]string) []string {
+	sigs := []string{}
+	for _, file := range files {
+		owner, longest := "", -1
+		for prefix, sig := range owners {
+			if strings.HasPrefix(file, prefix) && len(prefix) > longest {
+				owner, longest = sig, len(prefix)
+			}
+		}
+		if owner != "" && !HasString(sigs, owner) {
+			sigs = append(sigs, owner)
+		}
+	}
+	sort.Strings(sigs)
+	return sigs
+}
+
+// ListPRFiles returns the paths of all files changed by the PR with the
+// provided number.
+func ListPRFiles(client *github.Client, number int, opts ...GithubApiOption) ([]string, error) {
+	c := configFromOpts(opts...)
+
+	lo := &github.ListOptions{
+		Page:    1,
+		PerPage: 100,
+	}
+
+	files := []string{}
+	for {
+		page, resp, err := client.PullRequests.ListFiles(c.ctx, c.org, c.repo, number, lo)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range page {
+			files = append(files, file.GetFilename())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		lo.Page = resp.NextPage
+	}
+	return files, nil
+}
+
+// inferredSIGs returns the SIGs owning the files changed by a PR if a SIG
+// ownership mapping was provided. Failures are logged and result in no SIGs,
+// so that the note is listed as uncategorized.
+func inferredSIGs(client *github.Client, logger log.Logger, number int, opts ...GithubApiOption) []string {
+	c := configFromOpts(opts...)
+	if len(c.sigOwners) == 0 {
+		return nil
+	}
+
+	if c.sigBudget != nil {
+		ok, exhausted := c.sigBudget.take()
+		if exhausted {
+			warnPR(logger, c, WarningBudgetExhausted, number, "SIG inference budget exhausted, the notes of the remaining PRs without sig labels are uncategorized", nil)
+		}
+		if !ok {
+			return nil
+		}
+	}
+
+	files, err := ListPRFiles(client, number, opts...)
+	if err != nil {
+		warnPR(logger, c, WarningFetchFailed, number, "error listing the files of the PR to infer its SIGs", err)
+		return nil
+	}
+
+	sigs := SIGsFromFiles(files, c.sigOwners)
+	level.Debug(logger).Log("msg", "inferred SIGs from changed files", "pr", number, "sigs", strings.Join(sigs, ","))
+	return sigs
+}
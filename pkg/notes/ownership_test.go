@@ -0,0 +1,65 @@
+package notes
+
+import (
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSIGsFromFiles(t *testing.T) {
+	owners := map[string]string{
+		"pkg/kubelet/":                    "node",
+		"pkg/kubelet/cm/":                 "node",
+		"pkg/scheduler/":                  "scheduling",
+		"staging/src/k8s.io/apiserver/":   "api-machinery",
+		"staging/src/k8s.io/apiserver/x/": "auth",
+	}
+
+	testCases := []struct {
+		files    []string
+		expected []string
+	}{
+		{files: []string{"README.md"}, expected: []string{}},
+		{files: []string{"pkg/kubelet/kubelet.go", "pkg/kubelet/cm/cm.go"}, expected: []string{"node"}},
+		{files: []string{"pkg/scheduler/a.go", "pkg/kubelet/b.go", "docs/c.md"}, expected: []string{"node", "scheduling"}},
+		{files: []string{"staging/src/k8s.io/apiserver/x/token.go"}, expected: []string{"auth"}},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, SIGsFromFiles(tc.files, owners))
+	}
+}
+
+func TestInferSIGBudget(t *testing.T) {
+	c := configFromOpts(WithInferSIGBudget(2))
+
+	ok, exhausted := c.sigBudget.take()
+	require.True(t, ok)
+	require.False(t, exhausted)
+
+	// the budget is shared between configurations built from the same option
+	c = configFromOpts(WithInferSIGBudget(0))
+	ok, exhausted = c.sigBudget.take()
+	require.False(t, ok)
+	require.True(t, exhausted)
+
+	ok, exhausted = c.sigBudget.take()
+	require.False(t, ok)
+	require.False(t, exhausted)
+
+	opt := WithInferSIGBudget(1)
+	ok, _ = configFromOpts(opt).sigBudget.take()
+	require.True(t, ok)
+	ok, _ = configFromOpts(opt).sigBudget.take()
+	require.False(t, ok)
+}
+
+func TestInferredSIGsWithoutBudget(t *testing.T) {
+	// no API request is made once the budget is exhausted
+	sigs := inferredSIGs(nil, log.NewNopLogger(), 1,
+		WithSIGOwnership(map[string]string{"pkg/": "node"}),
+		WithInferSIGBudget(0),
+	)
+	require.Nil(t, sigs)
+}
@@ -0,0 +1,218 @@
+package notes
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// minorSeries matches the versions of a minor series, like v1.20, v1.20.3 or
+// v1.20.x, capturing the major and minor numbers
+var minorSeries = regexp.MustCompile(`^v?(\d+)\.(\d+)(?:\.|$)`)
+
+// patchVersion matches the tags of final releases, like v1.20.1, capturing
+// the major, minor and patch numbers
+var patchVersion = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)$`)
+
+// parsePatchVersion returns the major, minor and patch numbers of a release
+// version, and false if it is not the version of a final release
+func parsePatchVersion(version string) ([3]int, bool) {
+	parsed := [3]int{}
+	match := patchVersion.FindStringSubmatch(version)
+	if match == nil {
+		return parsed, false
+	}
+	for i := range parsed {
+		parsed[i], _ = strconv.Atoi(match[i+1])
+	}
+	return parsed, true
+}
+
+// lessPatchVersion orders release versions by their major, minor and patch
+// numbers. Versions which cannot be parsed come after the parsed ones, in
+// lexical order.
+func lessPatchVersion(a, b string) bool {
+	pa, oka := parsePatchVersion(a)
+	pb, okb := parsePatchVersion(b)
+	if oka != okb {
+		return oka
+	}
+	if !oka {
+		return a < b
+	}
+	for i := range pa {
+		if pa[i] != pb[i] {
+			return pa[i] < pb[i]
+		}
+	}
+	return a < b
+}
+
+// patchTag is a release tag and the commit it points to
+type patchTag struct {
+	name   string
+	commit *object.Commit
+}
+
+// ContainingTags returns the first release tag of the repository in workDir
+// which contains each of the commits, like `git tag --contains` does, keyed by
+// the SHA of the commit. Only the tags of final releases are considered, which
+// are ordered by their version, and only those of the minor series of the
+// version of series, like v1.20 for v1.20.3 or v1.20.x, if it is set. Commits which are
+// not part of the repository or not contained in any of the tags are missing
+// from the result.
+func ContainingTags(workDir string, commits []string, series string) (map[string]string, error) {
+	repo, err := git.PlainOpen(workDir)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := ""
+	if series != "" {
+		match := minorSeries.FindStringSubmatch(series)
+		if match == nil {
+			return nil, errors.Errorf("%s is not the version of a minor series", series)
+		}
+		prefix = match[1] + "." + match[2] + "."
+	}
+
+	refs, err := repo.Tags()
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing the tags")
+	}
+	tags := []patchTag{}
+	if err := refs.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name().Short()
+		if _, ok := parsePatchVersion(name); !ok || !strings.HasPrefix(strings.TrimPrefix(name, "v"), prefix) {
+			return nil
+		}
+		// annotated tags are resolved to the commit they point to
+		hash, err := repo.ResolveRevision(plumbing.Revision(ref.Name().String()))
+		if err != nil {
+			return errors.Wrapf(err, "error resolving the tag %s", name)
+		}
+		commit, err := repo.CommitObject(*hash)
+		if err != nil {
+			return errors.Wrapf(err, "error getting the commit of the tag %s", name)
+		}
+		tags = append(tags, patchTag{name: name, commit: commit})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return lessPatchVersion(tags[i].name, tags[j].name)
+	})
+
+	result := map[string]string{}
+	for _, sha := range commits {
+		if _, ok := result[sha]; ok {
+			continue
+		}
+		commit, err := repo.CommitObject(plumbing.NewHash(sha))
+		if err != nil {
+			continue
+		}
+		for _, tag := range tags {
+			contained := commit.Hash == tag.commit.Hash
+			if !contained {
+				if contained, err = commit.IsAncestor(tag.commit); err != nil {
+					return nil, errors.Wrapf(err, "error checking if %s contains %s", tag.name, sha)
+				}
+			}
+			if contained {
+				result[sha] = tag.name
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// unreleasedBucket is the title of the notes which are not part of any release
+const unreleasedBucket = "Unreleased"
+
+// markdownHeading matches the headings of the rendered markdown, which are
+// demoted by a level within a bucket
+var markdownHeading = regexp.MustCompile(`(?m)^(#+ )`)
+
+// WithPatchBuckets allows the caller to group the rendered markdown by the
+// release version of the notes, like the patch releases of a minor series,
+// under a `## v1.20.1` heading per version, in the order of the versions. The
+// sections of every version are demoted by a level. Notes without a release
+// version come last, as unreleased. If the notes do not have more than one
+// version, the markdown is rendered as a single document.
+func WithPatchBuckets() DocumentOption {
+	return func(c *documentConfig) {
+		c.patchBuckets = true
+	}
+}
+
+// PatchBuckets returns the notes grouped by their release version, and the
+// versions in the order of WithPatchBuckets
+func PatchBuckets(notes ReleaseNoteList) ([]string, map[string]ReleaseNoteList) {
+	buckets := map[string]ReleaseNoteList{}
+	for number, note := range notes {
+		if buckets[note.ReleaseVersion] == nil {
+			buckets[note.ReleaseVersion] = ReleaseNoteList{}
+		}
+		buckets[note.ReleaseVersion][number] = note
+	}
+
+	versions := []string{}
+	for version := range buckets {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		// the unreleased notes come last
+		if versions[i] == "" || versions[j] == "" {
+			return versions[j] == ""
+		}
+		return lessPatchVersion(versions[i], versions[j])
+	})
+	return versions, buckets
+}
+
+// renderPatchBuckets renders the notes to markdown grouped by their release
+// version, as requested by WithPatchBuckets
+func renderPatchBuckets(notes ReleaseNoteList, w io.Writer, opts ...DocumentOption) error {
+	versions, buckets := PatchBuckets(notes)
+	if len(versions) <= 1 {
+		doc, err := CreateDocument(notes, opts...)
+		if err != nil {
+			return errors.Wrap(err, "error creating release note document")
+		}
+		return RenderMarkdown(doc, w, opts...)
+	}
+
+	for _, version := range versions {
+		title := version
+		if title == "" {
+			title = unreleasedBucket
+		}
+		doc, err := CreateDocument(buckets[version], opts...)
+		if err != nil {
+			return errors.Wrapf(err, "error creating the release note document of %s", title)
+		}
+		rendered := &bytes.Buffer{}
+		if err := RenderMarkdown(doc, rendered, opts...); err != nil {
+			return errors.Wrapf(err, "error rendering the release notes of %s", title)
+		}
+
+		if _, err := io.WriteString(w, "## "+title+"\n\n"); err != nil {
+			return err
+		}
+		if _, err := w.Write(markdownHeading.ReplaceAll(rendered.Bytes(), []byte("#$1"))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
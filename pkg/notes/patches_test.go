@@ -0,0 +1,105 @@
+package notes
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func TestContainingTags(t *testing.T) {
+	dir, err := ioutil.TempDir("", "release-notes-patches")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	repo, err := git.PlainInit(dir, false)
+	require.Nil(t, err)
+	worktree, err := repo.Worktree()
+	require.Nil(t, err)
+
+	commit := func(message string) plumbing.Hash {
+		require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "file"), []byte(message), 0644))
+		_, err := worktree.Add("file")
+		require.Nil(t, err)
+		hash, err := worktree.Commit(message, &git.CommitOptions{
+			Author: &object.Signature{Name: "a", Email: "a@example.com", When: time.Now()},
+		})
+		require.Nil(t, err)
+		return hash
+	}
+	tag := func(name string, hash plumbing.Hash) {
+		_, err := repo.CreateTag(name, hash, nil)
+		require.Nil(t, err)
+	}
+
+	first := commit("first")
+	tag("v1.20.0", first)
+	second := commit("second")
+	third := commit("third")
+	tag("v1.20.1", third)
+	tag("v1.20.2-rc.0", third)
+	fourth := commit("fourth")
+	tag("v1.20.10", fourth)
+	tag("v1.21.0", fourth)
+	fifth := commit("fifth")
+
+	tags, err := ContainingTags(dir, []string{first.String(), second.String(), third.String(), fourth.String(), fifth.String(), "unknown"}, "")
+	require.Nil(t, err)
+	require.Equal(t, map[string]string{
+		first.String():  "v1.20.0",
+		second.String(): "v1.20.1",
+		third.String():  "v1.20.1",
+		fourth.String(): "v1.20.10",
+	}, tags)
+
+	// the tags of other minor series are ignored
+	tags, err = ContainingTags(dir, []string{second.String()}, "v1.21.3")
+	require.Nil(t, err)
+	require.Equal(t, map[string]string{second.String(): "v1.21.0"}, tags)
+	tags, err = ContainingTags(dir, []string{second.String()}, "v1.2")
+	require.Nil(t, err)
+	require.Empty(t, tags)
+
+	tags, err = ContainingTags(dir, []string{second.String()}, "v1.21.x")
+	require.Nil(t, err)
+	require.Equal(t, map[string]string{second.String(): "v1.21.0"}, tags)
+
+	_, err = ContainingTags(dir, nil, "main")
+	require.NotNil(t, err)
+}
+
+func TestPatchBuckets(t *testing.T) {
+	releaseNotes := ReleaseNoteList{
+		1: {PrNumber: 1, Markdown: "an unreleased fix", Kinds: []string{"bug"}},
+		2: {PrNumber: 2, Markdown: "a later fix", Kinds: []string{"bug"}, ReleaseVersion: "v1.20.10"},
+		3: {PrNumber: 3, Markdown: "a feature", Feature: true, ReleaseVersion: "v1.20.2"},
+		4: {PrNumber: 4, Markdown: "a fix", Kinds: []string{"bug"}, ReleaseVersion: "v1.20.2"},
+	}
+
+	versions, buckets := PatchBuckets(releaseNotes)
+	require.Equal(t, []string{"v1.20.2", "v1.20.10", ""}, versions)
+	require.Len(t, buckets["v1.20.2"], 2)
+
+	content, err := RenderToBytes(releaseNotes, "markdown", WithPatchBuckets())
+	require.Nil(t, err)
+	markdown := string(content)
+	require.True(t, strings.HasPrefix(markdown, "## v1.20.2\n\n### New Features\n\n"))
+	require.Contains(t, markdown, "## v1.20.10\n\n### Bug Fixes\n\n- a later fix")
+	require.Contains(t, markdown, "## Unreleased\n\n### Bug Fixes\n\n- an unreleased fix")
+	require.True(t, strings.Index(markdown, "## v1.20.10") < strings.Index(markdown, "## Unreleased"))
+
+	// without release versions, the notes are rendered as a single document
+	single := ReleaseNoteList{1: releaseNotes[1]}
+	content, err = RenderToBytes(single, "markdown", WithPatchBuckets())
+	require.Nil(t, err)
+	plain, err := RenderToBytes(single, "markdown")
+	require.Nil(t, err)
+	require.Equal(t, string(plain), string(content))
+}
@@ -0,0 +1,71 @@
+package notes
+
+import (
+	"path"
+	"strings"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-github/v27/github"
+)
+
+// WithPathFilter allows the caller to keep only the notes of PRs which change
+// a file matching one of the glob patterns, like "pkg/kubelet/" or
+// "cmd/*/main.go". This costs an additional API request to list the files of
+// every PR, so the check is limited to the first limit PRs unless limit is
+// zero. The notes of the PRs which are not checked are kept. The budget is
+// shared by all calls which are passed the same option.
+func WithPathFilter(patterns []string, limit int) GithubApiOption {
+	var budget *inferenceBudget
+	if limit > 0 {
+		budget = &inferenceBudget{remaining: limit}
+	}
+	return func(c *githubApiConfig) {
+		c.pathFilter = patterns
+		c.pathBudget = budget
+	}
+}
+
+// MatchesPathFilter returns true if one of the files matches one of the glob
+// patterns. A pattern matches a file if it matches its path or one of its
+// parent directories, so "pkg/kubelet" and "pkg/kubelet/" match all files
+// below pkg/kubelet.
+func MatchesPathFilter(files, patterns []string) bool {
+	for _, file := range files {
+		for _, pattern := range patterns {
+			pattern = strings.TrimSuffix(pattern, "/")
+			for dir := file; dir != "." && dir != "/"; dir = path.Dir(dir) {
+				if matched, _ := path.Match(pattern, dir); matched {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// matchesPathFilter returns true if the PR of the note changes a file matching
+// the path filter, or if there is no path filter. The notes of PRs whose files
+// are not listed, because the budget is used up or the request fails, are kept.
+func matchesPathFilter(client *github.Client, logger log.Logger, note *ReleaseNote, opts ...GithubApiOption) bool {
+	c := configFromOpts(opts...)
+	if len(c.pathFilter) == 0 {
+		return true
+	}
+
+	if c.pathBudget != nil {
+		ok, exhausted := c.pathBudget.take()
+		if exhausted {
+			warnPR(logger, c, WarningBudgetExhausted, note.PrNumber, "path filter budget exhausted, the notes of the remaining PRs are kept without checking their files", nil)
+		}
+		if !ok {
+			return true
+		}
+	}
+
+	files, err := ListPRFiles(client, note.PrNumber, opts...)
+	if err != nil {
+		warnPR(logger, c, WarningFetchFailed, note.PrNumber, "error listing the files of the PR to apply the path filter", err)
+		return true
+	}
+	return MatchesPathFilter(files, c.pathFilter)
+}
@@ -0,0 +1,48 @@
+package notes
+
+import (
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchesPathFilter(t *testing.T) {
+	files := []string{"pkg/kubelet/kubelet.go", "cmd/kubectl/main.go"}
+	require.True(t, MatchesPathFilter(files, []string{"pkg/kubelet/"}))
+	require.True(t, MatchesPathFilter(files, []string{"pkg/kubelet"}))
+	require.True(t, MatchesPathFilter(files, []string{"docs/", "cmd/*/main.go"}))
+	require.True(t, MatchesPathFilter(files, []string{"pkg/*"}))
+	require.False(t, MatchesPathFilter(files, []string{"pkg/kube"}))
+	require.False(t, MatchesPathFilter(files, []string{"pkg/kubelet/*_test.go"}))
+	require.False(t, MatchesPathFilter([]string{}, []string{"pkg/kubelet/"}))
+}
+
+func TestPathFilter(t *testing.T) {
+	client, server := newFixtureClient(t, "path-filter", map[string]string{
+		"/repos/kubernetes/kubernetes/pulls/1/files": "pull-1-files.json",
+		"/repos/kubernetes/kubernetes/pulls/2/files": "pull-2-files.json",
+	})
+	defer server.Close()
+
+	kubelet := &ReleaseNote{PrNumber: 1}
+	kubectl := &ReleaseNote{PrNumber: 2}
+	missing := &ReleaseNote{PrNumber: 3}
+
+	// no files are listed without a path filter
+	require.True(t, matchesPathFilter(client, log.NewNopLogger(), kubectl))
+	require.Equal(t, 0, server.requests)
+
+	opts := []GithubApiOption{WithOrg("kubernetes"), WithRepo("kubernetes"), WithPathFilter([]string{"pkg/kubelet/"}, 0)}
+	require.True(t, matchesPathFilter(client, log.NewNopLogger(), kubelet, opts...))
+	require.False(t, matchesPathFilter(client, log.NewNopLogger(), kubectl, opts...))
+	require.True(t, matchesPathFilter(client, log.NewNopLogger(), missing, opts...))
+	require.Equal(t, 3, server.requests)
+
+	// the notes of the PRs beyond the budget are kept unchecked
+	server.requests = 0
+	opts = []GithubApiOption{WithOrg("kubernetes"), WithRepo("kubernetes"), WithPathFilter([]string{"pkg/kubelet/"}, 1)}
+	require.True(t, matchesPathFilter(client, log.NewNopLogger(), kubelet, opts...))
+	require.True(t, matchesPathFilter(client, log.NewNopLogger(), kubectl, opts...))
+	require.Equal(t, 1, server.requests)
+}
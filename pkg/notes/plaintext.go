@@ -0,0 +1,258 @@
+package notes
+
+import (
+	"strings"
+	"unicode"
+)
+
+// StripMarkdown flattens the markdown of a note to plain text. Links are
+// rendered as "text (url)", emphasis and strikethrough markers are removed,
+// code spans and fenced code blocks are unwrapped and backslash escapes are
+// resolved. Nested formatting, like a bold link text, is flattened as well.
+//
+// The inline markdown is parsed by a small recursive descent parser instead of
+// regular expressions, so that the content of code spans is never mangled and
+// unbalanced markers are kept as they are.
+func StripMarkdown(s string) string {
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+
+	result := []string{}
+	fenced := false
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fenced = !fenced
+			continue
+		}
+		if fenced {
+			result = append(result, line)
+			continue
+		}
+		result = append(result, stripInline([]rune(line)))
+	}
+	return strings.Join(result, "\n")
+}
+
+// emphasisMarkers are the delimiters of emphasis and strikethrough, longest
+// first so that "**" is matched before "*"
+var emphasisMarkers = []string{"**", "__", "~~", "*", "_"}
+
+// stripInline flattens the inline markdown of a single line
+func stripInline(s []rune) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && (unicode.IsPunct(s[i+1]) || unicode.IsSymbol(s[i+1])):
+			b.WriteRune(s[i+1])
+			i += 2
+			continue
+
+		case s[i] == '`':
+			if content, next, ok := parseCodeSpan(s, i); ok {
+				b.WriteString(content)
+				i = next
+				continue
+			}
+
+		case s[i] == '!' && i+1 < len(s) && s[i+1] == '[':
+			if text, url, next, ok := parseLink(s, i+1); ok {
+				b.WriteString(linkText(text, url))
+				i = next
+				continue
+			}
+
+		case s[i] == '[':
+			if text, url, next, ok := parseLink(s, i); ok {
+				b.WriteString(linkText(text, url))
+				i = next
+				continue
+			}
+
+		case s[i] == '<':
+			if url, next, ok := parseAutolink(s, i); ok {
+				b.WriteString(url)
+				i = next
+				continue
+			}
+
+		default:
+			if inner, next, ok := parseEmphasis(s, i); ok {
+				b.WriteString(stripInline(inner))
+				i = next
+				continue
+			}
+		}
+
+		b.WriteRune(s[i])
+		i++
+	}
+	return b.String()
+}
+
+// linkText renders a link as its flattened text followed by the url
+func linkText(text []rune, url string) string {
+	flattened := stripInline(text)
+	if url == "" || url == flattened {
+		return flattened
+	}
+	return flattened + " (" + url + ")"
+}
+
+// parseCodeSpan parses a code span starting with a run of backticks at i. The
+// span ends with a run of backticks of the same length.
+func parseCodeSpan(s []rune, i int) (content string, next int, ok bool) {
+	run := 0
+	for i+run < len(s) && s[i+run] == '`' {
+		run++
+	}
+
+	for j := i + run; j < len(s); {
+		if s[j] != '`' {
+			j++
+			continue
+		}
+		closing := 0
+		for j+closing < len(s) && s[j+closing] == '`' {
+			closing++
+		}
+		if closing == run {
+			return strings.TrimSpace(string(s[i+run : j])), j + closing, true
+		}
+		j += closing
+	}
+	return "", 0, false
+}
+
+// parseLink parses a link like [text](url) starting at the opening bracket at
+// i. Brackets within the text and parentheses within the url may be nested.
+func parseLink(s []rune, i int) (text []rune, url string, next int, ok bool) {
+	depth := 0
+	for j := i; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '`':
+			if _, end, code := parseCodeSpan(s, j); code {
+				j = end - 1
+			}
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth > 0 {
+				continue
+			}
+			if j+1 >= len(s) || s[j+1] != '(' {
+				return nil, "", 0, false
+			}
+
+			parens := 0
+			for k := j + 2; k < len(s); k++ {
+				switch s[k] {
+				case '(':
+					parens++
+				case ')':
+					if parens == 0 {
+						return s[i+1 : j], linkDestination(string(s[j+2 : k])), k + 1, true
+					}
+					parens--
+				}
+			}
+			return nil, "", 0, false
+		}
+	}
+	return nil, "", 0, false
+}
+
+// linkDestination drops the optional title of a link destination, like in
+// [text](url "title")
+func linkDestination(target string) string {
+	if fields := strings.Fields(target); len(fields) > 0 {
+		return strings.Trim(fields[0], "<>")
+	}
+	return ""
+}
+
+// parseAutolink parses an autolink like <https://k8s.io> starting at i
+func parseAutolink(s []rune, i int) (url string, next int, ok bool) {
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case ' ', '<':
+			return "", 0, false
+		case '>':
+			url = string(s[i+1 : j])
+			if !strings.Contains(url, "://") && !strings.HasPrefix(url, "mailto:") {
+				return "", 0, false
+			}
+			return url, j + 1, true
+		}
+	}
+	return "", 0, false
+}
+
+// parseEmphasis parses emphasis or strikethrough starting with a marker at i,
+// which is closed by the same marker. Underscores only delimit emphasis at
+// word boundaries, so that identifiers like snake_case are kept.
+func parseEmphasis(s []rune, i int) (inner []rune, next int, ok bool) {
+	for _, marker := range emphasisMarkers {
+		mr := []rune(marker)
+		if !hasRunesAt(s, i, mr) {
+			continue
+		}
+		open := i + len(mr)
+		if open >= len(s) || unicode.IsSpace(s[open]) {
+			return nil, 0, false
+		}
+		if mr[0] == '_' && i > 0 && isWordRune(s[i-1]) {
+			return nil, 0, false
+		}
+
+		for j := open + 1; j < len(s); {
+			if s[j] == '\\' {
+				j += 2
+				continue
+			}
+			if s[j] == '`' {
+				if _, end, code := parseCodeSpan(s, j); code {
+					j = end
+					continue
+				}
+			}
+			if s[j] != mr[0] {
+				j++
+				continue
+			}
+
+			// runs of a different length belong to nested markers
+			run := 0
+			for j+run < len(s) && s[j+run] == mr[0] {
+				run++
+			}
+			closes := run == len(mr) && !unicode.IsSpace(s[j-1]) &&
+				!(mr[0] == '_' && j+run < len(s) && isWordRune(s[j+run]))
+			if closes {
+				return s[open:j], j + run, true
+			}
+			j += run
+		}
+		return nil, 0, false
+	}
+	return nil, 0, false
+}
+
+// hasRunesAt returns true if s contains the runes of prefix at position i
+func hasRunesAt(s []rune, i int, prefix []rune) bool {
+	if i+len(prefix) > len(s) {
+		return false
+	}
+	for k, r := range prefix {
+		if s[i+k] != r {
+			return false
+		}
+	}
+	return true
+}
+
+// isWordRune returns true for letters and digits
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
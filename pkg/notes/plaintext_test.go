@@ -0,0 +1,36 @@
+package notes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStripMarkdown(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{input: "plain text", expected: "plain text"},
+		{input: "Fix a **bold** and *italic* and ~~gone~~ bug", expected: "Fix a bold and italic and gone bug"},
+		{input: "See [the docs](https://k8s.io/docs) for details", expected: "See the docs (https://k8s.io/docs) for details"},
+		{input: "See [**the** docs](https://k8s.io/docs \"Docs\")", expected: "See the docs (https://k8s.io/docs)"},
+		{input: "**bold with [a *nested* link](https://k8s.io)**", expected: "bold with a nested link (https://k8s.io)"},
+		{input: "*italic with **bold** inside*", expected: "italic with bold inside"},
+		{input: "Use `--foo=*bar*` or ``a ` tick``", expected: "Use --foo=*bar* or a ` tick"},
+		{input: "Keep snake_case_names and 2 * 3 * 4", expected: "Keep snake_case_names and 2 * 3 * 4"},
+		{input: "an _emphasized_ word", expected: "an emphasized word"},
+		{input: "unbalanced **bold and [link](", expected: "unbalanced **bold and [link]("},
+		{input: "escaped \\*stars\\* and \\[brackets\\]", expected: "escaped *stars* and [brackets]"},
+		{input: "visit <https://k8s.io> now", expected: "visit https://k8s.io now"},
+		{input: "![logo](https://k8s.io/logo.png)", expected: "logo (https://k8s.io/logo.png)"},
+		{input: "[https://k8s.io](https://k8s.io)", expected: "https://k8s.io"},
+		{input: "[url (with parens)](https://en.wikipedia.org/wiki/Go_(language))", expected: "url (with parens) (https://en.wikipedia.org/wiki/Go_(language))"},
+		{input: "before\n```yaml\nkey: **value**\n```\nafter", expected: "before\nkey: **value**\nafter"},
+		{input: "- a *list* item\n- another", expected: "- a list item\n- another"},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, StripMarkdown(tc.input), tc.input)
+	}
+}
@@ -0,0 +1,79 @@
+package notes
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultPRLinkFormat is the text of the links to the PRs of the notes, like
+// #12345
+const DefaultPRLinkFormat = "#{number}"
+
+// markdownPRLink matches the markdown link to a PR, like
+// [#123](https://github.com/kubernetes/kubernetes/pull/123), capturing the URL
+// as well as the organization, repository and number of the PR
+var markdownPRLink = regexp.MustCompile(`\[#\d+\]\((https?://[^/)]+/([^/)]+)/([^/)]+)/pull/(\d+))\)`)
+
+// WithPRLinkFormat allows the caller to change the text of the links to the
+// PRs of the markdown and HTML notes, like "PR #{number}" or
+// "{org}/{repo}#{number}". The placeholders {org}, {repo} and {number} are
+// replaced by the organization, repository and number of the PR. The default
+// is DefaultPRLinkFormat.
+func WithPRLinkFormat(format string) DocumentOption {
+	return func(c *documentConfig) {
+		c.prLinkFormat = format
+	}
+}
+
+// PRLinkText returns the text of the link to a PR in the provided format
+func PRLinkText(format, org, repo string, number int) string {
+	return strings.NewReplacer(
+		"{org}", org,
+		"{repo}", repo,
+		"{number}", strconv.Itoa(number),
+	).Replace(format)
+}
+
+// ValidatePRLinkFormat checks that the format renders a non empty text which
+// can be the text of a markdown link, for a sample PR
+func ValidatePRLinkFormat(format string) error {
+	text := PRLinkText(format, "kubernetes", "kubernetes", 12345)
+	if strings.TrimSpace(text) == "" {
+		return errors.Errorf("the PR link format %q renders an empty text", format)
+	}
+	if strings.ContainsAny(text, "[]\n") {
+		return errors.Errorf("the PR link format %q must not contain brackets or newlines", format)
+	}
+	return nil
+}
+
+// formatPRLinks rewrites the text of the markdown links to PRs, which are
+// rendered as #123 when the notes are fetched, in the format
+func formatPRLinks(markdown, format string) string {
+	if format == "" || format == DefaultPRLinkFormat {
+		return markdown
+	}
+	return markdownPRLink.ReplaceAllStringFunc(markdown, func(link string) string {
+		match := markdownPRLink.FindStringSubmatch(link)
+		number, _ := strconv.Atoi(match[4])
+		return "[" + PRLinkText(format, match[2], match[3], number) + "](" + match[1] + ")"
+	})
+}
+
+// prLinkText returns the text of the link to the PR of the note in the format
+// of the config. The organization and repository are taken from the URL of
+// the PR.
+func prLinkText(note *ReleaseNote, c *documentConfig) string {
+	format := c.prLinkFormat
+	if format == "" {
+		format = DefaultPRLinkFormat
+	}
+	org, repo := "", ""
+	if match := prURLRepository.FindStringSubmatch(note.PrUrl); match != nil {
+		org, repo = match[1], match[2]
+	}
+	return PRLinkText(format, org, repo, note.PrNumber)
+}
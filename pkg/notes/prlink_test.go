@@ -0,0 +1,45 @@
+package notes
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPRLinkFormat(t *testing.T) {
+	releaseNotes := ReleaseNoteList{
+		1: {
+			PrNumber: 1,
+			PrUrl:    "https://github.com/kubernetes/release/pull/1",
+			Text:     "a fix",
+			Markdown: "a fix ([#1](https://github.com/kubernetes/release/pull/1), [@a](https://github.com/a))\n\n  Also in [#2](https://github.com/kubernetes/kubernetes/pull/2)\n\n  Fixes [#3](https://github.com/kubernetes/release/issues/3)",
+			Author:   "a",
+			Kinds:    []string{"bug"},
+		},
+	}
+
+	content, err := RenderToBytes(releaseNotes, "markdown")
+	require.Nil(t, err)
+	require.Contains(t, string(content), "[#1](https://github.com/kubernetes/release/pull/1)")
+
+	opts := []DocumentOption{WithPRLinkFormat("{org}/{repo}#{number}")}
+	content, err = RenderToBytes(releaseNotes, "markdown", opts...)
+	require.Nil(t, err)
+	require.Contains(t, string(content), "- a fix ([kubernetes/release#1](https://github.com/kubernetes/release/pull/1), [@a]")
+	require.Contains(t, string(content), "Also in [kubernetes/kubernetes#2](https://github.com/kubernetes/kubernetes/pull/2)")
+	// the links to issues are kept
+	require.Contains(t, string(content), "Fixes [#3](https://github.com/kubernetes/release/issues/3)")
+
+	buf := &bytes.Buffer{}
+	require.Nil(t, RenderHTML(releaseNotes, buf, WithPRLinkFormat("PR #{number}")))
+	require.Contains(t, buf.String(), `<a href="https://github.com/kubernetes/release/pull/1">PR #1</a>`)
+}
+
+func TestValidatePRLinkFormat(t *testing.T) {
+	require.Nil(t, ValidatePRLinkFormat(DefaultPRLinkFormat))
+	require.Nil(t, ValidatePRLinkFormat("PR {number}"))
+	require.NotNil(t, ValidatePRLinkFormat(""))
+	require.NotNil(t, ValidatePRLinkFormat("  "))
+	require.NotNil(t, ValidatePRLinkFormat("[{number}]"))
+}
@@ -0,0 +1,53 @@
+package notes
+
+// ProgressStage is the step of ListReleaseNotes a Progress event reports on.
+type ProgressStage string
+
+const (
+	// ProgressCommits is reported after every page of commits fetched from
+	// GitHub
+	ProgressCommits ProgressStage = "commits"
+
+	// ProgressNotes is reported while the commits are turned into notes
+	ProgressNotes ProgressStage = "notes"
+)
+
+// Progress is a structured progress event of ListReleaseNotes.
+type Progress struct {
+	Stage ProgressStage
+
+	// Page and Pages are the current and last page of commits, only set for
+	// ProgressCommits
+	Page  int
+	Pages int
+
+	// Commits is the number of commits fetched so far for ProgressCommits and
+	// the total number of commits for ProgressNotes
+	Commits int
+
+	// Processed is the number of commits already turned into notes and Notes
+	// the number of resulting notes, only set for ProgressNotes
+	Processed int
+	Notes     int
+}
+
+// WithProgress allows the caller to receive progress events while the notes
+// are listed. The events are sent without blocking, so events are dropped if
+// the channel is full. The channel is never closed.
+func WithProgress(progress chan<- Progress) GithubApiOption {
+	return func(c *githubApiConfig) {
+		c.progress = progress
+	}
+}
+
+// sendProgress sends the event to the progress channel of the config, if any,
+// unless the channel is full
+func sendProgress(c *githubApiConfig, event Progress) {
+	if c.progress == nil {
+		return
+	}
+	select {
+	case c.progress <- event:
+	default:
+	}
+}
@@ -0,0 +1,25 @@
+package notes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendProgress(t *testing.T) {
+	// no channel is fine
+	sendProgress(configFromOpts(), Progress{Stage: ProgressNotes})
+
+	progress := make(chan Progress, 2)
+	c := configFromOpts(WithProgress(progress))
+
+	sendProgress(c, Progress{Stage: ProgressCommits, Page: 1, Pages: 3, Commits: 100})
+	sendProgress(c, Progress{Stage: ProgressCommits, Page: 2, Pages: 3, Commits: 200})
+
+	// the channel is full, so the event is dropped instead of blocking
+	sendProgress(c, Progress{Stage: ProgressCommits, Page: 3, Pages: 3, Commits: 250})
+
+	require.Len(t, progress, 2)
+	require.Equal(t, Progress{Stage: ProgressCommits, Page: 1, Pages: 3, Commits: 100}, <-progress)
+	require.Equal(t, Progress{Stage: ProgressCommits, Page: 2, Pages: 3, Commits: 200}, <-progress)
+}
@@ -0,0 +1,113 @@
+package notes
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-github/v27/github"
+)
+
+// WithReactions allows the caller to fetch the total number of reactions on
+// every PR into the Reactions of its note. This costs one additional API
+// request per PR, so the reactions are only fetched for the first limit PRs
+// unless limit is zero. The budget is shared by all calls which are passed the
+// same option.
+func WithReactions(limit int) GithubApiOption {
+	var budget *inferenceBudget
+	if limit > 0 {
+		budget = &inferenceBudget{remaining: limit}
+	}
+	return func(c *githubApiConfig) {
+		c.fetchReactions = true
+		c.reactionsBudget = budget
+	}
+}
+
+// PRReactions returns the total number of reactions on the PR with the
+// provided number
+func PRReactions(client *github.Client, number int, opts ...GithubApiOption) (int, error) {
+	c := configFromOpts(opts...)
+
+	issue, _, err := client.Issues.Get(c.ctx, c.org, c.repo, number)
+	if err != nil {
+		return 0, err
+	}
+	return issue.GetReactions().GetTotalCount(), nil
+}
+
+// noteReactions returns the reactions on the PR of the note if they were
+// requested and the budget allows. Failures are logged and result in no
+// reactions, because the reactions are informational only.
+func noteReactions(client *github.Client, logger log.Logger, number int, opts ...GithubApiOption) int {
+	c := configFromOpts(opts...)
+	if !c.fetchReactions {
+		return 0
+	}
+	if c.reactionsBudget != nil {
+		ok, exhausted := c.reactionsBudget.take()
+		if exhausted {
+			warnPR(logger, c, WarningBudgetExhausted, number, "reactions budget exhausted, the reactions of the remaining PRs are not fetched", nil)
+		}
+		if !ok {
+			return 0
+		}
+	}
+
+	reactions, err := PRReactions(client, number, opts...)
+	if err != nil {
+		warnPR(logger, c, WarningFetchFailed, number, "error fetching the reactions of the PR", err)
+		return 0
+	}
+	return reactions
+}
+
+// TopReacted returns the at most n notes with the most reactions, ordered by
+// the number of reactions and then by PR number. Notes without reactions are
+// never included.
+func TopReacted(notes ReleaseNoteList, n int) []*ReleaseNote {
+	top := []*ReleaseNote{}
+	for _, note := range notes {
+		if note.Reactions > 0 {
+			top = append(top, note)
+		}
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Reactions != top[j].Reactions {
+			return top[i].Reactions > top[j].Reactions
+		}
+		return top[i].PrNumber < top[j].PrNumber
+	})
+	if len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
+
+// RenderTopReactedMarkdown writes a "Most Popular Changes" section listing
+// the at most n notes with the most reactions to the supplied io.Writer in
+// markdown format. Nothing is written if no note has reactions.
+func RenderTopReactedMarkdown(notes ReleaseNoteList, n int, w io.Writer, opts ...DocumentOption) error {
+	top := TopReacted(notes, n)
+	if len(top) == 0 {
+		return nil
+	}
+	c := documentConfigFromOpts(opts...)
+
+	var b strings.Builder
+	b.WriteString(headingMarkdown("##", "Most Popular Changes", "most-popular-changes", c))
+	for _, note := range top {
+		item, err := noteListItem(note, c)
+		if err != nil {
+			return err
+		}
+		item = strings.TrimPrefix(item, "- ")
+		fmt.Fprintf(&b, "- %s (%d reactions)\n", item, note.Reactions)
+	}
+	b.WriteString("\n\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
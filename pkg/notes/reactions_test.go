@@ -0,0 +1,69 @@
+package notes
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoteReactions(t *testing.T) {
+	requests := 0
+	client, server := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/repos/kubernetes/kubernetes/issues/1":
+			fmt.Fprint(w, `{"number": 1, "reactions": {"total_count": 7, "+1": 5, "heart": 2}}`)
+		case "/repos/kubernetes/kubernetes/issues/2":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	reactions, err := PRReactions(client, 1)
+	require.Nil(t, err)
+	require.Equal(t, 7, reactions)
+	requests = 0
+
+	// the reactions are only fetched if requested
+	require.Equal(t, 0, noteReactions(client, log.NewNopLogger(), 1))
+	require.Equal(t, 0, requests)
+
+	// failures degrade to no reactions
+	opts := []GithubApiOption{WithReactions(2)}
+	require.Equal(t, 0, noteReactions(client, log.NewNopLogger(), 2, opts...))
+	require.Equal(t, 7, noteReactions(client, log.NewNopLogger(), 1, opts...))
+	require.Equal(t, 2, requests)
+
+	// the budget is exhausted
+	require.Equal(t, 0, noteReactions(client, log.NewNopLogger(), 1, opts...))
+	require.Equal(t, 2, requests)
+}
+
+func TestRenderTopReactedMarkdown(t *testing.T) {
+	notes := ReleaseNoteList{
+		1: {PrNumber: 1, Markdown: "First", Reactions: 3},
+		2: {PrNumber: 2, Markdown: "Second", Reactions: 10},
+		3: {PrNumber: 3, Markdown: "Third", Reactions: 3},
+		4: {PrNumber: 4, Markdown: "Fourth"},
+	}
+
+	require.Equal(t, []*ReleaseNote{notes[2], notes[1]}, TopReacted(notes, 2))
+	require.Equal(t, []*ReleaseNote{notes[2], notes[1], notes[3]}, TopReacted(notes, 10))
+
+	out := &bytes.Buffer{}
+	require.Nil(t, RenderTopReactedMarkdown(notes, 2, out))
+	require.Equal(t, "## Most Popular Changes\n\n"+
+		"- Second (10 reactions)\n"+
+		"- First (3 reactions)\n\n\n", out.String())
+
+	// nothing is written without reactions
+	out.Reset()
+	require.Nil(t, RenderTopReactedMarkdown(ReleaseNoteList{4: notes[4]}, 2, out))
+	require.Equal(t, "", out.String())
+}
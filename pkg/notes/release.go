@@ -0,0 +1,43 @@
+package notes
+
+import (
+	"net/http"
+
+	"github.com/google/go-github/v27/github"
+	"github.com/pkg/errors"
+)
+
+// PublishRelease creates the GitHub release of the provided tag with the body,
+// or updates the release if it already exists.
+func PublishRelease(client *github.Client, tag, body string, draft, prerelease bool, opts ...GithubApiOption) (*github.RepositoryRelease, error) {
+	c := configFromOpts(opts...)
+
+	release := &github.RepositoryRelease{
+		TagName:    github.String(tag),
+		Name:       github.String(tag),
+		Body:       github.String(body),
+		Draft:      github.Bool(draft),
+		Prerelease: github.Bool(prerelease),
+	}
+
+	existing, resp, err := client.Repositories.GetReleaseByTag(c.ctx, c.org, c.repo, tag)
+	if err != nil {
+		if resp == nil || resp.StatusCode != http.StatusNotFound {
+			return nil, errors.Wrapf(err, "error getting the release of tag %s", tag)
+		}
+
+		created, _, err := client.Repositories.CreateRelease(c.ctx, c.org, c.repo, release)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error creating the release of tag %s", tag)
+		}
+		return created, nil
+	}
+
+	// keep the name of an existing release, it may have been edited manually
+	release.Name = nil
+	updated, _, err := client.Repositories.EditRelease(c.ctx, c.org, c.repo, existing.GetID(), release)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error updating the release of tag %s", tag)
+	}
+	return updated, nil
+}
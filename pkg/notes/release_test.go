@@ -0,0 +1,86 @@
+package notes
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v27/github"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishRelease(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		existing bool
+		status   int
+		method   string
+		path     string
+		err      string
+	}{
+		{
+			name:   "create",
+			status: http.StatusNotFound,
+			method: http.MethodPost,
+			path:   "/repos/kubernetes/kubernetes/releases",
+		},
+		{
+			name:     "update",
+			existing: true,
+			method:   http.MethodPatch,
+			path:     "/repos/kubernetes/kubernetes/releases/42",
+		},
+		{
+			name:   "error getting the release",
+			status: http.StatusInternalServerError,
+			err:    "error getting the release of tag v1.17.0",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var published *github.RepositoryRelease
+			client, server := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet && r.URL.Path == "/repos/kubernetes/kubernetes/releases/tags/v1.17.0" {
+					if !tc.existing {
+						w.WriteHeader(tc.status)
+						fmt.Fprint(w, `{"message": "failed"}`)
+						return
+					}
+					fmt.Fprint(w, `{"id": 42, "tag_name": "v1.17.0", "name": "Kubernetes v1.17.0"}`)
+					return
+				}
+				if r.Method != tc.method || r.URL.Path != tc.path {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+					http.NotFound(w, r)
+					return
+				}
+				published = &github.RepositoryRelease{}
+				require.Nil(t, json.NewDecoder(r.Body).Decode(published))
+				published.ID = github.Int64(42)
+				require.Nil(t, json.NewEncoder(w).Encode(published))
+			}))
+			defer server.Close()
+
+			release, err := PublishRelease(client, "v1.17.0", "the notes", true, false, WithOrg("kubernetes"), WithRepo("kubernetes"))
+			if tc.err != "" {
+				require.NotNil(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				require.Nil(t, published)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, int64(42), release.GetID())
+			require.Equal(t, "v1.17.0", published.GetTagName())
+			require.Equal(t, "the notes", published.GetBody())
+			require.True(t, published.GetDraft())
+			require.False(t, published.GetPrerelease())
+
+			// the name of an existing release is kept
+			if tc.existing {
+				require.Nil(t, published.Name)
+			} else {
+				require.Equal(t, "v1.17.0", published.GetName())
+			}
+		})
+	}
+}
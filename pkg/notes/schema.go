@@ -0,0 +1,167 @@
+package notes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+//go:generate go run genschema.go
+
+// JSONSchema returns the JSON Schema which describes the JSON encoding of a
+// ReleaseNoteList. Consumers can use it to validate the output independently.
+func JSONSchema() []byte {
+	schema := make([]byte, len(jsonSchema))
+	copy(schema, jsonSchema)
+	return schema
+}
+
+// ValidateJSON validates the given JSON encoded ReleaseNoteList against the
+// schema returned by JSONSchema.
+func ValidateJSON(data []byte) error {
+	root := &schema{}
+	if err := json.Unmarshal(jsonSchema, root); err != nil {
+		return errors.Wrap(err, "error parsing the JSON schema")
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return errors.Wrap(err, "error parsing the JSON document")
+	}
+
+	return root.validate(root, value, "$")
+}
+
+// schema is the subset of JSON Schema which is needed to describe the JSON
+// encoding of a ReleaseNoteList.
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Enum                 []interface{}      `json:"enum"`
+	Required             []string           `json:"required"`
+	Properties           map[string]*schema `json:"properties"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+	Items                *schema            `json:"items"`
+	Definitions          map[string]*schema `json:"definitions"`
+}
+
+// validate checks that value conforms to the schema s. References are
+// resolved against the definitions of root and path is used to point to the
+// offending value in errors.
+func (s *schema) validate(root *schema, value interface{}, path string) error {
+	if s.Ref != "" {
+		definition, ok := root.Definitions[strings.TrimPrefix(s.Ref, "#/definitions/")]
+		if !ok {
+			return errors.Errorf("%s: unknown schema reference %q", path, s.Ref)
+		}
+		return definition.validate(root, value, path)
+	}
+
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			if fmt.Sprint(e) == fmt.Sprint(value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.Errorf("%s: value %v is not one of %v", path, value, s.Enum)
+		}
+	}
+
+	switch s.Type {
+	case "":
+		return nil
+
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return errors.Errorf("%s: expected an object", path)
+		}
+		for _, name := range s.Required {
+			if _, ok := object[name]; !ok {
+				return errors.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+
+		keys := []string{}
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			propertyPath := fmt.Sprintf("%s.%s", path, key)
+			if property, ok := s.Properties[key]; ok {
+				if err := property.validate(root, object[key], propertyPath); err != nil {
+					return err
+				}
+				continue
+			}
+
+			additional := strings.TrimSpace(string(s.AdditionalProperties))
+			switch additional {
+			case "", "true":
+			case "false":
+				return errors.Errorf("%s: unexpected property", propertyPath)
+			default:
+				additionalSchema := &schema{}
+				if err := json.Unmarshal(s.AdditionalProperties, additionalSchema); err != nil {
+					return errors.Wrap(err, "error parsing the JSON schema")
+				}
+				if err := additionalSchema.validate(root, object[key], propertyPath); err != nil {
+					return err
+				}
+			}
+		}
+
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return errors.Errorf("%s: expected an array", path)
+		}
+		if s.Items != nil {
+			for i, item := range array {
+				if err := s.Items.validate(root, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+
+	case "string":
+		if _, ok := value.(string); !ok {
+			return errors.Errorf("%s: expected a string", path)
+		}
+
+	case "integer":
+		number, ok := value.(json.Number)
+		if !ok {
+			return errors.Errorf("%s: expected an integer", path)
+		}
+		if _, err := number.Int64(); err != nil {
+			return errors.Errorf("%s: expected an integer, got %s", path, number)
+		}
+
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			return errors.Errorf("%s: expected a number", path)
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return errors.Errorf("%s: expected a boolean", path)
+		}
+
+	default:
+		return errors.Errorf("%s: unsupported schema type %q", path, s.Type)
+	}
+
+	return nil
+}
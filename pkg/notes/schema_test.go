@@ -0,0 +1,81 @@
+package notes
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// populate recursively sets every field of v to a non-zero value, so that no
+// field is dropped by omitempty when encoding it
+func populate(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		v.Set(reflect.New(v.Type().Elem()))
+		populate(v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanSet() {
+				populate(v.Field(i))
+			}
+		}
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		populate(v.Index(0))
+	case reflect.Map:
+		v.Set(reflect.MakeMap(v.Type()))
+		key := reflect.New(v.Type().Key()).Elem()
+		populate(key)
+		value := reflect.New(v.Type().Elem()).Elem()
+		populate(value)
+		v.SetMapIndex(key, value)
+	case reflect.String:
+		v.SetString("external")
+	case reflect.Int, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Bool:
+		v.SetBool(true)
+	}
+}
+
+func TestValidateJSON(t *testing.T) {
+	note := &ReleaseNote{}
+	populate(reflect.ValueOf(note).Elem())
+
+	data, err := json.Marshal(ReleaseNoteList{note.PrNumber: note})
+	require.NoError(t, err)
+	require.NoError(t, ValidateJSON(data))
+
+	// an empty list is valid as well
+	require.NoError(t, ValidateJSON([]byte("{}")))
+}
+
+func TestValidateJSONFailures(t *testing.T) {
+	testCases := map[string]string{
+		"renamed field":    `{"1": {"commit": "", "text": "", "markdown": "", "author": "", "author_url": "", "pr_url": "", "prNumber": 1}}`,
+		"wrong type":       `{"1": {"commit": "", "text": "", "markdown": "", "author": "", "author_url": "", "pr_url": "", "pr_number": "1"}}`,
+		"invalid doc type": `{"1": {"commit": "", "text": "", "markdown": "", "author": "", "author_url": "", "pr_url": "", "pr_number": 1, "documentation": [{"url": "", "type": "foo"}]}}`,
+		"not an object":    `[]`,
+	}
+
+	for name, data := range testCases {
+		t.Run(name, func(t *testing.T) {
+			require.Error(t, ValidateJSON([]byte(data)))
+		})
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	schema := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(JSONSchema(), &schema))
+	require.Equal(t, "ReleaseNoteList", schema["title"])
+}
+
+func TestSchemaGenerated(t *testing.T) {
+	schema, err := ioutil.ReadFile("schema.json")
+	require.NoError(t, err)
+	require.Equal(t, string(schema), string(JSONSchema()), "schema_generated.go is out of date, run go generate ./pkg/notes")
+}
@@ -0,0 +1,50 @@
+package notes
+
+import (
+	"strings"
+)
+
+// DefaultSeeTitlePhrases are the notes which refer to the PR title instead of
+// describing the change themselves
+var DefaultSeeTitlePhrases = []string{"see pr title", "see title"}
+
+// WithResolveSeeTitle allows the caller to replace the notes which only refer
+// to the PR title, like "See PR title.", by the PR title. The phrases are
+// matched ignoring case, surrounding whitespace and trailing punctuation. If
+// no phrases are provided, DefaultSeeTitlePhrases are used.
+func WithResolveSeeTitle(phrases []string) GithubApiOption {
+	return func(c *githubApiConfig) {
+		if len(phrases) == 0 {
+			phrases = DefaultSeeTitlePhrases
+		}
+		c.seeTitlePhrases = phrases
+	}
+}
+
+// normalizeSeeTitle returns the text in the form the phrases are matched in
+func normalizeSeeTitle(text string) string {
+	return strings.ToLower(strings.TrimRight(strings.TrimSpace(text), ".!:; "))
+}
+
+// isSeeTitle reports whether the note text is one of the phrases
+func isSeeTitle(text string, phrases []string) bool {
+	normalized := normalizeSeeTitle(text)
+	for _, phrase := range phrases {
+		if normalized == normalizeSeeTitle(phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveSeeTitle replaces the text of a note which only refers to the PR
+// title by the title, escaped like the notes of the PR bodies, and reports
+// whether it did
+func resolveSeeTitle(note *ReleaseNote, phrases []string) bool {
+	if len(phrases) == 0 || note.PrTitle == "" || !isSeeTitle(note.Text, phrases) {
+		return false
+	}
+	note.Text = strings.ReplaceAll(strings.TrimSpace(note.PrTitle), "#", "&#35;")
+	note.TitleSubstituted = true
+	return true
+}
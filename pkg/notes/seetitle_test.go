@@ -0,0 +1,56 @@
+package notes
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsSeeTitle(t *testing.T) {
+	for text, expected := range map[string]bool{
+		"see pr title":      true,
+		"See PR title.":     true,
+		"  See title!  ":    true,
+		"SEE PR TITLE":      true,
+		"See the PR title":  false,
+		"See PR title, too": false,
+		"":                  false,
+	} {
+		require.Equal(t, expected, isSeeTitle(text, DefaultSeeTitlePhrases), text)
+	}
+	require.True(t, isSeeTitle("Same as title.", []string{"same as title"}))
+	require.False(t, isSeeTitle("See PR title", []string{"same as title"}))
+}
+
+func TestListReleaseNotesResolveSeeTitle(t *testing.T) {
+	fixtures := map[string]string{
+		"/repos/kubernetes/kubernetes/commits": "commits.json",
+	}
+	for i, sha := range []string{"aaa", "bbb", "ccc", "ddd"} {
+		fixtures["/repos/kubernetes/kubernetes/commits/"+sha+"/pulls"] = "commit-" + sha + "-pulls.json"
+		fixtures[fmt.Sprintf("/repos/kubernetes/kubernetes/pulls/%d", i+1)] = fmt.Sprintf("pull-%d.json", i+1)
+	}
+	client, server := newFixtureClient(t, "see-title", fixtures)
+	defer server.Close()
+
+	notes, err := ListReleaseNotes(client, log.NewNopLogger(), "master", "start", "end", "", "", WithResolveSeeTitle(nil))
+	require.Nil(t, err)
+	require.Len(t, notes, 4)
+	require.Equal(t, "Fix the kubelet restart loop", notes[1].Text)
+	require.True(t, notes[1].TitleSubstituted)
+	require.Contains(t, notes[1].Markdown, "Fix the kubelet restart loop")
+	require.Equal(t, "Add the &#35;scheduler profile flag", notes[2].Text)
+	require.True(t, notes[2].TitleSubstituted)
+	require.Equal(t, "Bump the etcd client", notes[3].Text)
+	require.True(t, notes[3].TitleSubstituted)
+	require.Equal(t, "See the PR title for the details of the deprecation", notes[4].Text)
+	require.False(t, notes[4].TitleSubstituted)
+
+	notes, err = ListReleaseNotes(client, log.NewNopLogger(), "master", "start", "end", "", "")
+	require.Nil(t, err)
+	require.Equal(t, "see pr title", notes[1].Text)
+	require.Equal(t, "See PR title.", notes[2].Text)
+	require.False(t, notes[2].TitleSubstituted)
+}
@@ -0,0 +1,117 @@
+package notes
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// SortKey is a key the notes within every section are ordered by
+type SortKey string
+
+const (
+	// SortKeyKind orders the notes by their kind, in the order of the kind
+	// priority if any, or else alphabetically. Notes without a kind come last.
+	SortKeyKind SortKey = "kind"
+
+	// SortKeyMergedAt orders the notes by their merge date, oldest first.
+	// Notes without a merge date, like the ones read from JSON, come last.
+	SortKeyMergedAt SortKey = "merged-at"
+
+	// SortKeyNumber orders the notes by their PR number
+	SortKeyNumber SortKey = "number"
+
+	// SortKeySize orders the notes by the size of their change, largest
+	// first. Notes without stats come last.
+	SortKeySize SortKey = "size"
+)
+
+// sortKeys are all supported sort keys
+var sortKeys = []SortKey{SortKeyKind, SortKeyMergedAt, SortKeyNumber, SortKeySize}
+
+// ParseSortKeys returns the sort keys with the provided names, or an error if
+// any of them is not supported
+func ParseSortKeys(names []string) ([]SortKey, error) {
+	keys := []SortKey{}
+	for _, name := range names {
+		key := SortKey(name)
+		supported := false
+		for _, k := range sortKeys {
+			supported = supported || k == key
+		}
+		if !supported {
+			return nil, errors.Errorf("%q is an unsupported sort key (options: kind, merged-at, number, size)", name)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// WithSortKeys allows the caller to order the notes within every section by
+// several keys, like SortKeyKind and then SortKeyMergedAt. The keys apply
+// after the kind priority, if any, and before WithSortBy. Notes which are equal
+// in all keys keep the order of their PR number.
+func WithSortKeys(keys []SortKey) DocumentOption {
+	return func(c *documentConfig) {
+		c.sortKeys = keys
+	}
+}
+
+// compareSortKey compares the notes by the key. It returns a negative number if
+// a comes first, a positive number if b comes first and zero if they are equal.
+func compareSortKey(a, b *ReleaseNote, key SortKey, c *documentConfig) int {
+	switch key {
+	case SortKeyKind:
+		if len(c.kindPriority) > 0 {
+			return kindRank(a, c.kindPriority) - kindRank(b, c.kindPriority)
+		}
+		ka, kb := firstKind(a), firstKind(b)
+		switch {
+		case ka == kb:
+			return 0
+		case ka == "":
+			return 1
+		case kb == "":
+			return -1
+		case ka < kb:
+			return -1
+		}
+		return 1
+	case SortKeyMergedAt:
+		switch {
+		case a.MergedAt.Equal(b.MergedAt):
+			return 0
+		case a.MergedAt.IsZero():
+			return 1
+		case b.MergedAt.IsZero():
+			return -1
+		case a.MergedAt.Before(b.MergedAt):
+			return -1
+		}
+		return 1
+	case SortKeyNumber:
+		return a.PrNumber - b.PrNumber
+	case SortKeySize:
+		switch {
+		case a.Stats == nil && b.Stats == nil:
+			return 0
+		case a.Stats == nil:
+			return 1
+		case b.Stats == nil:
+			return -1
+		}
+		return b.Stats.Size() - a.Stats.Size()
+	}
+	return 0
+}
+
+// firstKind returns the alphabetically first kind of the note, or an empty
+// string if it has none
+func firstKind(note *ReleaseNote) string {
+	if len(note.Kinds) == 0 {
+		return ""
+	}
+	kinds := append([]string{}, note.Kinds...)
+	sort.Strings(kinds)
+	return kinds[0]
+}
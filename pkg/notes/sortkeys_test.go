@@ -0,0 +1,50 @@
+package notes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSortKeys(t *testing.T) {
+	keys, err := ParseSortKeys([]string{"kind", "merged-at"})
+	require.Nil(t, err)
+	require.Equal(t, []SortKey{SortKeyKind, SortKeyMergedAt}, keys)
+
+	_, err = ParseSortKeys([]string{"kind", "author"})
+	require.NotNil(t, err)
+}
+
+func TestSortKeys(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2019, 1, d, 0, 0, 0, 0, time.UTC)
+	}
+	notes := ReleaseNoteList{
+		1: {PrNumber: 1, Kinds: []string{"feature"}, MergedAt: day(3)},
+		2: {PrNumber: 2, Kinds: []string{"bug"}, MergedAt: day(5)},
+		3: {PrNumber: 3, MergedAt: day(1)},
+		4: {PrNumber: 4, Kinds: []string{"feature"}, MergedAt: day(2)},
+		5: {PrNumber: 5, Kinds: []string{"bug"}},
+		6: {PrNumber: 6, Kinds: []string{"bug"}, MergedAt: day(4)},
+	}
+	numbers := func(opts ...DocumentOption) []int {
+		result := []int{}
+		for _, note := range sortedNotes(notes, opts...) {
+			result = append(result, note.PrNumber)
+		}
+		return result
+	}
+
+	require.Equal(t, []int{1, 2, 3, 4, 5, 6}, numbers())
+
+	// notes without a kind or merge date come last
+	require.Equal(t, []int{6, 2, 5, 4, 1, 3}, numbers(WithSortKeys([]SortKey{SortKeyKind, SortKeyMergedAt})))
+	require.Equal(t, []int{3, 4, 1, 6, 2, 5}, numbers(WithSortKeys([]SortKey{SortKeyMergedAt})))
+
+	// the kind follows the kind priority
+	require.Equal(t, []int{4, 1, 6, 2, 5, 3}, numbers(
+		WithKindPriority([]string{"feature", "bug"}),
+		WithSortKeys([]SortKey{SortKeyKind, SortKeyMergedAt}),
+	))
+}
@@ -0,0 +1,143 @@
+package notes
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// markdownBoundaries are the positions the markdown may be split at, from the
+// preferred top level sections over their groups, like the SIGs, to the notes
+var markdownBoundaries = []*regexp.Regexp{
+	regexp.MustCompile(`(?m)^(?:<a id="[^"]*"></a>\n)?## `),
+	regexp.MustCompile(`(?m)^(?:<a id="[^"]*"></a>\n)?### `),
+	regexp.MustCompile(`(?m)^- `),
+}
+
+// markdownPieces splits the markdown at the boundaries of the level into
+// pieces of at most maxBytes, splitting the larger pieces at the boundaries of
+// the next levels
+func markdownPieces(markdown []byte, level, maxBytes int) ([][]byte, error) {
+	if len(markdown) <= maxBytes {
+		return [][]byte{markdown}, nil
+	}
+	if level == len(markdownBoundaries) {
+		return nil, errors.Errorf("a note or heading of %d bytes exceeds the limit of %d bytes", len(markdown), maxBytes)
+	}
+
+	starts := []int{0}
+	for _, match := range markdownBoundaries[level].FindAllIndex(markdown, -1) {
+		if match[0] > 0 {
+			starts = append(starts, match[0])
+		}
+	}
+	starts = append(starts, len(markdown))
+
+	pieces := [][]byte{}
+	for i := 0; i < len(starts)-1; i++ {
+		split, err := markdownPieces(markdown[starts[i]:starts[i+1]], level+1, maxBytes)
+		if err != nil {
+			return nil, err
+		}
+		pieces = append(pieces, split...)
+	}
+	return pieces, nil
+}
+
+// SplitMarkdown splits the markdown into consecutive parts of at most maxBytes
+// each. The parts end at a section boundary where possible, then at the
+// boundary of a group, like a SIG, and never within a note. A single note or
+// heading which exceeds the limit is an error.
+func SplitMarkdown(markdown []byte, maxBytes int) ([][]byte, error) {
+	pieces, err := markdownPieces(markdown, 0, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := [][]byte{}
+	part := []byte{}
+	for _, piece := range pieces {
+		if len(part)+len(piece) > maxBytes && len(part) > 0 {
+			parts = append(parts, part)
+			part = []byte{}
+		}
+		part = append(part, piece...)
+	}
+	if len(part) > 0 {
+		parts = append(parts, part)
+	}
+	return parts, nil
+}
+
+// keyedJSONOverhead is the size of the braces of the JSON object rendered by
+// RenderKeyedJSON, and keyedJSONSeparator the size of the separator between
+// two of its notes
+const (
+	keyedJSONOverhead  = len("{\n\n}\n")
+	keyedJSONSeparator = len(",\n")
+)
+
+// SplitKeyedJSON renders the notes like RenderKeyedJSON, but split into
+// several JSON objects of at most maxBytes each, which all conform to the
+// schema of the JSON output. The notes are assigned to the parts in the order
+// of their keys. A single note which exceeds the limit is an error.
+func SplitKeyedJSON(notes KeyedReleaseNotes, maxBytes int) ([][]byte, error) {
+	keys := make([]string, 0, len(notes))
+	for key := range notes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := [][]byte{}
+	part := KeyedReleaseNotes{}
+	size := keyedJSONOverhead
+	flush := func() error {
+		content, err := RenderKeyedJSON(part)
+		if err != nil {
+			return err
+		}
+		parts = append(parts, content)
+		part = KeyedReleaseNotes{}
+		size = keyedJSONOverhead
+		return nil
+	}
+
+	for _, key := range keys {
+		single, err := RenderKeyedJSON(KeyedReleaseNotes{key: notes[key]})
+		if err != nil {
+			return nil, err
+		}
+		// the entry of the note without the braces of its object
+		entry := len(single) - keyedJSONOverhead
+		if keyedJSONOverhead+entry > maxBytes {
+			return nil, errors.Errorf("the note %s of %d bytes exceeds the limit of %d bytes", key, len(single), maxBytes)
+		}
+
+		added := entry
+		if len(part) > 0 {
+			added += keyedJSONSeparator
+		}
+		if size+added > maxBytes {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			added = entry
+		}
+		part[key] = notes[key]
+		size += added
+	}
+	if len(part) > 0 || len(parts) == 0 {
+		if err := flush(); err != nil {
+			return nil, err
+		}
+	}
+
+	// the sizes are computed, so the rendered parts are checked once more
+	for i, content := range parts {
+		if len(content) > maxBytes {
+			return nil, errors.Errorf("part %d of %d bytes exceeds the limit of %d bytes", i+1, len(content), maxBytes)
+		}
+	}
+	return parts, nil
+}
@@ -0,0 +1,81 @@
+package notes
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitMarkdown(t *testing.T) {
+	notes := ReleaseNoteList{}
+	for i := 1; i <= 6; i++ {
+		notes[i] = &ReleaseNote{PrNumber: i, Markdown: fmt.Sprintf("note number %d", i), SIGs: []string{fmt.Sprintf("sig-%d", i%3)}}
+	}
+	notes[7] = &ReleaseNote{PrNumber: 7, Markdown: "a feature", Feature: true}
+	doc, err := CreateDocument(notes, WithStableAnchors())
+	require.Nil(t, err)
+	buf := &bytes.Buffer{}
+	require.Nil(t, RenderMarkdown(doc, buf, WithStableAnchors()))
+	markdown := buf.Bytes()
+
+	parts, err := SplitMarkdown(markdown, len(markdown))
+	require.Nil(t, err)
+	require.Len(t, parts, 1)
+
+	// the SIG section is split between its SIGs
+	parts, err = SplitMarkdown(markdown, 120)
+	require.Nil(t, err)
+	require.True(t, len(parts) > 1)
+	require.Equal(t, string(markdown), string(bytes.Join(parts, nil)))
+	for _, part := range parts {
+		require.True(t, len(part) <= 120)
+		require.True(t, strings.HasPrefix(string(part), "<a id="), string(part))
+	}
+
+	// then between its notes
+	parts, err = SplitMarkdown(markdown, 60)
+	require.Nil(t, err)
+	require.Equal(t, string(markdown), string(bytes.Join(parts, nil)))
+	for _, part := range parts {
+		require.True(t, len(part) <= 60)
+	}
+
+	_, err = SplitMarkdown(markdown, 10)
+	require.NotNil(t, err)
+}
+
+func TestSplitKeyedJSON(t *testing.T) {
+	notes := KeyedReleaseNotes{}
+	for i := 1; i <= 10; i++ {
+		note := &ReleaseNote{PrNumber: i, PrUrl: fmt.Sprintf("https://github.com/kubernetes/kubernetes/pull/%d", i), Text: "a note"}
+		notes[NoteKey(note)] = note
+	}
+	content, err := RenderKeyedJSON(notes)
+	require.Nil(t, err)
+
+	parts, err := SplitKeyedJSON(notes, len(content))
+	require.Nil(t, err)
+	require.Len(t, parts, 1)
+	require.Equal(t, string(content), string(parts[0]))
+
+	parts, err = SplitKeyedJSON(notes, len(content)/3)
+	require.Nil(t, err)
+	require.True(t, len(parts) >= 3)
+	merged := KeyedReleaseNotes{}
+	for _, part := range parts {
+		require.True(t, len(part) <= len(content)/3)
+		require.Nil(t, ValidateJSON(part))
+		parsed, err := ParseKeyedReleaseNotes(part)
+		require.Nil(t, err)
+		for key, note := range parsed {
+			merged[key] = note
+		}
+	}
+	require.Equal(t, notes, merged)
+
+	_, err = SplitKeyedJSON(notes, 20)
+	require.NotNil(t, err)
+}
@@ -0,0 +1,126 @@
+package notes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-kit/kit/log"
+	"github.com/google/go-github/v27/github"
+)
+
+// Stats is the size of the change of a PR
+type Stats struct {
+	Additions    int `json:"additions"`
+	Deletions    int `json:"deletions"`
+	ChangedFiles int `json:"changed_files"`
+}
+
+// Size is the number of changed lines, which is the magnitude notes are
+// ranked by with SortBySize
+func (s *Stats) Size() int {
+	if s == nil {
+		return 0
+	}
+	return s.Additions + s.Deletions
+}
+
+// NoteOrder is the order of the notes within the sections of a document
+type NoteOrder string
+
+const (
+	// SortByNumber orders the notes by their PR number, the default
+	SortByNumber NoteOrder = "number"
+
+	// SortBySize orders the notes by the size of their change, largest
+	// first. Notes without stats come last.
+	SortBySize NoteOrder = "size"
+)
+
+// WithStats allows the caller to fetch the additions, deletions and changed
+// files of every PR into the Stats of its note. The stats of notes which are
+// read from the PR cost nothing, while the other notes cost one additional
+// API request per PR, so these are only fetched for the first limit PRs
+// unless limit is zero. The budget is shared by all calls which are passed the
+// same option.
+func WithStats(limit int) GithubApiOption {
+	var budget *inferenceBudget
+	if limit > 0 {
+		budget = &inferenceBudget{remaining: limit}
+	}
+	return func(c *githubApiConfig) {
+		c.fetchStats = true
+		c.statsBudget = budget
+	}
+}
+
+// WithShowStats allows the caller to append the additions and deletions of
+// the notes with stats to their markdown, like "(+120 −30)"
+func WithShowStats() DocumentOption {
+	return func(c *documentConfig) {
+		c.showStats = true
+	}
+}
+
+// WithSortBy allows the caller to order the notes within every section, after
+// their kind priority if any
+func WithSortBy(order NoteOrder) DocumentOption {
+	return func(c *documentConfig) {
+		c.sortBy = order
+	}
+}
+
+// statsFromPR returns the stats of the PR
+func statsFromPR(pr *github.PullRequest) *Stats {
+	return &Stats{
+		Additions:    pr.GetAdditions(),
+		Deletions:    pr.GetDeletions(),
+		ChangedFiles: pr.GetChangedFiles(),
+	}
+}
+
+// PRStats returns the stats of the PR with the provided number
+func PRStats(client *github.Client, number int, opts ...GithubApiOption) (*Stats, error) {
+	c := configFromOpts(opts...)
+
+	pr, _, err := client.PullRequests.Get(c.ctx, c.org, c.repo, number)
+	if err != nil {
+		return nil, err
+	}
+	return statsFromPR(pr), nil
+}
+
+// noteStats returns the stats of the PR of the note if they were requested
+// and the budget allows. Failures are logged and result in no stats, because
+// the stats are informational only.
+func noteStats(client *github.Client, logger log.Logger, number int, opts ...GithubApiOption) *Stats {
+	c := configFromOpts(opts...)
+	if !c.fetchStats {
+		return nil
+	}
+	if c.statsBudget != nil {
+		ok, exhausted := c.statsBudget.take()
+		if exhausted {
+			warnPR(logger, c, WarningBudgetExhausted, number, "stats budget exhausted, the stats of the remaining PRs are not fetched", nil)
+		}
+		if !ok {
+			return nil
+		}
+	}
+
+	stats, err := PRStats(client, number, opts...)
+	if err != nil {
+		warnPR(logger, c, WarningFetchFailed, number, "error fetching the stats of the PR", err)
+		return nil
+	}
+	return stats
+}
+
+// statsSuffix appends the additions and deletions to the first line of the
+// markdown, so that they are not separated from the note by its description
+func statsSuffix(markdown string, stats *Stats) string {
+	suffix := fmt.Sprintf(" (+%d −%d)", stats.Additions, stats.Deletions)
+	if i := strings.Index(markdown, "\n"); i >= 0 {
+		return markdown[:i] + suffix + markdown[i:]
+	}
+	return markdown + suffix
+}
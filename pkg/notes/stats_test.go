@@ -0,0 +1,73 @@
+package notes
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoteStats(t *testing.T) {
+	requests := 0
+	client, server := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/repos/kubernetes/kubernetes/pulls/1":
+			fmt.Fprint(w, `{"number": 1, "additions": 120, "deletions": 30, "changed_files": 4}`)
+		case "/repos/kubernetes/kubernetes/pulls/2":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	expected := &Stats{Additions: 120, Deletions: 30, ChangedFiles: 4}
+	stats, err := PRStats(client, 1)
+	require.Nil(t, err)
+	require.Equal(t, expected, stats)
+	requests = 0
+
+	// the stats are only fetched if requested
+	require.Nil(t, noteStats(client, log.NewNopLogger(), 1))
+	require.Equal(t, 0, requests)
+
+	// failures degrade to no stats
+	opts := []GithubApiOption{WithStats(2)}
+	require.Nil(t, noteStats(client, log.NewNopLogger(), 2, opts...))
+	require.Equal(t, expected, noteStats(client, log.NewNopLogger(), 1, opts...))
+	require.Equal(t, 2, requests)
+
+	// the budget is exhausted
+	require.Nil(t, noteStats(client, log.NewNopLogger(), 1, opts...))
+	require.Equal(t, 2, requests)
+}
+
+func TestRenderStats(t *testing.T) {
+	notes := ReleaseNoteList{
+		1: {PrNumber: 1, Markdown: "- Small ([#1](url))", Stats: &Stats{Additions: 3, Deletions: 1}},
+		2: {PrNumber: 2, Markdown: "- Large ([#2](url))\n  - The description", Stats: &Stats{Additions: 120, Deletions: 30}},
+		3: {PrNumber: 3, Markdown: "- Unknown ([#3](url))"},
+	}
+
+	render := func(opts ...DocumentOption) string {
+		doc, err := CreateDocument(notes, opts...)
+		require.Nil(t, err)
+		out := &bytes.Buffer{}
+		require.Nil(t, RenderMarkdown(doc, out, opts...))
+		return out.String()
+	}
+
+	require.Equal(t, "## Other Notable Changes\n\n"+
+		"- Small ([#1](url))\n"+
+		"- Large ([#2](url))\n  - The description\n"+
+		"- Unknown ([#3](url))\n\n\n", render())
+
+	require.Equal(t, "## Other Notable Changes\n\n"+
+		"- Large ([#2](url)) (+120 −30)\n  - The description\n"+
+		"- Small ([#1](url)) (+3 −1)\n"+
+		"- Unknown ([#3](url))\n\n\n", render(WithShowStats(), WithSortBy(SortBySize)))
+}
@@ -0,0 +1,103 @@
+package notes
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WithEnforceStyle allows the caller to render every note capitalized and
+// ending with a period, as required by most style guides. Only the rendered
+// output is changed, the notes themselves are not.
+func WithEnforceStyle() DocumentOption {
+	return func(c *documentConfig) {
+		c.enforceStyle = true
+	}
+}
+
+// StyleText returns the text starting with a capital letter and ending with a
+// period. Texts which start with an identifier, like "kube-proxy" or a code
+// span, are not capitalized. Text which ends with a code block is left alone,
+// while a period is appended after closing parentheses and code spans unless
+// they are preceded by terminal punctuation, like in "(see the docs.)".
+func StyleText(text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return text
+	}
+	if needsCapital(text) {
+		r, size := utf8.DecodeRuneInString(text)
+		text = string(unicode.ToUpper(r)) + text[size:]
+	}
+	if needsPeriod(text) {
+		text += "."
+	}
+	return text
+}
+
+// StyleViolations returns the reasons why the text does not conform to the
+// style enforced by StyleText, or nothing if it conforms
+func StyleViolations(text string) []string {
+	text = strings.TrimSpace(text)
+	violations := []string{}
+	if text == "" {
+		return violations
+	}
+	if needsCapital(text) {
+		violations = append(violations, "does not start with a capital letter")
+	}
+	if needsPeriod(text) {
+		violations = append(violations, "does not end with a period")
+	}
+	return violations
+}
+
+// needsCapital returns whether the first word of the text is a lowercase word
+// which is not an identifier
+func needsCapital(text string) bool {
+	word := strings.FieldsFunc(text, unicode.IsSpace)[0]
+	r, _ := utf8.DecodeRuneInString(word)
+	if !unicode.IsLower(r) {
+		return false
+	}
+	for _, r := range word {
+		if unicode.IsUpper(r) || unicode.IsDigit(r) || strings.ContainsRune("-_./`", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// needsPeriod returns whether the text lacks terminal punctuation
+func needsPeriod(text string) bool {
+	if strings.HasSuffix(text, "```") {
+		return false
+	}
+	if strings.HasSuffix(text, "`") {
+		return true
+	}
+	trimmed := strings.TrimRight(text, ")]\"'*_")
+	if trimmed == "" {
+		return false
+	}
+	last, _ := utf8.DecodeLastRuneInString(trimmed)
+	return !strings.ContainsRune(".!?:", last)
+}
+
+// styledNote returns a copy of the note whose text and markdown conform to
+// the enforced style. The markdown is only changed if it starts with the text,
+// which is the case unless it was edited by hand.
+func styledNote(note *ReleaseNote) *ReleaseNote {
+	styled := *note
+	styled.Text = StyleText(note.Text)
+	if styled.Text == note.Text {
+		return note
+	}
+
+	indented := strings.ReplaceAll(strings.TrimSpace(note.Text), "\n", "\n  ")
+	markdown := strings.TrimLeft(note.Markdown, " ")
+	if strings.HasPrefix(markdown, indented) {
+		styled.Markdown = strings.ReplaceAll(styled.Text, "\n", "\n  ") + markdown[len(indented):]
+	}
+	return &styled
+}
@@ -0,0 +1,51 @@
+package notes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStyleText(t *testing.T) {
+	for _, tc := range []struct {
+		text       string
+		styled     string
+		violations []string
+	}{
+		{"Fix the kubelet.", "Fix the kubelet.", []string{}},
+		{"fix the kubelet", "Fix the kubelet.", []string{"does not start with a capital letter", "does not end with a period"}},
+		{"Fix the kubelet (see #123)", "Fix the kubelet (see #123).", []string{"does not end with a period"}},
+		{"Fix the kubelet (see the docs.)", "Fix the kubelet (see the docs.)", []string{}},
+		{"Rename `foo.bar`", "Rename `foo.bar`.", []string{"does not end with a period"}},
+		{"`kubectl apply` is faster!", "`kubectl apply` is faster!", []string{}},
+		{"kube-proxy supports IPVS", "kube-proxy supports IPVS.", []string{"does not end with a period"}},
+		{"iSCSI volumes are mounted read-only.", "iSCSI volumes are mounted read-only.", []string{}},
+		{"The following flags are removed:", "The following flags are removed:", []string{}},
+		{"Run this:\n```\nkubectl apply\n```", "Run this:\n```\nkubectl apply\n```", []string{}},
+		{"  über cool  ", "Über cool.", []string{"does not start with a capital letter", "does not end with a period"}},
+	} {
+		require.Equal(t, tc.styled, StyleText(tc.text), tc.text)
+		require.Equal(t, tc.violations, StyleViolations(tc.text), tc.text)
+	}
+}
+
+func TestEnforceStyle(t *testing.T) {
+	note := &ReleaseNote{
+		Text:     "fix the kubelet\nwhen restarting",
+		Markdown: "fix the kubelet\n  when restarting ([#1](https://github.com/kubernetes/kubernetes/pull/1), [@alice](https://github.com/alice))",
+		PrNumber: 1,
+	}
+
+	item, err := noteListItem(note, documentConfigFromOpts(WithEnforceStyle()))
+	require.Nil(t, err)
+	require.Equal(t, "Fix the kubelet\n  when restarting. ([#1](https://github.com/kubernetes/kubernetes/pull/1), [@alice](https://github.com/alice))", item)
+
+	// the note itself is not changed
+	require.Equal(t, "fix the kubelet\nwhen restarting", note.Text)
+
+	// hand edited markdown is left alone
+	note.Markdown = "Something else"
+	item, err = noteListItem(note, documentConfigFromOpts(WithEnforceStyle()))
+	require.Nil(t, err)
+	require.Equal(t, "Something else", item)
+}
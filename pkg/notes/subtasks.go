@@ -0,0 +1,68 @@
+package notes
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Subtask is an item of a task list in the body of a PR, like the tracking
+// issues of an enhancement
+type Subtask struct {
+	Text string `json:"text"`
+	Done bool   `json:"done,omitempty"`
+}
+
+// WithSubtasks allows the caller to extract the task list items of the PR
+// bodies, like "- [x] #1234", into the subtasks of the notes, which are
+// rendered as a task list under every note in GitHub Flavored Markdown.
+func WithSubtasks() GithubApiOption {
+	return func(c *githubApiConfig) {
+		c.includeSubtasks = true
+	}
+}
+
+var (
+	// taskListItem matches a task list item, like "- [ ] text" or "* [x] text"
+	taskListItem = regexp.MustCompile(`^\s*[-*+]\s+\[([ xX])\]\s+(\S.*)$`)
+
+	// htmlComment matches the comments of PR templates, which may contain
+	// example task lists
+	htmlComment = regexp.MustCompile(`(?s)<!--.*?-->`)
+)
+
+// SubtasksFromString returns the task list items of a PR body in their
+// order. Items within code blocks and HTML comments are ignored.
+func SubtasksFromString(s string) []Subtask {
+	subtasks := []Subtask{}
+	fenced := false
+	for _, line := range strings.Split(htmlComment.ReplaceAllString(s, ""), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fenced = !fenced
+			continue
+		}
+		if fenced {
+			continue
+		}
+		if match := taskListItem.FindStringSubmatch(line); match != nil {
+			subtasks = append(subtasks, Subtask{
+				Text: strings.TrimSpace(match[2]),
+				Done: match[1] != " ",
+			})
+		}
+	}
+	return subtasks
+}
+
+// subtasksMarkdown renders the subtasks as a task list nested under a note
+func subtasksMarkdown(subtasks []Subtask) string {
+	var b strings.Builder
+	for _, subtask := range subtasks {
+		check := " "
+		if subtask.Done {
+			check = "x"
+		}
+		b.WriteString("\n  - [" + check + "] " + subtask.Text)
+	}
+	return b.String()
+}
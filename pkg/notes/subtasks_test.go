@@ -0,0 +1,45 @@
+package notes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubtasksFromString(t *testing.T) {
+	body := "Tracks the graduation of the feature.\n\n" +
+		"- [x] Alpha: kubernetes/enhancements#1234\n" +
+		"- [ ] Beta: #1235\n" +
+		"  * [X] docs\n" +
+		"- not a task\n" +
+		"<!--\n- [ ] example of the PR template\n-->\n" +
+		"```release-note\n- [ ] a list in the note\n```\n"
+
+	require.Equal(t, []Subtask{
+		{Text: "Alpha: kubernetes/enhancements#1234", Done: true},
+		{Text: "Beta: #1235"},
+		{Text: "docs", Done: true},
+	}, SubtasksFromString(body))
+	require.Empty(t, SubtasksFromString("no tasks\n- [ ]"))
+}
+
+func TestSubtasksMarkdown(t *testing.T) {
+	note := &ReleaseNote{
+		PrNumber: 1,
+		Markdown: "Graduate the foo feature ([#1](https://github.com/kubernetes/kubernetes/pull/1), [@alice](https://github.com/alice))",
+		Subtasks: []Subtask{{Text: "Alpha", Done: true}, {Text: "Beta"}},
+	}
+
+	item, err := noteListItem(note, documentConfigFromOpts())
+	require.Nil(t, err)
+	require.Equal(t, note.Markdown+"\n  - [x] Alpha\n  - [ ] Beta", item)
+
+	item, err = noteListItem(note, documentConfigFromOpts(WithMarkdownFlavor(FlavorCommonMark)))
+	require.Nil(t, err)
+	require.Equal(t, note.Markdown, item)
+
+	content, err := RenderToBytes(ReleaseNoteList{1: note}, "json")
+	require.Nil(t, err)
+	require.Nil(t, ValidateJSON(content))
+	require.Contains(t, string(content), `"done": true`)
+}
@@ -0,0 +1,125 @@
+package notes
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Summary counts the notes of a list
+type Summary struct {
+	// Total is the number of notes
+	Total int `json:"total"`
+
+	// ActionRequired is the number of notes which require an action
+	ActionRequired int `json:"action_required"`
+
+	// Kinds is the number of notes per kind label, like "bug"
+	Kinds map[string]int `json:"kinds"`
+}
+
+// Summarize counts the notes of the provided list in total, per kind and how
+// many of them require an action.
+func Summarize(notes ReleaseNoteList) *Summary {
+	summary := &Summary{Kinds: map[string]int{}}
+	for _, note := range notes {
+		summary.Total++
+		if note.ActionRequired {
+			summary.ActionRequired++
+		}
+		for _, kind := range note.Kinds {
+			summary.Kinds[kind]++
+		}
+	}
+	return summary
+}
+
+// BadgeStyle selects how RenderBadgesMarkdown renders the counts
+type BadgeStyle string
+
+const (
+	// BadgeStyleShields renders the counts as shields.io image badges
+	BadgeStyleShields BadgeStyle = "shields"
+
+	// BadgeStyleStatic renders the counts as plain text, which works offline
+	BadgeStyleStatic BadgeStyle = "static"
+)
+
+// DefaultBadgeColors are the badge colors per kind, with "total" and
+// "action-required" for the respective counts. Kinds without a color are
+// rendered in blue.
+var DefaultBadgeColors = map[string]string{
+	"total":           "lightgrey",
+	"action-required": "red",
+	"feature":         "green",
+	"bug":             "orange",
+}
+
+// badgeLabels are the labels of the well known counts
+var badgeLabels = map[string]string{
+	"total":           "Total",
+	"action-required": "Action Required",
+	"feature":         "Features",
+	"bug":             "Bug Fixes",
+}
+
+// RenderBadgesMarkdown writes the counts of the summary as a single line of
+// badges to the supplied io.Writer. The colors override the DefaultBadgeColors
+// and are ignored for the static style.
+func RenderBadgesMarkdown(summary *Summary, style BadgeStyle, colors map[string]string, w io.Writer) error {
+	counts := map[string]int{
+		"total":           summary.Total,
+		"action-required": summary.ActionRequired,
+	}
+	kinds := []string{}
+	for kind, count := range summary.Kinds {
+		counts[kind] = count
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	badges := []string{}
+	for _, name := range append([]string{"total", "action-required"}, kinds...) {
+		label, ok := badgeLabels[name]
+		if !ok {
+			label = name
+		}
+
+		switch style {
+		case BadgeStyleStatic:
+			badges = append(badges, fmt.Sprintf("%s: %d", label, counts[name]))
+		case BadgeStyleShields:
+			color := colors[name]
+			if color == "" {
+				color = DefaultBadgeColors[name]
+			}
+			if color == "" {
+				color = "blue"
+			}
+			badges = append(badges, fmt.Sprintf(
+				"![%s: %d](https://img.shields.io/badge/%s-%d-%s)",
+				label, counts[name], shieldsEscape(label), counts[name], url.PathEscape(color),
+			))
+		default:
+			return errors.Errorf("%q is an unsupported badge style", style)
+		}
+	}
+
+	separator := " "
+	if style == BadgeStyleStatic {
+		separator = " | "
+	}
+	_, err := io.WriteString(w, strings.Join(badges, separator)+"\n\n")
+	return err
+}
+
+// shieldsEscape escapes the dashes, underscores and spaces which are special
+// within the path of a shields.io badge
+func shieldsEscape(s string) string {
+	s = strings.NewReplacer("-", "--", "_", "__", " ", "_").Replace(s)
+	return url.PathEscape(s)
+}
@@ -0,0 +1,45 @@
+package notes
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarize(t *testing.T) {
+	summary := Summarize(ReleaseNoteList{
+		1: &ReleaseNote{Kinds: []string{"feature"}},
+		2: &ReleaseNote{Kinds: []string{"bug"}, ActionRequired: true},
+		3: &ReleaseNote{Kinds: []string{"bug", "api-change"}},
+		4: &ReleaseNote{},
+	})
+
+	require.Equal(t, 4, summary.Total)
+	require.Equal(t, 1, summary.ActionRequired)
+	require.Equal(t, map[string]int{"feature": 1, "bug": 2, "api-change": 1}, summary.Kinds)
+}
+
+func TestRenderBadgesMarkdown(t *testing.T) {
+	summary := &Summary{
+		Total:          3,
+		ActionRequired: 1,
+		Kinds:          map[string]int{"feature": 2, "api-change": 1},
+	}
+
+	shields := &bytes.Buffer{}
+	require.Nil(t, RenderBadgesMarkdown(summary, BadgeStyleShields, map[string]string{"feature": "brightgreen"}, shields))
+	require.Equal(t,
+		"![Total: 3](https://img.shields.io/badge/Total-3-lightgrey) "+
+			"![Action Required: 1](https://img.shields.io/badge/Action_Required-1-red) "+
+			"![api-change: 1](https://img.shields.io/badge/api--change-1-blue) "+
+			"![Features: 2](https://img.shields.io/badge/Features-2-brightgreen)\n\n",
+		shields.String(),
+	)
+
+	static := &bytes.Buffer{}
+	require.Nil(t, RenderBadgesMarkdown(summary, BadgeStyleStatic, nil, static))
+	require.Equal(t, "Total: 3 | Action Required: 1 | api-change: 1 | Features: 2\n\n", static.String())
+
+	require.NotNil(t, RenderBadgesMarkdown(summary, "fancy", nil, &bytes.Buffer{}))
+}
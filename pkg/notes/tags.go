@@ -0,0 +1,47 @@
+package notes
+
+import (
+	"net/http"
+
+	"github.com/google/go-github/v27/github"
+	"github.com/pkg/errors"
+)
+
+// maxTagIndirections limits the chain of annotated tags which point to other
+// tags, which is followed until the commit is reached
+const maxTagIndirections = 5
+
+// TagCommitSHA returns the SHA of the commit the tag points to, as resolved
+// with the refs API, so that no clone is needed. The ref of a lightweight tag
+// points to the commit, while the ref of an annotated tag points to the tag
+// object, which is fetched to find the commit.
+func TagCommitSHA(client *github.Client, tag string, opts ...GithubApiOption) (string, error) {
+	c := configFromOpts(opts...)
+
+	ref, resp, err := client.Git.GetRef(c.ctx, c.org, c.repo, "tags/"+tag)
+	if err != nil {
+		err = errors.Wrapf(err, "error getting the ref of the tag %s", tag)
+		// GetRef replaces the error response of a missing ref by its own
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return "", &Error{Cause: ErrInvalidRange, Err: err}
+		}
+		return "", classifyError(err, ErrInvalidRange)
+	}
+
+	object := ref.GetObject()
+	for i := 0; i < maxTagIndirections; i++ {
+		switch object.GetType() {
+		case "commit":
+			return object.GetSHA(), nil
+		case "tag":
+			annotated, _, err := client.Git.GetTag(c.ctx, c.org, c.repo, object.GetSHA())
+			if err != nil {
+				return "", classifyError(errors.Wrapf(err, "error getting the annotated tag %s", tag), nil)
+			}
+			object = annotated.GetObject()
+		default:
+			return "", errors.Errorf("the tag %s points to a %s instead of a commit", tag, object.GetType())
+		}
+	}
+	return "", errors.Errorf("the tag %s points to more than %d other tags", tag, maxTagIndirections)
+}
@@ -0,0 +1,57 @@
+package notes
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagCommitSHA(t *testing.T) {
+	responses := map[string]string{
+		// a lightweight tag points to the commit
+		"/repos/kubernetes/kubernetes/git/refs/tags/v1.0.0": `{"ref": "refs/tags/v1.0.0", "object": {"type": "commit", "sha": "aaa"}}`,
+		// an annotated tag points to the tag object
+		"/repos/kubernetes/kubernetes/git/refs/tags/v1.1.0": `{"ref": "refs/tags/v1.1.0", "object": {"type": "tag", "sha": "t11"}}`,
+		"/repos/kubernetes/kubernetes/git/tags/t11":         `{"sha": "t11", "object": {"type": "commit", "sha": "bbb"}}`,
+		// a tag of a tag
+		"/repos/kubernetes/kubernetes/git/refs/tags/v1.2.0": `{"ref": "refs/tags/v1.2.0", "object": {"type": "tag", "sha": "t12"}}`,
+		"/repos/kubernetes/kubernetes/git/tags/t12":         `{"sha": "t12", "object": {"type": "tag", "sha": "t11"}}`,
+		// a tag of a tree
+		"/repos/kubernetes/kubernetes/git/refs/tags/tree": `{"ref": "refs/tags/tree", "object": {"type": "tree", "sha": "ccc"}}`,
+		// a tag of itself
+		"/repos/kubernetes/kubernetes/git/refs/tags/loop": `{"ref": "refs/tags/loop", "object": {"type": "tag", "sha": "loop"}}`,
+		"/repos/kubernetes/kubernetes/git/tags/loop":      `{"sha": "loop", "object": {"type": "tag", "sha": "loop"}}`,
+	}
+	client, server := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, response)
+	}))
+	defer server.Close()
+
+	sha, err := TagCommitSHA(client, "v1.0.0")
+	require.Nil(t, err)
+	require.Equal(t, "aaa", sha)
+
+	sha, err = TagCommitSHA(client, "v1.1.0")
+	require.Nil(t, err)
+	require.Equal(t, "bbb", sha)
+
+	sha, err = TagCommitSHA(client, "v1.2.0")
+	require.Nil(t, err)
+	require.Equal(t, "bbb", sha)
+
+	_, err = TagCommitSHA(client, "tree")
+	require.NotNil(t, err)
+
+	_, err = TagCommitSHA(client, "loop")
+	require.NotNil(t, err)
+
+	_, err = TagCommitSHA(client, "v9.9.9")
+	require.Equal(t, ErrInvalidRange, ErrorCause(err))
+}
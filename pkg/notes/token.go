@@ -0,0 +1,52 @@
+package notes
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// tokenExpirationHeader is set by GitHub on responses to requests which were
+// authenticated with an expiring token, like a fine-grained access token
+const tokenExpirationHeader = "GitHub-Authentication-Token-Expiration"
+
+// tokenScopesHeader lists the scopes of a classic OAuth or personal access
+// token. Fine-grained tokens have permissions instead and omit the header.
+const tokenScopesHeader = "X-OAuth-Scopes"
+
+// TokenExpiration returns the expiration time of the token which was used for
+// a request, as reported in the headers of the response. The zero time is
+// returned if the token does not expire.
+func TokenExpiration(header http.Header) (time.Time, error) {
+	value := header.Get(tokenExpirationHeader)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	// GitHub uses either a time zone abbreviation or a numeric offset
+	for _, layout := range []string{"2006-01-02 15:04:05 MST", "2006-01-02 15:04:05 -0700"} {
+		if expiration, err := time.Parse(layout, value); err == nil {
+			return expiration, nil
+		}
+	}
+	return time.Time{}, errors.Errorf("unable to parse token expiration %q", value)
+}
+
+// TokenScopes returns the scopes of the token which was used for a request, as
+// reported in the headers of the response. The boolean is false if the scopes
+// are unknown, like for fine-grained tokens.
+func TokenScopes(header http.Header) ([]string, bool) {
+	if _, ok := header[http.CanonicalHeaderKey(tokenScopesHeader)]; !ok {
+		return nil, false
+	}
+
+	scopes := []string{}
+	for _, scope := range strings.Split(header.Get(tokenScopesHeader), ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, true
+}
@@ -0,0 +1,46 @@
+package notes
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenExpiration(t *testing.T) {
+	header := http.Header{}
+	expiration, err := TokenExpiration(header)
+	require.Nil(t, err)
+	require.True(t, expiration.IsZero())
+
+	header.Set("github-authentication-token-expiration", "2021-12-31 23:59:59 UTC")
+	expiration, err = TokenExpiration(header)
+	require.Nil(t, err)
+	require.True(t, time.Date(2021, 12, 31, 23, 59, 59, 0, time.UTC).Equal(expiration))
+
+	header.Set("github-authentication-token-expiration", "2023-04-01 00:00:00 -0700")
+	expiration, err = TokenExpiration(header)
+	require.Nil(t, err)
+	require.True(t, time.Date(2023, 4, 1, 7, 0, 0, 0, time.UTC).Equal(expiration))
+
+	header.Set("github-authentication-token-expiration", "next week")
+	_, err = TokenExpiration(header)
+	require.NotNil(t, err)
+}
+
+func TestTokenScopes(t *testing.T) {
+	_, known := TokenScopes(http.Header{})
+	require.False(t, known)
+
+	header := http.Header{}
+	header.Set("X-OAuth-Scopes", "")
+	scopes, known := TokenScopes(header)
+	require.True(t, known)
+	require.Empty(t, scopes)
+
+	header.Set("X-OAuth-Scopes", "repo, read:org")
+	scopes, known = TokenScopes(header)
+	require.True(t, known)
+	require.Equal(t, []string{"repo", "read:org"}, scopes)
+}
@@ -0,0 +1,38 @@
+package notes
+
+import (
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+)
+
+const (
+	// TraceDecisions logs the decision taken for every commit, like why it
+	// was skipped or which note it resulted in
+	TraceDecisions = 1
+
+	// TraceChecks additionally logs the outcome of every check a commit goes
+	// through, like the matched author and the release note block of its PR
+	TraceChecks = 2
+)
+
+// WithVerbosity allows the caller to trace how every commit is turned into a
+// note, or why it is not, at the debug level. At TraceDecisions, a line is
+// logged per commit, and at TraceChecks a line per check of the commit. The
+// trace only holds SHAs, PR numbers, logins, labels and the names of the
+// checks, never the PR bodies, request details or credentials, so that it is
+// safe to share in a bug report.
+func WithVerbosity(v int) GithubApiOption {
+	return func(c *githubApiConfig) {
+		c.verbosity = v
+	}
+}
+
+// traceCommit logs a step of the processing of the commit with the given SHA
+// if the verbosity of the config is at least v
+func traceCommit(logger log.Logger, c *githubApiConfig, v int, sha, step string, keyvals ...interface{}) {
+	if c.verbosity < v {
+		return
+	}
+	keyvals = append([]interface{}{"msg", "commit trace", "v", v, "sha", sha, "step", step}, keyvals...)
+	level.Debug(logger).Log(keyvals...)
+}
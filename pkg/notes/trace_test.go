@@ -0,0 +1,42 @@
+package notes
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerbosity(t *testing.T) {
+	client, server := newFixtureClient(t, "require-merged", requireMergedFixtures)
+	defer server.Close()
+
+	trace := func(v int) string {
+		buf := &bytes.Buffer{}
+		_, err := ListReleaseNotes(client, log.NewLogfmtLogger(buf), "master", "start", "end", "", "", WithRequireMerged(), WithVerbosity(v))
+		require.Nil(t, err)
+		lines := []string{}
+		for _, line := range strings.Split(buf.String(), "\n") {
+			if strings.Contains(line, `msg="commit trace"`) {
+				lines = append(lines, line)
+			}
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	require.Empty(t, trace(0))
+
+	decisions := trace(TraceDecisions)
+	require.Contains(t, decisions, `sha=aaa step=included pr=2`)
+	require.Contains(t, decisions, `sha=bbb step="skipped: the PR is not merged"`)
+	require.NotContains(t, decisions, "v=2")
+
+	checks := trace(TraceChecks)
+	require.Contains(t, checks, `v=2 sha=aaa step="the PR has a release note block" pr=2`)
+	require.Contains(t, checks, `sha=aaa step=included pr=2`)
+	// the PR bodies are never traced
+	require.NotContains(t, checks, "release-note\\n")
+	require.NotContains(t, checks, "kubelet restart loop")
+}
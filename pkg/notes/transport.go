@@ -0,0 +1,305 @@
+package notes
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+)
+
+const (
+	// retryBaseDelay is the delay before the first retry of a failed request
+	retryBaseDelay = time.Second
+
+	// retryMaxDelay caps the exponential backoff between retries
+	retryMaxDelay = 30 * time.Second
+)
+
+// retryTransport is an http.RoundTripper which retries requests that failed
+// with a 5xx status code or hit the per-request timeout, using a jittered
+// exponential backoff between the attempts.
+type retryTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+	logger     log.Logger
+}
+
+// NewRetryTransport wraps the provided http.RoundTripper so that requests which
+// fail with a server error (5xx) or time out as configured by a transport
+// returned from NewTimeoutTransport are retried up to maxRetries times. Retries
+// respect the deadline of the request context, so they never outlive it. If
+// base is nil, http.DefaultTransport is used.
+func NewRetryTransport(base http.RoundTripper, maxRetries int, logger log.Logger) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &retryTransport{
+		base:       base,
+		maxRetries: maxRetries,
+		logger:     logger,
+	}
+}
+
+// RoundTrip implements the http.RoundTripper interface
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if _, timeout := err.(*requestTimeoutError); err != nil && !timeout {
+			return resp, err
+		}
+		if err == nil && resp.StatusCode < http.StatusInternalServerError {
+			return resp, err
+		}
+		if attempt >= t.maxRetries || (req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+
+		reason := ""
+		if err != nil {
+			reason = err.Error()
+		} else {
+			// the response is discarded, so make sure the connection can be reused
+			reason = resp.Status
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		wait := retryDelay(attempt)
+		level.Info(t.logger).Log(
+			"msg", "retrying failed GitHub request",
+			"reason", reason,
+			"url", req.URL.Path,
+			"attempt", attempt+1,
+			"wait", wait,
+		)
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+
+		if req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = cloneRequest(req)
+			req.Body = body
+		}
+	}
+}
+
+// retryDelay returns the jittered exponential backoff for the given attempt,
+// which is a random duration between half and the full exponential delay.
+func retryDelay(attempt int) time.Duration {
+	delay := retryMaxDelay
+	if attempt < 16 {
+		if d := retryBaseDelay << uint(attempt); d < retryMaxDelay {
+			delay = d
+		}
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// requestTimeoutError is returned by the timeoutTransport if a single request
+// exceeded its deadline
+type requestTimeoutError struct {
+	timeout time.Duration
+}
+
+func (e *requestTimeoutError) Error() string {
+	return fmt.Sprintf("request timed out after %s", e.timeout)
+}
+
+// timeoutTransport is an http.RoundTripper which applies a deadline to every
+// single request
+type timeoutTransport struct {
+	base    http.RoundTripper
+	timeout time.Duration
+}
+
+// NewTimeoutTransport wraps the provided http.RoundTripper so that every request
+// is aborted if it takes longer than timeout, including reading the response
+// body. This is independent of any deadline of the request context. If base
+// is nil, http.DefaultTransport is used.
+func NewTimeoutTransport(base http.RoundTripper, timeout time.Duration) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &timeoutTransport{
+		base:    base,
+		timeout: timeout,
+	}
+}
+
+// RoundTrip implements the http.RoundTripper interface
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		if ctx.Err() == context.DeadlineExceeded && req.Context().Err() == nil {
+			return nil, &requestTimeoutError{timeout: t.timeout}
+		}
+		return nil, err
+	}
+
+	// the deadline has to outlive RoundTrip, because the body is read later
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose cancels a context once the wrapped body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	defer c.cancel()
+	return c.ReadCloser.Close()
+}
+
+// concurrencyTransport is an http.RoundTripper which bounds the number of
+// requests in flight at the same time
+type concurrencyTransport struct {
+	base http.RoundTripper
+	sem  chan struct{}
+}
+
+// NewConcurrencyTransport wraps the provided http.RoundTripper so that at most
+// limit requests are in flight at the same time, which helps to stay below the
+// secondary rate limits of GitHub. A request is in flight until its response
+// body is closed. Requests waiting for a free slot respect their context. If
+// base is nil, http.DefaultTransport is used.
+func NewConcurrencyTransport(base http.RoundTripper, limit int) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &concurrencyTransport{
+		base: base,
+		sem:  make(chan struct{}, limit),
+	}
+}
+
+// RoundTrip implements the http.RoundTripper interface
+func (t *concurrencyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case t.sem <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+
+	release := &sync.Once{}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		release.Do(func() { <-t.sem })
+		return nil, err
+	}
+
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: func() {
+		release.Do(func() { <-t.sem })
+	}}
+	return resp, nil
+}
+
+// releaseOnClose calls release once the wrapped body is closed
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (r *releaseOnClose) Close() error {
+	defer r.release()
+	return r.ReadCloser.Close()
+}
+
+// acceptTransport is an http.RoundTripper which overrides the Accept header of
+// every request
+type acceptTransport struct {
+	base      http.RoundTripper
+	mediaType string
+}
+
+// NewAcceptTransport wraps the provided http.RoundTripper so that every request
+// accepts the provided media type, like the media types of the GitHub preview
+// APIs. If base is nil, http.DefaultTransport is used.
+func NewAcceptTransport(base http.RoundTripper, mediaType string) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &acceptTransport{
+		base:      base,
+		mediaType: mediaType,
+	}
+}
+
+// cloneRequest returns a shallow copy of the request with its own headers,
+// like http.Request.Clone, which requires Go 1.13
+func cloneRequest(req *http.Request) *http.Request {
+	clone := new(http.Request)
+	*clone = *req
+	clone.Header = make(http.Header, len(req.Header))
+	for key, values := range req.Header {
+		clone.Header[key] = append([]string(nil), values...)
+	}
+	return clone
+}
+
+// RoundTrip implements the http.RoundTripper interface
+func (t *acceptTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// a RoundTripper must not modify the request it was passed
+	req = cloneRequest(req)
+	req.Header.Set("Accept", t.mediaType)
+	return t.base.RoundTrip(req)
+}
+
+// DefaultAllowedHosts are the hosts of github.com which the GitHub token may be
+// sent to
+var DefaultAllowedHosts = []string{"github.com", "api.github.com", "uploads.github.com"}
+
+type hostAllowlistTransport struct {
+	base  http.RoundTripper
+	hosts map[string]struct{}
+}
+
+// NewHostAllowlistTransport wraps the provided http.RoundTripper so that it
+// refuses every request to a host which is not one of the provided hostnames.
+// Wrapped by the transport which adds the token, it prevents the token from
+// being sent to any other host, like after a redirect or with a misconfigured
+// API URL. If base is nil, http.DefaultTransport is used.
+func NewHostAllowlistTransport(base http.RoundTripper, hosts []string) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	allowed := map[string]struct{}{}
+	for _, host := range hosts {
+		allowed[strings.ToLower(host)] = struct{}{}
+	}
+	return &hostAllowlistTransport{
+		base:  base,
+		hosts: allowed,
+	}
+}
+
+// RoundTrip implements the http.RoundTripper interface
+func (t *hostAllowlistTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if _, ok := t.hosts[strings.ToLower(req.URL.Hostname())]; !ok {
+		// a RoundTripper must close the body of the request, even on errors
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, errors.Errorf("refusing to send the GitHub token to %s, which is not an allowed host", req.URL.Host)
+	}
+	return t.base.RoundTrip(req)
+}
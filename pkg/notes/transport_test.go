@@ -0,0 +1,180 @@
+package notes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryTransport(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewRetryTransport(nil, 3, log.NewNopLogger())}
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, 2, calls)
+}
+
+func TestRetryTransportMaxRetries(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewRetryTransport(nil, 0, log.NewNopLogger())}
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	require.Equal(t, 1, calls)
+}
+
+func TestRetryTransportRespectsContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	client := &http.Client{Transport: NewRetryTransport(nil, 10, log.NewNopLogger())}
+	_, err = client.Do(req.WithContext(ctx))
+	require.Error(t, err)
+}
+
+func TestTimeoutTransportIsRetried(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	transport := NewRetryTransport(NewTimeoutTransport(nil, 50*time.Millisecond), 1, log.NewNopLogger())
+	client := &http.Client{Transport: transport}
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, 2, calls)
+}
+
+func TestTimeoutTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewTimeoutTransport(nil, 50*time.Millisecond)}
+	_, err := client.Get(server.URL)
+	require.Error(t, err)
+}
+
+func TestConcurrencyTransport(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewConcurrencyTransport(nil, 2)}
+	errs := make(chan error, 8)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(server.URL)
+			if err == nil {
+				resp.Body.Close()
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	require.True(t, atomic.LoadInt32(&maxInFlight) <= 2)
+}
+
+func TestAcceptTransport(t *testing.T) {
+	accepted := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accepted <- r.Header.Get("Accept")
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewAcceptTransport(nil, "application/vnd.github.symmetra-preview+json")}
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.Nil(t, err)
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := client.Do(req)
+	require.Nil(t, err)
+	resp.Body.Close()
+
+	require.Equal(t, "application/vnd.github.symmetra-preview+json", <-accepted)
+	require.Equal(t, "application/vnd.github.v3+json", req.Header.Get("Accept"))
+}
+
+func TestHostAllowlistTransport(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	require.Nil(t, err)
+
+	client := &http.Client{Transport: NewHostAllowlistTransport(nil, []string{strings.ToUpper(serverURL.Hostname())})}
+	resp, err := client.Get(server.URL)
+	require.Nil(t, err)
+	resp.Body.Close()
+	require.Equal(t, 1, requests)
+
+	client = &http.Client{Transport: NewHostAllowlistTransport(nil, DefaultAllowedHosts)}
+	_, err = client.Get(server.URL)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "refusing to send the GitHub token to "+serverURL.Host)
+	require.Equal(t, 1, requests)
+}
@@ -0,0 +1,155 @@
+package notes
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+)
+
+// WarningCategory classifies the warnings collected by Warnings
+type WarningCategory string
+
+const (
+	// WarningMalformedNote is a commit whose note cannot be read, like a PR
+	// without a release note block
+	WarningMalformedNote WarningCategory = "malformed-note"
+
+	// WarningSkipped is a commit which is skipped, like the commit of a PR
+	// which is not merged
+	WarningSkipped WarningCategory = "skipped"
+
+	// WarningFetchFailed is a PR whose additional data, like its files or
+	// reactions, cannot be fetched
+	WarningFetchFailed WarningCategory = "fetch-failed"
+
+	// WarningBudgetExhausted is the first PR whose additional data is not
+	// fetched because a budget is exhausted
+	WarningBudgetExhausted WarningCategory = "budget-exhausted"
+
+	// WarningUnlabeled is a note without a SIG or kind label, or without one
+	// of the labels required by the caller
+	WarningUnlabeled WarningCategory = "unlabeled"
+
+	// WarningStyle is a note which violates the style of WithEnforceStyle
+	WarningStyle WarningCategory = "style"
+
+	// WarningTooLong is a note which exceeds the maximum length
+	WarningTooLong WarningCategory = "too-long"
+)
+
+// Warning is a problem with a commit or note which does not stop the notes
+// from being listed or rendered, but which its author may want to fix
+type Warning struct {
+	// PrNumber is the number of the PR, if known
+	PrNumber int `json:"pr_number,omitempty"`
+
+	// Commit is the SHA of the commit, if the warning is about a commit
+	Commit string `json:"commit,omitempty"`
+
+	Category WarningCategory `json:"category"`
+	Message  string          `json:"message"`
+}
+
+// Warnings collects the warnings of ListReleaseNotes and the renderers. It
+// is safe for concurrent use, and a nil *Warnings drops all warnings.
+type Warnings struct {
+	mu       sync.Mutex
+	warnings []Warning
+}
+
+// Add records the warning, unless an identical one is already recorded, like
+// for a note which is rendered in several sections
+func (w *Warnings) Add(warning Warning) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, existing := range w.warnings {
+		if existing == warning {
+			return
+		}
+	}
+	w.warnings = append(w.warnings, warning)
+}
+
+// List returns the recorded warnings ordered by their PR number, commit and
+// category. The warnings of the same PR and category keep their order.
+func (w *Warnings) List() []Warning {
+	if w == nil {
+		return []Warning{}
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	list := append([]Warning{}, w.warnings...)
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].PrNumber != list[j].PrNumber {
+			return list[i].PrNumber < list[j].PrNumber
+		}
+		if list[i].Commit != list[j].Commit {
+			return list[i].Commit < list[j].Commit
+		}
+		return list[i].Category < list[j].Category
+	})
+	return list
+}
+
+// WithWarnings allows the caller to collect the warnings of ListReleaseNotes,
+// which are logged in addition
+func WithWarnings(w *Warnings) GithubApiOption {
+	return func(c *githubApiConfig) {
+		c.warnings = w
+	}
+}
+
+// WithRenderWarnings allows the caller to collect the warnings about the
+// rendered notes, like the notes without a SIG or kind label
+func WithRenderWarnings(w *Warnings) DocumentOption {
+	return func(c *documentConfig) {
+		c.warnings = w
+	}
+}
+
+// addRenderWarnings records the warnings about a rendered note
+func addRenderWarnings(note *ReleaseNote, c *documentConfig) {
+	if c.warnings == nil {
+		return
+	}
+	if len(note.SIGs) == 0 && len(note.Kinds) == 0 && !note.Dependency {
+		title := c.otherTitle
+		if title == "" {
+			title = sectionTitles[sectionUncategorized]
+		}
+		c.warnings.Add(Warning{
+			PrNumber: note.PrNumber,
+			Category: WarningUnlabeled,
+			Message:  "the note has no SIG or kind label and is listed under " + title,
+		})
+	}
+	if c.enforceStyle {
+		if violations := StyleViolations(note.Text); len(violations) > 0 {
+			c.warnings.Add(Warning{
+				PrNumber: note.PrNumber,
+				Category: WarningStyle,
+				Message:  "the note is restyled because it violates the style: " + strings.Join(violations, ", "),
+			})
+		}
+	}
+}
+
+// warnPR logs a warning about a PR and records it in the warnings of the
+// config, if any. The error, if any, is part of the message.
+func warnPR(logger log.Logger, c *githubApiConfig, category WarningCategory, number int, msg string, err error) {
+	message := msg
+	keyvals := []interface{}{"msg", msg, "pr", number}
+	if err != nil {
+		message = msg + ": " + err.Error()
+		keyvals = append(keyvals, "err", err)
+	}
+	level.Warn(logger).Log(keyvals...)
+	c.warnings.Add(Warning{PrNumber: number, Category: category, Message: message})
+}
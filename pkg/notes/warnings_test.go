@@ -0,0 +1,58 @@
+package notes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarnings(t *testing.T) {
+	var none *Warnings
+	none.Add(Warning{PrNumber: 1, Category: WarningStyle, Message: "dropped"})
+	require.Empty(t, none.List())
+
+	warnings := &Warnings{}
+	warnings.Add(Warning{PrNumber: 2, Category: WarningUnlabeled, Message: "b"})
+	warnings.Add(Warning{PrNumber: 1, Category: WarningStyle, Message: "a"})
+	warnings.Add(Warning{PrNumber: 2, Category: WarningUnlabeled, Message: "b"})
+	warnings.Add(Warning{Commit: "aaa", Category: WarningSkipped, Message: "c"})
+	require.Equal(t, []Warning{
+		{Commit: "aaa", Category: WarningSkipped, Message: "c"},
+		{PrNumber: 1, Category: WarningStyle, Message: "a"},
+		{PrNumber: 2, Category: WarningUnlabeled, Message: "b"},
+	}, warnings.List())
+}
+
+func TestListReleaseNotesWarnings(t *testing.T) {
+	client, server := newFixtureClient(t, "require-merged", requireMergedFixtures)
+	defer server.Close()
+
+	warnings := &Warnings{}
+	releaseNotes, err := ListReleaseNotes(client, log.NewNopLogger(), "master", "start", "end", "", "", WithRequireMerged(), WithReactions(0), WithWarnings(warnings))
+	require.Nil(t, err)
+	require.Len(t, releaseNotes, 1)
+	require.Equal(t, []Warning{
+		{Commit: "bbb", Category: WarningSkipped, Message: "the PR of the commit is not merged"},
+		{PrNumber: 2, Category: WarningFetchFailed, Message: warnings.List()[1].Message},
+	}, warnings.List())
+	require.True(t, strings.HasPrefix(warnings.List()[1].Message, "error fetching the reactions of the PR: "))
+
+	// the PR has no labels
+	rendered := &Warnings{}
+	_, err = RenderToBytes(releaseNotes, "markdown", WithRenderWarnings(rendered), WithOtherSectionTitle("Other"))
+	require.Nil(t, err)
+	require.Equal(t, []Warning{
+		{PrNumber: 2, Category: WarningUnlabeled, Message: "the note has no SIG or kind label and is listed under Other"},
+	}, rendered.List())
+
+	releaseNotes[2].Kinds = []string{"bug"}
+	releaseNotes[2].Text = "fixed the kubelet restart loop"
+	rendered = &Warnings{}
+	_, err = RenderToBytes(releaseNotes, "html", WithRenderWarnings(rendered), WithEnforceStyle())
+	require.Nil(t, err)
+	require.Equal(t, []Warning{
+		{PrNumber: 2, Category: WarningStyle, Message: "the note is restyled because it violates the style: does not start with a capital letter, does not end with a period"},
+	}, rendered.List())
+}